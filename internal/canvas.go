@@ -1,6 +1,11 @@
 package internal
 
-import "math"
+import (
+	"cmp"
+	"math"
+	"regexp"
+	"slices"
+)
 
 // NEW: Pixel art data structures
 type GridPosition struct {
@@ -30,10 +35,17 @@ type PixelMessage struct {
 	X         *int             `json:"x,omitempty"`
 	Y         *int             `json:"y,omitempty"`
 	Color     string           `json:"color,omitempty"`
+	BrushSize int              `json:"brush_size,omitempty"` // cells across; 0 means 1
 	Timestamp int64            `json:"timestamp"`
 	Pixels    []GridPosition   `json:"pixels,omitempty"` // Batch operations
 }
 
+// Bounds for PixelMessage.BrushSize
+const (
+	MinBrushSize = 1
+	MaxBrushSize = 8
+)
+
 type PixelMessageType string
 
 const (
@@ -41,14 +53,151 @@ const (
 	PixelPlace PixelMessageType = "place"
 	ErasePixel PixelMessageType = "erase"
 	BatchErase PixelMessageType = "batch_erase"
+	// FillPixel floods the region around X/Y; it is stored and broadcast as
+	// the batch_place of the cells it covers
+	FillPixel PixelMessageType = "fill"
 )
 
+// CanvasBackground is canvas metadata (not pixels) so every client renders the same backdrop
+type CanvasBackground struct {
+	Color string `json:"color"` // #rrggbb
+	Grid  bool   `json:"grid"`
+}
+
+// DefaultCanvasBackground is a plain white canvas without a grid
+var DefaultCanvasBackground = CanvasBackground{Color: "#ffffff"}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// IsValid reports whether the background color is a #rrggbb hex value
+func (b CanvasBackground) IsValid() bool {
+	return IsHexColor(b.Color)
+}
+
+// IsHexColor reports whether s is a #rrggbb hex color
+func IsHexColor(s string) bool {
+	return hexColorPattern.MatchString(s)
+}
+
+// FloodFill returns the cells a fill with color at start would paint: the
+// 4-connected region of cells sharing start's current color (blank counts as
+// a color) on the canvas ops render to. It visits at most
+// CanvasWidth*CanvasHeight cells, and returns nil if start is off the grid or
+// already that color.
+func FloodFill(ops []PixelMessage, start GridPosition, color string) []GridPosition {
+	if start.GridX < 0 || start.GridX >= CanvasWidth || start.GridY < 0 || start.GridY >= CanvasHeight {
+		return nil
+	}
+	cells := RenderCanvas(ops)
+	target := cells[start]
+	if target == color {
+		return nil
+	}
+
+	limit := CanvasWidth * CanvasHeight
+	seen := map[GridPosition]bool{start: true}
+	queue := []GridPosition{start}
+	filled := make([]GridPosition, 0, limit)
+	for len(queue) > 0 && len(filled) < limit {
+		cell := queue[0]
+		queue = queue[1:]
+		filled = append(filled, cell)
+		for _, next := range []GridPosition{
+			{GridX: cell.GridX + 1, GridY: cell.GridY},
+			{GridX: cell.GridX - 1, GridY: cell.GridY},
+			{GridX: cell.GridX, GridY: cell.GridY + 1},
+			{GridX: cell.GridX, GridY: cell.GridY - 1},
+		} {
+			if next.GridX < 0 || next.GridX >= CanvasWidth || next.GridY < 0 || next.GridY >= CanvasHeight ||
+				seen[next] || cells[next] != target {
+				continue
+			}
+			seen[next] = true
+			queue = append(queue, next)
+		}
+	}
+	return filled
+}
+
+// RenderCanvas replays canvas operations into the color of each painted cell.
+// Later placements overwrite earlier ones; erase ops clear their cells.
+func RenderCanvas(ops []PixelMessage) map[GridPosition]string {
+	cells := make(map[GridPosition]string)
+	for _, op := range ops {
+		switch op.Type {
+		case PixelPlace:
+			if op.X != nil && op.Y != nil {
+				cells[GridPosition{GridX: *op.X, GridY: *op.Y}] = op.Color
+			}
+		case ErasePixel:
+			if op.X != nil && op.Y != nil {
+				delete(cells, GridPosition{GridX: *op.X, GridY: *op.Y})
+			}
+		case BatchPlace:
+			for _, p := range op.Pixels {
+				cells[p] = op.Color
+			}
+		case BatchErase:
+			for _, p := range op.Pixels {
+				delete(cells, p)
+			}
+		}
+	}
+	return cells
+}
+
+// CompactCanvas collapses ops into one batch_place per color that renders
+// identically. Batches are ordered by color and pixels by position so the
+// result is deterministic.
+func CompactCanvas(ops []PixelMessage) []PixelMessage {
+	var lastTimestamp int64
+	for _, op := range ops {
+		lastTimestamp = max(lastTimestamp, op.Timestamp)
+	}
+
+	byColor := make(map[string][]GridPosition)
+	for pos, color := range RenderCanvas(ops) {
+		byColor[color] = append(byColor[color], pos)
+	}
+
+	colors := make([]string, 0, len(byColor))
+	for color := range byColor {
+		colors = append(colors, color)
+	}
+	slices.Sort(colors)
+
+	compacted := make([]PixelMessage, 0, len(colors))
+	for _, color := range colors {
+		pixels := byColor[color]
+		slices.SortFunc(pixels, func(a, b GridPosition) int {
+			return cmp.Or(cmp.Compare(a.GridY, b.GridY), cmp.Compare(a.GridX, b.GridX))
+		})
+		compacted = append(compacted, PixelMessage{
+			Type:      BatchPlace,
+			Color:     color,
+			Timestamp: lastTimestamp,
+			Pixels:    pixels,
+		})
+	}
+	return compacted
+}
+
 const (
 	CanvasWidth  = 35
 	CanvasHeight = 20
 )
 
+// NormalizeCoordinates maps a point on a client's canvas onto the server grid,
+// clamped to its bounds. A non-positive client dimension is taken to match the
+// grid, so it can never divide by zero.
 func NormalizeCoordinates(x int, y int, clientCanvasWidth int, clientCanvasHeight int) (gridX int, gridY int) {
+	if clientCanvasWidth <= 0 {
+		clientCanvasWidth = CanvasWidth
+	}
+	if clientCanvasHeight <= 0 {
+		clientCanvasHeight = CanvasHeight
+	}
+
 	// - Assume client sends coordinates scaled to their canvas size
 	// - Convert to server grid
 	gridX = int(math.Floor(float64(x) * float64(CanvasWidth) / float64(clientCanvasWidth)))
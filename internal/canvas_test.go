@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"maps"
+	"testing"
+)
+
+func intPtr(v int) *int { return &v }
+
+func TestCompactCanvasRendersIdentically(t *testing.T) {
+	ops := []PixelMessage{
+		{Type: PixelPlace, X: intPtr(1), Y: intPtr(1), Color: "#000000", Timestamp: 1},
+		{Type: PixelPlace, X: intPtr(2), Y: intPtr(1), Color: "#000000", Timestamp: 2},
+		{Type: BatchPlace, Color: "#ff0000", Timestamp: 3,
+			Pixels: []GridPosition{{GridX: 2, GridY: 1}, {GridX: 3, GridY: 3}, {GridX: 4, GridY: 4}}},
+		{Type: PixelPlace, X: intPtr(4), Y: intPtr(4), Color: "#000000", Timestamp: 4},
+		{Type: BatchErase, Timestamp: 5, Pixels: []GridPosition{{GridX: 3, GridY: 3}}},
+	}
+
+	compacted := CompactCanvas(ops)
+
+	if !maps.Equal(RenderCanvas(ops), RenderCanvas(compacted)) {
+		t.Errorf("compacted canvas renders differently:\n got %v\nwant %v", RenderCanvas(compacted), RenderCanvas(ops))
+	}
+	if len(compacted) != 2 {
+		t.Errorf("expected one batch per color, got %d ops", len(compacted))
+	}
+	for _, op := range compacted {
+		if op.Type != BatchPlace || op.Timestamp != 5 {
+			t.Errorf("expected batch_place ops stamped with the last timestamp, got %+v", op)
+		}
+	}
+}
+
+func TestNormalizeCoordinatesZeroClientSize(t *testing.T) {
+	cases := []struct {
+		x, y, w, h   int
+		wantX, wantY int
+	}{
+		{5, 5, 0, 0, 5, 5},
+		{100, 100, 0, 0, CanvasWidth - 1, CanvasHeight - 1},
+		{-3, 7, -10, 200, 0, 0},
+		{175, 10, 350, 0, 17, 10},
+		{175, 100, 350, 200, 17, 10}, // normal scaling
+		{349, 199, 350, 200, CanvasWidth - 1, CanvasHeight - 1},
+	}
+	for _, c := range cases {
+		gx, gy := NormalizeCoordinates(c.x, c.y, c.w, c.h)
+		if gx != c.wantX || gy != c.wantY {
+			t.Errorf("NormalizeCoordinates(%d, %d, %d, %d) = (%d, %d), want (%d, %d)",
+				c.x, c.y, c.w, c.h, gx, gy, c.wantX, c.wantY)
+		}
+		if gx < 0 || gx >= CanvasWidth || gy < 0 || gy >= CanvasHeight {
+			t.Errorf("NormalizeCoordinates(%d, %d, %d, %d) left the grid: (%d, %d)", c.x, c.y, c.w, c.h, gx, gy)
+		}
+	}
+}
+
+func TestFloodFillEmptyCanvas(t *testing.T) {
+	filled := FloodFill(nil, GridPosition{GridX: 3, GridY: 4}, "#ff0000")
+	if len(filled) != CanvasWidth*CanvasHeight {
+		t.Fatalf("expected the whole %dx%d canvas filled, got %d cells", CanvasWidth, CanvasHeight, len(filled))
+	}
+	if again := FloodFill([]PixelMessage{{Type: BatchPlace, Color: "#ff0000", Pixels: filled}},
+		GridPosition{GridX: 0, GridY: 0}, "#ff0000"); again != nil {
+		t.Errorf("expected refilling with the same color to do nothing, got %d cells", len(again))
+	}
+	if off := FloodFill(nil, GridPosition{GridX: CanvasWidth, GridY: 0}, "#ff0000"); off != nil {
+		t.Errorf("expected a fill off the grid to do nothing, got %d cells", len(off))
+	}
+}
+
+func TestFloodFillStaysInsideBorder(t *testing.T) {
+	// A black square outline from (2,2) to (6,6) around a 3x3 interior
+	var border []GridPosition
+	for i := 2; i <= 6; i++ {
+		border = append(border,
+			GridPosition{GridX: i, GridY: 2}, GridPosition{GridX: i, GridY: 6},
+			GridPosition{GridX: 2, GridY: i}, GridPosition{GridX: 6, GridY: i})
+	}
+	ops := []PixelMessage{{Type: BatchPlace, Color: "#000000", Pixels: border}}
+
+	inside := FloodFill(ops, GridPosition{GridX: 4, GridY: 4}, "#00ff00")
+	if len(inside) != 9 {
+		t.Fatalf("expected the 3x3 interior filled, got %d cells: %v", len(inside), inside)
+	}
+	for _, p := range inside {
+		if p.GridX < 3 || p.GridX > 5 || p.GridY < 3 || p.GridY > 5 {
+			t.Errorf("fill leaked outside the border at %+v", p)
+		}
+	}
+
+	outside := FloodFill(ops, GridPosition{GridX: 0, GridY: 0}, "#00ff00")
+	if want := CanvasWidth*CanvasHeight - 16 - 9; len(outside) != want {
+		t.Errorf("expected %d cells outside the square, got %d", want, len(outside))
+	}
+}
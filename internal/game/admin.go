@@ -0,0 +1,73 @@
+package game
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"log"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scythe504/skribblr-backend/internal"
+	"github.com/scythe504/skribblr-backend/internal/utils"
+)
+
+// =============================================================================
+// ADMIN
+// =============================================================================
+
+// IsAdminToken reports whether token matches the configured AdminToken.
+// Always false while AdminToken is unset.
+func IsAdminToken(token string) bool {
+	adminToken := AdminToken
+	if adminToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) == 1
+}
+
+// ForceWord sets the word for the current drawer's turn and starts drawing
+// straight away, skipping word selection. Meant for reproducing scoring
+// scenarios and recovering stuck rooms; token must match AdminToken.
+func ForceWord(roomId, word, token string) error {
+	if !IsAdminToken(token) {
+		log.Printf("[ForceWord] room=%s: rejected, bad admin token", roomId)
+		return fmt.Errorf("unauthorized")
+	}
+	word = strings.TrimSpace(word)
+	if word == "" {
+		return fmt.Errorf("word must not be empty")
+	}
+
+	RoomsMu.RLock()
+	room := Rooms[roomId]
+	RoomsMu.RUnlock()
+	if room == nil {
+		return fmt.Errorf("room %s not found", roomId)
+	}
+
+	// 1. Only a turn that has a drawer but hasn't started drawing can take a word
+	room.Mu.Lock()
+	if !room.HasGameStarted || room.Current == nil {
+		room.Mu.Unlock()
+		return fmt.Errorf("room %s has no drawer", roomId)
+	}
+	if room.DrawingStarted {
+		room.Mu.Unlock()
+		return fmt.Errorf("room %s is already drawing this turn", roomId)
+	}
+
+	// 2. Set the word as HandleWordSelection would
+	room.Word = word
+	room.WordEmoji = ""
+	room.WordDifficulty = utils.WordDifficultyByLength(utf8.RuneCountInString(word))
+	room.WordChoices = make([]internal.Word, 0)
+	drawerID := room.Current.Id
+	room.Mu.Unlock()
+
+	log.Printf("[ForceWord] room=%s: admin set word %q for drawer %s", roomId, word, drawerID)
+
+	// 3. Replace the waiting/selection timer with the drawing phase
+	CancelPhaseTimer(room)
+	StartDrawingPhase(room)
+	return nil
+}
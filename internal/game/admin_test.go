@@ -0,0 +1,64 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func withAdminToken(t *testing.T, token string) {
+	t.Helper()
+	prev := AdminToken
+	AdminToken = token
+	t.Cleanup(func() { AdminToken = prev })
+}
+
+func TestForceWordDrivesScoredRound(t *testing.T) {
+	withAdminToken(t, "secret")
+	players, _ := joinMidGame(t, "force-word", "drawer", "guesser", "other")
+	drawer, guesser := players[0], players[1]
+	room := drawer.Room
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+	room.Mu.Lock()
+	room.PlayerOrder = []string{"drawer", "guesser", "other"}
+	room.Current = drawer
+	room.Phase = internal.PhaseWaiting
+	room.Mu.Unlock()
+
+	if err := ForceWord("force-word", "lighthouse", "wrong"); err == nil {
+		t.Fatal("expected a bad admin token to be rejected")
+	}
+	if err := ForceWord("force-word", "lighthouse", "secret"); err != nil {
+		t.Fatalf("ForceWord failed: %v", err)
+	}
+
+	room.Mu.RLock()
+	phase, word := room.Phase, room.Word
+	room.Mu.RUnlock()
+	if phase != internal.PhaseDrawing || word != "lighthouse" {
+		t.Fatalf("expected drawing with the forced word, got phase=%s word=%q", phase, word)
+	}
+	if err := ForceWord("force-word", "anchor", "secret"); err == nil {
+		t.Error("expected forcing a word mid-drawing to fail")
+	}
+
+	HandleGuessEnhanced(guesser, "Lighthouse")
+
+	room.Mu.RLock()
+	guesserScore, drawerScore := guesser.Score, drawer.Score
+	room.Mu.RUnlock()
+	// A hard (10-letter) word guessed first within seconds
+	if guesserScore != 300 {
+		t.Errorf("expected the first fast guess of a hard word to score 300, got %d", guesserScore)
+	}
+	if drawerScore != 0 {
+		t.Errorf("expected the drawer to be scored when the turn ends, got %d mid-turn", drawerScore)
+	}
+}
+
+func TestForceWordDisabledWithoutToken(t *testing.T) {
+	withAdminToken(t, "")
+	if IsAdminToken("") || IsAdminToken("anything") {
+		t.Error("expected admin calls to be disabled when no token is configured")
+	}
+}
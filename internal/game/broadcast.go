@@ -0,0 +1,112 @@
+package game
+
+import (
+	"context"
+	"log"
+
+	"github.com/scythe504/skribblr-backend/internal"
+	"github.com/scythe504/skribblr-backend/internal/utils"
+)
+
+// =============================================================================
+// BROADCAST QUEUE
+// =============================================================================
+
+// queueBroadcast sends msg to room's connected players, except exclude if
+// non-nil, from the room's broadcast worker. Broadcasts queued for a room go
+// out in the order they were queued, and each room spends one goroutine on
+// them however fast they arrive. Recipients are the players connected now.
+// Caller must not hold room.Mu.
+func queueBroadcast[T any](room *internal.Room, msg internal.Message[T], exclude *internal.Player) {
+	room.Mu.RLock()
+	recipients := broadcastRecipients(room, exclude)
+	room.Mu.RUnlock()
+	enqueueBroadcast(room, recipients, msg)
+}
+
+// queueBroadcastLocked is queueBroadcast for callers holding room.Mu. It never
+// blocks, so holding the lock across it is fine.
+func queueBroadcastLocked[T any](room *internal.Room, msg internal.Message[T], exclude *internal.Player) {
+	enqueueBroadcast(room, broadcastRecipients(room, exclude), msg)
+}
+
+// queueSend sends msg to player alone through room's broadcast queue, for a
+// private message that must keep its order with the room's broadcasts. Like
+// queueBroadcastLocked it never blocks, so room.Mu may be held or not.
+func queueSend[T any](room *internal.Room, player *internal.Player, msg internal.Message[T]) {
+	enqueueBroadcast(room, []*internal.Player{player}, msg)
+}
+
+// broadcastRecipients lists room's connected players other than exclude.
+// Caller must hold room.Mu.
+func broadcastRecipients(room *internal.Room, exclude *internal.Player) []*internal.Player {
+	players := make([]*internal.Player, 0, len(room.Players))
+	for _, p := range room.Players {
+		if p.IsConnected && (exclude == nil || p.Id != exclude.Id) {
+			players = append(players, p)
+		}
+	}
+	return players
+}
+
+// enqueueBroadcast hands the send to room's worker, starting it on first use.
+// It never blocks: the send is dropped if the room is closed or the queue is
+// full. The queue only fills if sends stall, which WriteTimeout bounds, so a
+// drop means the room is badly behind. The worker never takes room.Mu.
+func enqueueBroadcast[T any](room *internal.Room, recipients []*internal.Player, msg internal.Message[T]) {
+	queue, created := room.BroadcastQueue(BroadcastQueueSize)
+	ctx := room.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if created {
+		go runBroadcastWorker(ctx, room.Id, queue)
+	}
+
+	send := func() {
+		sent := 0
+		for _, p := range recipients {
+			if err := p.SafeWriteJSON(msg); err != nil {
+				log.Printf("[BroadcastQueue][Room:%s] Failed for player %s (%s): %v",
+					room.Id, p.Id, p.Username, err)
+				continue
+			}
+			sent++
+		}
+		log.Printf("[BroadcastQueue][Room:%s] Sent %s to %d/%d players",
+			room.Id, msg.Type, sent, len(recipients))
+		utils.LogGameEvent(room, msg.Type, msg.Data)
+	}
+
+	if ctx.Err() != nil {
+		log.Printf("[BroadcastQueue][Room:%s] Room closed, dropping %s", room.Id, msg.Type)
+		return
+	}
+	select {
+	case queue <- send:
+	default:
+		log.Printf("[BroadcastQueue][Room:%s] Queue full (%d), dropping %s", room.Id, cap(queue), msg.Type)
+	}
+}
+
+// runBroadcastWorker sends a room's queued broadcasts one at a time until the
+// room's context ends, then sends whatever was queued before that (e.g. a
+// final game_ended) and stops
+func runBroadcastWorker(ctx context.Context, roomID string, queue <-chan func()) {
+	for {
+		select {
+		case send := <-queue:
+			send()
+		case <-ctx.Done():
+			for {
+				select {
+				case send := <-queue:
+					send()
+				default:
+					log.Printf("[BroadcastQueue][Room:%s] Room closed, stopping broadcast worker", roomID)
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func drawBurst(drawer *internal.Player, n int) {
+	for i := 1; i <= n; i++ {
+		raw := json.RawMessage(fmt.Sprintf(`{"type":"place","x":%d,"y":%d,"color":"#000000","timestamp":%d}`,
+			i%internal.CanvasWidth, i%internal.CanvasHeight, i))
+		HandlePixelDrawEnhanced(drawer, raw)
+	}
+}
+
+// flushBroadcasts queues a marker to room and waits for conn to get it, so
+// everything queued before has been delivered
+func flushBroadcasts(t *testing.T, room *internal.Room, conn *fakeConn) {
+	t.Helper()
+	n := len(conn.messagesOfType("flush")) + 1
+	queueBroadcast(room, internal.Message[any]{Type: "flush"}, nil)
+	waitForCount(t, conn, "flush", n)
+}
+
+// stallConn is a fakeConn whose writes hang for a while, then fail the way
+// a write past its deadline does
+type stallConn struct {
+	*fakeConn
+	closed atomic.Bool
+}
+
+func (c *stallConn) WriteJSON(any) error {
+	time.Sleep(50 * time.Millisecond)
+	return os.ErrDeadlineExceeded
+}
+
+func (c *stallConn) Close() error {
+	c.closed.Store(true)
+	return c.fakeConn.Close()
+}
+
+func TestStalledClientNeverBlocksRoomLock(t *testing.T) {
+	room := newTestRoom(t, "broadcast-stall")
+	_, conn := addConnectedPlayer(room, "alice")
+	stalled := addTestPlayer(room, "bob")
+	stall := &stallConn{fakeConn: newFakeConn()}
+	room.Mu.Lock()
+	stalled.Conn = stall
+	stalled.IsConnected = true
+	room.Mu.Unlock()
+
+	// Far more broadcasts than the queue holds, all under the room lock
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		room.Mu.Lock()
+		defer room.Mu.Unlock()
+		for range 2 * BroadcastQueueSize {
+			queueBroadcastLocked(room, internal.Message[any]{Type: "ping"}, nil)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected queueing under room.Mu never to block on a stalled client")
+	}
+
+	// The stalled write times out and closes that client's connection
+	deadline := time.Now().Add(2 * time.Second)
+	for !stall.closed.Load() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the stalled client's connection to be closed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	// The healthy client got what fit in the queue, in one piece
+	waitForCount(t, conn, "ping", 1)
+}
+
+func TestCanvasHandlersDontWaitOnStalledClient(t *testing.T) {
+	room := newTestRoom(t, "broadcast-stall-canvas")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	stalled := addTestPlayer(room, "bob")
+	room.Mu.Lock()
+	stalled.Conn = &stallConn{fakeConn: newFakeConn()}
+	stalled.IsConnected = true
+	room.Mu.Unlock()
+	makeDrawer(room, drawer)
+
+	// clear, undo and background all broadcast while holding room.Mu
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for range BroadcastQueueSize {
+			ClearCanvas(room, drawer)
+			HandleUndo(drawer)
+			HandleBackgroundChange(drawer, json.RawMessage(`{"color":"#112233"}`))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected canvas handlers never to wait on a stalled client")
+	}
+}
+
+// waitForCount polls conn until it has n messages of msgType
+func waitForCount(t *testing.T, conn *fakeConn, msgType string, n int) []internal.Message[json.RawMessage] {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		msgs := conn.messagesOfType(msgType)
+		if len(msgs) >= n {
+			return msgs
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d %s messages, got %d", n, msgType, len(msgs))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestQueuedBroadcastsKeepDrawOrder(t *testing.T) {
+	withPixelBroadcastWindow(t, 0)
+	room := newTestRoom(t, "broadcast-order")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	const ops = 300
+	drawBurst(drawer, ops)
+
+	for i, msg := range waitForCount(t, guesserConn, "place", ops) {
+		var pixel internal.PixelMessage
+		if err := json.Unmarshal(msg.Data, &pixel); err != nil {
+			t.Fatalf("bad pixel payload: %v", err)
+		}
+		if pixel.Timestamp != int64(i+1) {
+			t.Fatalf("expected op %d in position %d, got timestamp %d", i+1, i, pixel.Timestamp)
+		}
+	}
+}
+
+func TestBroadcastBurstKeepsGoroutinesBounded(t *testing.T) {
+	withPixelBroadcastWindow(t, 0)
+
+	room := newTestRoom(t, "broadcast-burst")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	before := runtime.NumGoroutine()
+	var peak atomic.Int64
+	stop := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for {
+			if n := int64(runtime.NumGoroutine()); n > peak.Load() {
+				peak.Store(n)
+			}
+			select {
+			case <-stop:
+				return
+			default:
+				runtime.Gosched()
+			}
+		}
+	}()
+
+	const ops = 500
+	drawBurst(drawer, ops)
+	close(stop)
+	<-sampled
+	waitForCount(t, guesserConn, "place", ops)
+
+	// The sampler and the room's broadcast worker, plus some slack
+	if grew := int(peak.Load()) - before; grew > 10 {
+		t.Errorf("expected a burst of %d draws to use a bounded number of goroutines, grew by %d", ops, grew)
+	}
+}
+
+func TestCanvasOpsReachGuessersInOrder(t *testing.T) {
+	room := newTestRoom(t, "broadcast-canvas-order")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	for range 20 {
+		placePixel(t, drawer, 1, 1)
+		ClearCanvas(room, drawer)
+		placePixel(t, drawer, 2, 2)
+		HandleUndo(drawer)
+		HandleBackgroundChange(drawer, json.RawMessage(`{"color":"#112233"}`))
+	}
+
+	want := []string{"place", "canvas_cleared", "place", "canvas_state", "background_changed"}
+	waitForCount(t, guesserConn, "background_changed", 20)
+	var got []string
+	for _, msg := range guesserConn.messages() {
+		got = append(got, msg.Type)
+	}
+	if len(got) != 20*len(want) {
+		t.Fatalf("expected %d canvas messages, got %d: %v", 20*len(want), len(got), got)
+	}
+	for i, typ := range got {
+		if typ != want[i%len(want)] {
+			t.Fatalf("message %d: expected %s, got %s (sequence %v)", i, want[i%len(want)], typ, got)
+		}
+	}
+}
+
+func TestGameStateKeepsOrderWithBroadcasts(t *testing.T) {
+	room := newTestRoom(t, "state-order")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	held, heldConn := addConnectedPlayer(room, "held")
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	held.IsConnected = false
+
+	queueBroadcast(room, internal.Message[any]{Type: "before"}, nil)
+	broadcastGameStateNow(room)
+	queueBroadcast(room, internal.Message[any]{Type: "after"}, nil)
+
+	for name, conn := range map[string]*fakeConn{"drawer": drawerConn, "guesser": guesserConn} {
+		flushBroadcasts(t, room, conn)
+		var order []string
+		for _, msg := range conn.messages() {
+			if msg.Type == "before" || msg.Type == "game_state_update" || msg.Type == "after" {
+				order = append(order, msg.Type)
+			}
+		}
+		if fmt.Sprint(order) != "[before game_state_update after]" {
+			t.Errorf("%s: expected the state in order with the room's broadcasts, got %v", name, order)
+		}
+	}
+	if got := len(heldConn.messagesOfType("game_state_update")); got != 0 {
+		t.Errorf("expected nothing written to a held seat, got %d game_state_update", got)
+	}
+}
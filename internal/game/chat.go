@@ -0,0 +1,97 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// =============================================================================
+// CHAT
+// =============================================================================
+
+// HandleChatMessage relays free-form chat to the room. Unlike guesses, chat is
+// open to everyone (the drawer and players who already guessed included) and
+// never scores; to keep it from leaking the answer, any message containing
+// the current word is rejected. Muted players only see their own messages.
+func HandleChatMessage(player *internal.Player, text string) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleChatMessage] Player %s has no room reference", player.Username)
+		return
+	}
+
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return
+	}
+	if length := utf8.RuneCountInString(text); length > MaxChatLength {
+		log.Printf("[HandleChatMessage] room=%s player=%s message too long (%d characters), rejecting",
+			room.Id, player.Id, length)
+		SendErrorToPlayer(player, "chat_too_long",
+			fmt.Sprintf("Chat messages can be at most %d characters", MaxChatLength))
+		return
+	}
+
+	room.Mu.RLock()
+	leaksAnswer := containsWord(text, room.Word)
+	chatMessage := internal.Message[any]{
+		Type: "chat_message",
+		Data: map[string]any{
+			"room_id":   room.Id,
+			"player_id": player.Id,
+			"username":  player.Username,
+			"message":   text,
+			"timestamp": time.Now().UnixMilli(),
+		},
+	}
+	roomID := room.Id
+	muted := player.IsMuted
+	room.Mu.RUnlock()
+
+	if leaksAnswer {
+		log.Printf("[HandleChatMessage] room=%s player=%s chat contains the answer, blocking", roomID, player.Id)
+		SendErrorToPlayer(player, "chat_blocked", "Chat messages can't contain the word being drawn")
+		return
+	}
+
+	if muted {
+		if err := player.SafeWriteJSON(chatMessage); err != nil {
+			log.Printf("[HandleChatMessage] room=%s: failed to echo muted chat to %s: %v", roomID, player.Id, err)
+		}
+		return
+	}
+	queueBroadcast(room, chatMessage, nil)
+}
+
+// containsWord reports whether text contains word (case-insensitive) as a
+// whole word or phrase, so "apple!" matches apple but "pineapple" does not
+func containsWord(text, word string) bool {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if word == "" {
+		return false
+	}
+	text = strings.ToLower(text)
+	for offset := 0; ; {
+		i := strings.Index(text[offset:], word)
+		if i < 0 {
+			return false
+		}
+		start, end := offset+i, offset+i+len(word)
+		before, _ := utf8.DecodeLastRuneInString(text[:start])
+		after, _ := utf8.DecodeRuneInString(text[end:])
+		if !isWordRune(before) && !isWordRune(after) {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
@@ -0,0 +1,63 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDrawerChatIsRelayed(t *testing.T) {
+	room := newTestRoom(t, "chat-drawer")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	room.Mu.Lock()
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	room.Mu.Unlock()
+
+	HandleChatMessage(drawer, "  nice try everyone  ")
+
+	msg, ok := guesserConn.waitFor("chat_message", time.Second)
+	if !ok {
+		t.Fatal("expected the drawer's chat to reach the guesser")
+	}
+	var data map[string]any
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad chat_message payload: %v", err)
+	}
+	if data["message"] != "nice try everyone" || data["username"] != drawer.Username || data["timestamp"] == nil {
+		t.Errorf("unexpected chat payload %v", data)
+	}
+	if guesses := guesserConn.messagesOfType("guess_message"); len(guesses) != 0 {
+		t.Errorf("expected chat not to be treated as a guess, got %d guess messages", len(guesses))
+	}
+}
+
+func TestChatContainingAnswerIsBlocked(t *testing.T) {
+	room := newTestRoom(t, "chat-filter")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	room.Mu.Lock()
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	room.Mu.Unlock()
+
+	HandleChatMessage(drawer, "the answer is APPLE!")
+
+	if _, ok := drawerConn.waitFor("error", time.Second); !ok {
+		t.Error("expected the sender to be told the message was blocked")
+	}
+	// A later allowed message proves the blocked one was never broadcast
+	HandleChatMessage(drawer, "pineapple pizza anyone?")
+	msg, ok := guesserConn.waitFor("chat_message", time.Second)
+	if !ok {
+		t.Fatal("expected a message merely containing the word as a substring to be relayed")
+	}
+	var data map[string]any
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad chat_message payload: %v", err)
+	}
+	if data["message"] != "pineapple pizza anyone?" {
+		t.Errorf("expected the blocked message never to be relayed, got %v", data["message"])
+	}
+}
@@ -4,9 +4,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
 	"time"
 
-	"github.com/gorilla/websocket"
 	"github.com/scythe504/skribblr-backend/internal"
 	"github.com/scythe504/skribblr-backend/internal/utils"
 )
@@ -64,7 +64,7 @@ func HandlePixelDrawEnhanced(player *internal.Player, rawData json.RawMessage) {
 
 	// TODO: 6. Validate pixel data
 	switch pixelMessage.Type {
-	case internal.PixelPlace, internal.ErasePixel:
+	case internal.PixelPlace, internal.ErasePixel, internal.FillPixel:
 		if pixelMessage.X == nil || pixelMessage.Y == nil {
 			log.Printf("[HandlePixelDrawEnhanced] Missing X/Y coordinates for single pixel operation from player %s",
 				player.Username)
@@ -103,6 +103,36 @@ func HandlePixelDrawEnhanced(player *internal.Player, rawData json.RawMessage) {
 			// Nothing to draw/erase
 			return
 		}
+	default:
+		// - Unknown type (typo or malicious): don't store or rebroadcast it
+		log.Printf("[HandlePixelDrawEnhanced] Unknown pixel type %q from player %s, rejecting",
+			pixelMessage.Type, player.Username)
+		go SendErrorToPlayer(player, "invalid_pixel_type",
+			fmt.Sprintf("Unknown pixel type %q", pixelMessage.Type))
+		return
+	}
+
+	// - Placed pixels need a #rrggbb color, which clients render as-is;
+	//   erases carry none
+	switch pixelMessage.Type {
+	case internal.PixelPlace, internal.BatchPlace, internal.FillPixel:
+		if !internal.IsHexColor(pixelMessage.Color) {
+			log.Printf("[HandlePixelDrawEnhanced] Invalid color %q from player %s, rejecting",
+				pixelMessage.Color, player.Username)
+			go SendErrorToPlayer(player, "invalid_color", "Colors must be #rrggbb hex values")
+			return
+		}
+		pixelMessage.Color = strings.ToLower(pixelMessage.Color)
+	default:
+		pixelMessage.Color = ""
+	}
+	if pixelMessage.BrushSize != 0 &&
+		(pixelMessage.BrushSize < internal.MinBrushSize || pixelMessage.BrushSize > internal.MaxBrushSize) {
+		log.Printf("[HandlePixelDrawEnhanced] Brush size %d out of range from player %s, rejecting",
+			pixelMessage.BrushSize, player.Username)
+		go SendErrorToPlayer(player, "invalid_brush_size",
+			fmt.Sprintf("Brush size must be between %d and %d", internal.MinBrushSize, internal.MaxBrushSize))
+		return
 	}
 
 	// TODO: 7. Normalize coordinates
@@ -110,7 +140,7 @@ func HandlePixelDrawEnhanced(player *internal.Player, rawData json.RawMessage) {
 	// - If client sent scaled coordinates, convert to grid positions
 	// - Maintain aspect ratio
 	switch pixelMessage.Type {
-	case internal.PixelPlace, internal.ErasePixel:
+	case internal.PixelPlace, internal.ErasePixel, internal.FillPixel:
 		gridX, gridY := internal.NormalizeCoordinates(*pixelMessage.X, *pixelMessage.Y, player.CanvasWidth, player.CanvasHeight)
 		pixelMessage.X = &gridX
 		pixelMessage.Y = &gridY
@@ -126,56 +156,66 @@ func HandlePixelDrawEnhanced(player *internal.Player, rawData json.RawMessage) {
 		pixelMessage.Timestamp = time.Now().UnixMilli()
 	}
 
+	// A fill becomes the batch of cells it covers, worked out from the canvas
+	// as the server has it so every client ends up with the same picture
+	if pixelMessage.Type == internal.FillPixel {
+		start := internal.GridPosition{GridX: *pixelMessage.X, GridY: *pixelMessage.Y}
+		pixels := internal.FloodFill(room.CanvasState, start, pixelMessage.Color)
+		if len(pixels) == 0 {
+			log.Printf("[HandlePixelDrawEnhanced] Fill at (%d,%d) by player %s changes nothing",
+				start.GridX, start.GridY, player.Username)
+			return
+		}
+		log.Printf("[HandlePixelDrawEnhanced] Fill at (%d,%d) by player %s covers %d cells",
+			start.GridX, start.GridY, player.Username, len(pixels))
+		pixelMessage = internal.PixelMessage{
+			Type:      internal.BatchPlace,
+			Color:     pixelMessage.Color,
+			Timestamp: pixelMessage.Timestamp,
+			Pixels:    pixels,
+		}
+	}
+
+	// Record the canvas before this op so the drawer can undo it
+	pushUndoSnapshot(room)
+	room.HasDrawn = true
+
 	// TODO: 8. Apply changes to room.CanvasState
 	switch pixelMessage.Type {
 	// - Single pixel: append/update canvas
 	case internal.PixelPlace:
 		room.CanvasState = append(room.CanvasState, pixelMessage)
+		markCanvasDrawn(room)
 		log.Printf("[HandlePixelDrawEnhanced] Added pixel at (%d,%d) by player %s",
 			*pixelMessage.X, *pixelMessage.Y, player.Username)
 	case internal.BatchPlace:
 		room.CanvasState = append(room.CanvasState, pixelMessage)
+		if len(pixelMessage.Pixels) > 0 {
+			markCanvasDrawn(room)
+		}
 		log.Printf("[HandlePixelDrawEnhanced] Added %d pixels in batch by player %s",
 			len(pixelMessage.Pixels), player.Username)
 		// - Batch: loop through each pixel and append/update
 	case internal.ErasePixel:
-		newCanvas := []internal.PixelMessage{}
-		eraseCount := 0
-		for _, existing := range room.CanvasState {
-			if existing.Type == internal.PixelPlace &&
-				existing.X != nil && existing.Y != nil &&
-				*existing.X == *pixelMessage.X && *existing.Y == *pixelMessage.Y {
-				eraseCount++
-				continue // Skip this pixel (erase it)
-			}
-			newCanvas = append(newCanvas, existing)
-		}
 		// - Erase operations: remove pixels from canvas
-		room.CanvasState = newCanvas
+		eraseCount := eraseFromCanvas(room, []internal.GridPosition{{GridX: *pixelMessage.X, GridY: *pixelMessage.Y}})
 		log.Printf("[HandlePixelDrawEnhanced] Erased %d pixel(s) at (%d,%d) by player %s",
 			eraseCount, *pixelMessage.X, *pixelMessage.Y, player.Username)
+		trackEmptyCanvas(room)
 	case internal.BatchErase:
-		eraseMap := map[string]struct{}{}
-		for _, p := range pixelMessage.Pixels {
-			key := fmt.Sprintf("%d_%d", p.GridX, p.GridY)
-			eraseMap[key] = struct{}{}
-		}
-
-		newCanvas := []internal.PixelMessage{}
-		eraseCount := 0
-		for _, existing := range room.CanvasState {
-			if existing.Type == internal.PixelPlace && existing.X != nil && existing.Y != nil {
-				key := fmt.Sprintf("%d_%d", *existing.X, *existing.Y)
-				if _, ok := eraseMap[key]; ok {
-					eraseCount++
-					continue // Skip this pixel (erase it)
-				}
-			}
-			newCanvas = append(newCanvas, existing)
-		}
-		room.CanvasState = newCanvas
+		eraseCount := eraseFromCanvas(room, pixelMessage.Pixels)
 		log.Printf("[HandlePixelDrawEnhanced] Erased %d pixel(s) in batch by player %s",
 			eraseCount, player.Username)
+		trackEmptyCanvas(room)
+	}
+
+	// Bound per-round memory: fold a long op history into per-color batches.
+	// Clients keep their own canvas, so nothing is broadcast for this.
+	if MaxCanvasOpsPerRound > 0 && len(room.CanvasState) > MaxCanvasOpsPerRound {
+		before := len(room.CanvasState)
+		room.CanvasState = internal.CompactCanvas(room.CanvasState)
+		log.Printf("[HandlePixelDrawEnhanced] Room %s: compacted canvas from %d to %d ops",
+			room.Id, before, len(room.CanvasState))
 	}
 
 	// TODO: 9. Broadcast pixel draw message to other players
@@ -187,16 +227,76 @@ func HandlePixelDrawEnhanced(player *internal.Player, rawData json.RawMessage) {
 	}
 
 	// TODO: 10. Optional: throttle or rate-limit broadcasts
-	// - Avoid flooding network for large batch operations
+	// - Single pixels are held for PixelBroadcastWindow and sent as batches;
+	//   anything else flushes them first so ops still arrive in order
+	if pixelMessage.Type == internal.PixelPlace && PixelBroadcastWindow > 0 {
+		bufferPixel(room, player, pixelMessage)
+		return
+	}
+	flushPendingPixelsLocked(room)
 
 	// TODO: 12. Unlock room.Mu before broadcasting
-	// - Broadcasting can be outside lock to avoid blocking other actions
-	// CRITICAL FIX: Broadcast in goroutine to avoid holding lock during network I/O
-	go func() {
-		log.Printf("[HandlePixelDrawEnhanced] Broadcasting %s to other players in room %s",
-			pixelMessage.Type, room.Id)
-		SafeBroadcastToRoomExcept(room, pixelDrawMessage, room.Current)
-	}()
+	// - Queued while still holding the lock, so clients get canvas ops in the
+	//   order they were applied; the broadcast queue sends outside the lock
+	log.Printf("[HandlePixelDrawEnhanced] Queueing %s for other players in room %s",
+		pixelMessage.Type, room.Id)
+	queueBroadcastLocked(room, pixelDrawMessage, player)
+}
+
+// bufferPixel holds a single-pixel place for the next batched broadcast,
+// scheduling a flush PixelBroadcastWindow after the first one buffered.
+// Caller must hold room.Mu.
+func bufferPixel(room *internal.Room, player *internal.Player, pixel internal.PixelMessage) {
+	if room.PendingPixelsFrom != player {
+		flushPendingPixelsLocked(room)
+	}
+	room.PendingPixelsFrom = player
+	room.PendingPixels = append(room.PendingPixels, pixel)
+	if len(room.PendingPixels) > 1 {
+		return
+	}
+
+	time.AfterFunc(PixelBroadcastWindow, func() {
+		room.Mu.Lock()
+		defer room.Mu.Unlock()
+		flushPendingPixelsLocked(room)
+	})
+}
+
+// flushPendingPixelsLocked queues the buffered pixels for everyone but their
+// drawer, one batch_place per run of same color and brush size (a run of one
+// goes out as a plain place). Caller must hold room.Mu.
+func flushPendingPixelsLocked(room *internal.Room) {
+	pending, from := room.PendingPixels, room.PendingPixelsFrom
+	room.PendingPixels, room.PendingPixelsFrom = nil, nil
+	if len(pending) == 0 {
+		return
+	}
+
+	for start := 0; start < len(pending); {
+		end := start + 1
+		for end < len(pending) && pending[end].Color == pending[start].Color &&
+			pending[end].BrushSize == pending[start].BrushSize {
+			end++
+		}
+
+		op := pending[start]
+		if end-start > 1 {
+			op = internal.PixelMessage{
+				Type:      internal.BatchPlace,
+				Color:     pending[start].Color,
+				BrushSize: pending[start].BrushSize,
+				Timestamp: pending[end-1].Timestamp,
+				Pixels:    make([]internal.GridPosition, 0, end-start),
+			}
+			for _, p := range pending[start:end] {
+				op.Pixels = append(op.Pixels, internal.GridPosition{GridX: *p.X, GridY: *p.Y})
+			}
+		}
+		queueBroadcastLocked(room, internal.Message[any]{Type: string(op.Type), Data: op}, from)
+		start = end
+	}
+	log.Printf("[flushPendingPixelsLocked] room=%s flushed %d buffered pixel(s)", room.Id, len(pending))
 }
 
 // ClearCanvas resets the drawing canvas
@@ -214,9 +314,11 @@ func ClearCanvas(room *internal.Room, clearedBy *internal.Player) {
 		return
 	}
 
-	// 2. Clear room.CanvasState slice
+	// 2. Clear room.CanvasState slice (undoable like any other op)
 	pixelCount := len(room.CanvasState)
+	pushUndoSnapshot(room)
 	room.CanvasState = make([]internal.PixelMessage, 0)
+	trackEmptyCanvas(room)
 
 	// 3. Prepare canvas_cleared message (snapshot data before unlock)
 	clearedCanvasMessage := internal.Message[map[string]any]{
@@ -229,19 +331,171 @@ func ClearCanvas(room *internal.Room, clearedBy *internal.Player) {
 		},
 	}
 
+	// 4. Queue canvas_cleared before unlocking, so it goes out in the same
+	// order as the draw ops around it (the broadcast also logs the clear)
+	flushPendingPixelsLocked(room)
+	queueBroadcastLocked(room, clearedCanvasMessage, clearedBy)
 	room.Mu.Unlock()
 
 	log.Printf("[ClearCanvas] Cleared %d pixels from canvas in room %s by player %s",
 		pixelCount, room.Id, clearedBy.Username)
+}
+
+// HandleUndo reverts the drawer's most recent canvas operation
+func HandleUndo(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleUndo] Player %s has no room reference", player.Username)
+		return
+	}
+
+	room.Mu.Lock()
+	if !canEditCanvas(room, player) {
+		log.Printf("[HandleUndo] Player %s cannot undo in room %s", player.Username, room.Id)
+		room.Mu.Unlock()
+		return
+	}
+	if len(room.UndoStack) == 0 {
+		log.Printf("[HandleUndo] Nothing to undo in room %s", room.Id)
+		room.Mu.Unlock()
+		return
+	}
+
+	last := len(room.UndoStack) - 1
+	room.RedoStack = append(room.RedoStack, room.CanvasState)
+	room.CanvasState = room.UndoStack[last]
+	room.UndoStack = room.UndoStack[:last]
+	trackEmptyCanvas(room)
+
+	flushPendingPixelsLocked(room)
+	queueBroadcastLocked(room, canvasStateMessage(room), nil)
+	room.Mu.Unlock()
+
+	log.Printf("[HandleUndo] Player %s undid last operation in room %s", player.Username, room.Id)
+}
+
+// HandleRedo re-applies the last undone operation, if nothing was drawn since
+func HandleRedo(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleRedo] Player %s has no room reference", player.Username)
+		return
+	}
+
+	room.Mu.Lock()
+	if !canEditCanvas(room, player) {
+		log.Printf("[HandleRedo] Player %s cannot redo in room %s", player.Username, room.Id)
+		room.Mu.Unlock()
+		return
+	}
+	if len(room.RedoStack) == 0 {
+		log.Printf("[HandleRedo] Nothing to redo in room %s", room.Id)
+		room.Mu.Unlock()
+		return
+	}
+
+	last := len(room.RedoStack) - 1
+	room.UndoStack = append(room.UndoStack, room.CanvasState)
+	room.CanvasState = room.RedoStack[last]
+	room.RedoStack = room.RedoStack[:last]
+	trackEmptyCanvas(room)
+
+	flushPendingPixelsLocked(room)
+	queueBroadcastLocked(room, canvasStateMessage(room), nil)
+	room.Mu.Unlock()
+
+	log.Printf("[HandleRedo] Player %s redid last operation in room %s", player.Username, room.Id)
+}
+
+// HandleBackgroundChange lets the drawer set the canvas background for their turn
+func HandleBackgroundChange(player *internal.Player, rawData json.RawMessage) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleBackgroundChange] Player %s has no room reference", player.Username)
+		return
+	}
+
+	var background internal.CanvasBackground
+	if err := json.Unmarshal(rawData, &background); err != nil {
+		log.Printf("[HandleBackgroundChange] Malformed background from player %s: %v",
+			player.Username, err)
+		return
+	}
+	if !background.IsValid() {
+		log.Printf("[HandleBackgroundChange] Invalid background color %q from player %s",
+			background.Color, player.Username)
+		return
+	}
+
+	room.Mu.Lock()
+	if !canEditCanvas(room, player) {
+		log.Printf("[HandleBackgroundChange] Player %s cannot change the background in room %s",
+			player.Username, room.Id)
+		room.Mu.Unlock()
+		return
+	}
+	room.CanvasBackground = background
 
-	// CRITICAL FIX: Broadcast in goroutine to avoid any potential deadlock
-	go func() {
-		log.Printf("[ClearCanvas] Broadcasting canvas_cleared to players in room %s", room.Id)
-		SafeBroadcastToRoomExcept(room, clearedCanvasMessage, clearedBy)
+	backgroundMessage := internal.Message[map[string]any]{
+		Type: "background_changed",
+		Data: map[string]any{
+			"room_id":           room.Id,
+			"canvas_background": background,
+			"changed_by":        player.Id,
+			"timestamp":         time.Now().UnixMilli(),
+		},
+	}
+	flushPendingPixelsLocked(room)
+	queueBroadcastLocked(room, backgroundMessage, nil)
+	room.Mu.Unlock()
 
-		// 4. Log canvas clear action
-		utils.LogGameEvent(room, clearedCanvasMessage.Type, clearedCanvasMessage.Data)
-	}()
+	log.Printf("[HandleBackgroundChange] Player %s set background %+v in room %s",
+		player.Username, background, room.Id)
+}
+
+// canEditCanvas reports whether player may modify the canvas. Caller must hold room.Mu.
+func canEditCanvas(room *internal.Room, player *internal.Player) bool {
+	return room.Phase == internal.PhaseDrawing && room.Current == player && player.CanDraw
+}
+
+// pushUndoSnapshot saves a copy of the canvas onto the undo stack, trimming it
+// to UndoHistoryDepth. Any new operation invalidates the redo stack.
+// Caller must hold room.Mu.
+func pushUndoSnapshot(room *internal.Room) {
+	if UndoHistoryDepth <= 0 {
+		room.UndoStack = nil
+		room.RedoStack = nil
+		return
+	}
+
+	snapshot := append([]internal.PixelMessage(nil), room.CanvasState...)
+	room.UndoStack = append(room.UndoStack, snapshot)
+	if overflow := len(room.UndoStack) - UndoHistoryDepth; overflow > 0 {
+		room.UndoStack = room.UndoStack[overflow:]
+	}
+	room.RedoStack = nil
+}
+
+// resetCanvasHistory drops undo/redo history. Caller must hold room.Mu.
+func resetCanvasHistory(room *internal.Room) {
+	room.UndoStack = make([][]internal.PixelMessage, 0)
+	room.RedoStack = make([][]internal.PixelMessage, 0)
+	// Pixels from the old canvas must not land on the new one
+	room.PendingPixels, room.PendingPixelsFrom = nil, nil
+}
+
+// canvasStateMessage snapshots the full canvas for broadcast. Caller must hold room.Mu.
+func canvasStateMessage(room *internal.Room) internal.Message[map[string]any] {
+	return internal.Message[map[string]any]{
+		Type: "canvas_state",
+		Data: map[string]any{
+			"room_id":      room.Id,
+			"canvas_state": append([]internal.PixelMessage(nil), room.CanvasState...),
+			"can_undo":     len(room.UndoStack) > 0,
+			"can_redo":     len(room.RedoStack) > 0,
+			"timestamp":    time.Now().UnixMilli(),
+		},
+	}
 }
 
 // UpdateDrawingPermissions sets who can draw based on game state
@@ -280,78 +534,210 @@ func UpdateDrawingPermissions(room *internal.Room) {
 
 	room.Mu.Unlock()
 
-	// CRITICAL FIX: Broadcast in goroutine to avoid any potential deadlock
-	go func() {
-		log.Printf("[UpdateDrawingPermissions] Broadcasting permission update to room %s", room.Id)
-		SafeBroadcastToRoom(room, drawingPermissionMessage)
-	}()
+	log.Printf("[UpdateDrawingPermissions] Broadcasting permission update to room %s", room.Id)
+	queueBroadcast(room, drawingPermissionMessage, nil)
 }
 
 // =============================================================================
 // BROADCASTING & MESSAGING
 // =============================================================================
 
+// SafeBroadcastToRoom sends msg to every connected player through the room's
+// broadcast queue, so it keeps its order relative to every other queued
+// broadcast. Caller must not hold room.Mu.
 func SafeBroadcastToRoom[T any](room *internal.Room, msg internal.Message[T]) {
-	// 1. Snapshot connected players under lock
-	room.Mu.Lock()
-	players := make([]*internal.Player, 0, len(room.Players))
-	for _, player := range room.Players {
-		if player.IsConnected {
-			players = append(players, player)
+	queueBroadcast(room, msg, nil)
+}
+
+// SafeBroadcastToRoomExcept is SafeBroadcastToRoom skipping exclude
+func SafeBroadcastToRoomExcept[T any](room *internal.Room, msg internal.Message[T], exclude *internal.Player) {
+	queueBroadcast(room, msg, exclude)
+}
+
+// SendErrorToPlayer privately notifies player that their request was rejected
+func SendErrorToPlayer(player *internal.Player, code string, message string) {
+	errorMessage := internal.Message[internal.ErrorData]{
+		Type: "error",
+		Data: internal.ErrorData{
+			Code:    code,
+			Message: message,
+		},
+	}
+	if err := player.SafeWriteJSON(errorMessage); err != nil {
+		log.Printf("[SendErrorToPlayer] Failed to send %s error to player %s (%s): %v",
+			code, player.Id, player.Username, err)
+	}
+}
+
+// trackEmptyCanvas notes when the drawer's canvas loses all visible content
+// (e.g. it was cleared) and arms the empty-canvas skip, or disarms it once
+// something is drawn again. It renders the whole canvas, so it's only called
+// after ops that can remove content: erase, clear, undo and redo; placing
+// pixels uses markCanvasDrawn. Caller must hold room.Mu.
+func trackEmptyCanvas(room *internal.Room) {
+	if room.Phase != internal.PhaseDrawing {
+		return
+	}
+	if len(internal.RenderCanvas(room.CanvasState)) > 0 {
+		room.CanvasEmptySince = time.Time{}
+		return
+	}
+	if !room.CanvasEmptySince.IsZero() {
+		return // already empty, the pending check covers it
+	}
+	room.CanvasEmptySince = time.Now()
+	if EmptyCanvasSkipAfter > 0 {
+		scheduleEmptyCanvasSkip(room, room.Current, room.CanvasEmptySince)
+	}
+}
+
+// markCanvasDrawn disarms the empty-canvas skip after pixels were placed,
+// which always leaves something visible. Caller must hold room.Mu.
+func markCanvasDrawn(room *internal.Room) {
+	room.CanvasEmptySince = time.Time{}
+}
+
+// scheduleEmptyCanvasSkip ends drawer's turn if the canvas is still in the
+// same empty stretch (since) after EmptyCanvasSkipAfter, so guessers aren't
+// left staring at nothing
+func scheduleEmptyCanvasSkip(room *internal.Room, drawer *internal.Player, since time.Time) {
+	time.AfterFunc(EmptyCanvasSkipAfter, func() {
+		if room.Context != nil && room.Context.Err() != nil {
+			return
+		}
+
+		room.Mu.Lock()
+		stillEmpty := room.Phase == internal.PhaseDrawing && room.Current == drawer &&
+			room.CanvasEmptySince.Equal(since)
+		if !stillEmpty {
+			room.Mu.Unlock()
+			return
 		}
+		room.CanvasEmptySince = time.Time{} // skip once
+		room.RoundRecorded = true           // a skipped turn is not a completed word
+		roomID := room.Id
+		room.Mu.Unlock()
+
+		log.Printf("[scheduleEmptyCanvasSkip] room=%s: drawer %s left the canvas empty for %v, skipping turn",
+			roomID, drawer.Username, EmptyCanvasSkipAfter)
+		SafeBroadcastToRoom(room, internal.Message[any]{
+			Type: "drawer_skipped",
+			Data: map[string]any{
+				"room_id":   roomID,
+				"player_id": drawer.Id,
+				"username":  drawer.Username,
+				"reason":    "empty_canvas",
+			},
+		})
+		CancelPhaseTimer(room)
+		NextRound(room)
+	})
+}
+
+// scheduleAFKDrawerSkip ends drawer's turn if they still haven't sent a
+// pixel_draw after the given time into the drawing phase that began at
+// startedAt, so guessers don't wait out the whole timer for nothing
+func scheduleAFKDrawerSkip(room *internal.Room, drawer *internal.Player, startedAt time.Time, after time.Duration) {
+	time.AfterFunc(after, func() {
+		if room.Context != nil && room.Context.Err() != nil {
+			return
+		}
+
+		room.Mu.Lock()
+		afk := room.Phase == internal.PhaseDrawing && room.Current == drawer &&
+			room.PhaseChangedAt.Equal(startedAt) && !room.HasDrawn
+		if !afk {
+			room.Mu.Unlock()
+			return
+		}
+		room.RoundRecorded = true // a skipped turn is not a completed word
+		roomID := room.Id
+		room.Mu.Unlock()
+
+		log.Printf("[scheduleAFKDrawerSkip] room=%s: drawer %s sent nothing in %v, skipping turn",
+			roomID, drawer.Username, after)
+		SafeBroadcastToRoom(room, internal.Message[any]{
+			Type: "afk_skip",
+			Data: map[string]any{
+				"room_id":   roomID,
+				"player_id": drawer.Id,
+				"username":  drawer.Username,
+				"after_ms":  after.Milliseconds(),
+			},
+		})
+		CancelPhaseTimer(room)
+		NextRound(room)
+	})
+}
+
+// eraseFromCanvas removes the given cells from every placement in the canvas,
+// dropping batches left empty, and returns how many pixels were removed.
+// Caller must hold room.Mu.
+func eraseFromCanvas(room *internal.Room, cells []internal.GridPosition) int {
+	erase := make(map[internal.GridPosition]struct{}, len(cells))
+	for _, c := range cells {
+		erase[c] = struct{}{}
 	}
-	room.Mu.Unlock()
 
-	// 2. Iterate over snapshot and send
-	successCount := 0
-	for _, player := range players {
-		if err := player.SafeWriteJSON(msg); err != nil {
-			log.Printf("[Broadcast][Room:%s] Failed for player %s (%s): %v",
-				room.Id, player.Id, player.Username, err)
-			continue
+	newCanvas := make([]internal.PixelMessage, 0, len(room.CanvasState))
+	eraseCount := 0
+	for _, existing := range room.CanvasState {
+		switch {
+		case existing.Type == internal.PixelPlace && existing.X != nil && existing.Y != nil:
+			if _, ok := erase[internal.GridPosition{GridX: *existing.X, GridY: *existing.Y}]; ok {
+				eraseCount++
+				continue // Skip this pixel (erase it)
+			}
+		case existing.Type == internal.BatchPlace:
+			kept := make([]internal.GridPosition, 0, len(existing.Pixels))
+			for _, p := range existing.Pixels {
+				if _, ok := erase[p]; ok {
+					eraseCount++
+					continue
+				}
+				kept = append(kept, p)
+			}
+			if len(kept) == 0 {
+				continue
+			}
+			existing.Pixels = kept
 		}
-		successCount++
-		log.Printf("[Broadcast][Room:%s] Sent to player %s (%s)",
-			room.Id, player.Id, player.Username)
+		newCanvas = append(newCanvas, existing)
 	}
-	log.Printf("[Broadcast][Room:%s] Successfully sent to %d/%d players",
-		room.Id, successCount, len(players))
+	room.CanvasState = newCanvas
+	return eraseCount
 }
 
-func SafeBroadcastToRoomExcept[T any](room *internal.Room, msg internal.Message[T], exclude *internal.Player) {
-	// 1. Snapshot connected players under lock
+// BroadcastGameState sends complete game state to all players. Calls made
+// within GameStateBroadcastWindow of each other are coalesced into a single
+// broadcast, which snapshots the state when it fires.
+func BroadcastGameState(room *internal.Room) {
+	if GameStateBroadcastWindow <= 0 {
+		broadcastGameStateNow(room)
+		return
+	}
+
 	room.Mu.Lock()
-	players := make([]*internal.Player, 0, len(room.Players))
-	for _, player := range room.Players {
-		if player.IsConnected {
-			players = append(players, player)
-		}
+	if room.StateBroadcastPending {
+		room.Mu.Unlock()
+		return
 	}
+	room.StateBroadcastPending = true
 	room.Mu.Unlock()
 
-	// 2. Iterate over snapshot and send, skipping excluded
-	successCount := 0
-	excludedCount := 0
-	for _, player := range players {
-		if exclude != nil && player.Id == exclude.Id {
-			excludedCount++
-			continue
-		}
-		if err := player.SafeWriteJSON(msg); err != nil {
-			log.Printf("[BroadcastExcept][Room:%s] Failed for player %s (%s): %v",
-				room.Id, player.Id, player.Username, err)
-			continue
+	time.AfterFunc(GameStateBroadcastWindow, func() {
+		room.Mu.Lock()
+		room.StateBroadcastPending = false
+		room.Mu.Unlock()
+
+		if room.Context != nil && room.Context.Err() != nil {
+			return
 		}
-		successCount++
-		log.Printf("[BroadcastExcept][Room:%s] Sent to player %s (%s)",
-			room.Id, player.Id, player.Username)
-	}
-	log.Printf("[BroadcastExcept][Room:%s] Successfully sent to %d players (excluded %d)",
-		room.Id, successCount, excludedCount)
+		broadcastGameStateNow(room)
+	})
 }
 
-// BroadcastGameState sends complete game state to all players
-func BroadcastGameState(room *internal.Room) {
+func broadcastGameStateNow(room *internal.Room) {
 	log.Printf("[BroadcastGameState] Broadcasting game state for room %s", room.Id)
 
 	// Validate first to avoid sending broken state
@@ -368,9 +754,16 @@ func BroadcastGameState(room *internal.Room) {
 	//    - Round information
 	baseState.RoundNumber = room.RoundNumber
 	baseState.MaxRounds = room.MaxRounds
-	//    - Player list (use ToPublicPlayer() to avoid sensitive data)
+	//    - Player list (lean snapshots keep the frequent broadcast small)
+	//      During an active round, guessers who haven't guessed get a redacted list
+	roundActive := room.Phase == internal.PhaseDrawing
+	var redactedPlayers []internal.PlayerSnapshot
 	for _, p := range room.Players {
-		baseState.Players = append(baseState.Players, p.ToPublicPlayer())
+		snapshot := internal.CreatePlayerSnapshot(p)
+		baseState.Players = append(baseState.Players, snapshot)
+		if roundActive {
+			redactedPlayers = append(redactedPlayers, snapshot.Redacted())
+		}
 	}
 	//    - Current drawer info
 	if room.Current != nil {
@@ -378,27 +771,24 @@ func BroadcastGameState(room *internal.Room) {
 	}
 	//    - Correct guessers
 	baseState.CorrectGuessers = room.CorrectGuessers
+	//    - Canvas backdrop
+	baseState.CanvasBackground = room.CanvasBackground
+	//    - Word difficulty (unless hidden)
+	baseState.WordDifficulty = guesserWordDifficulty(room)
+	//    - How many have guessed so far (while drawing)
+	baseState.GuessProgress = guessProgress(room)
 
 	// CRITICAL FIX: Move timer access inside the lock to prevent race condition
 	//    - Timer information
-	if room.Timer != nil {
-		baseState.TimeRemaining = int64(room.Timer.TimeRemaining)
-	}
+	baseState.TimeRemaining = room.RemainingTime()
 	//    - Masked word (if in drawing phase)
 	maskedWord := ""
 	fullWord := room.Word
+	fullDifficulty := room.WordDifficulty
 	if baseState.Phase == internal.PhaseDrawing {
 		maskedWord = utils.GetMaskedWord(room.Word)
 	}
 
-	// Snapshot current drawer for later use
-	var currentDrawer *internal.Player
-	if room.Current != nil {
-		currentDrawer = room.Current
-	}
-
-	room.Mu.RUnlock() // Now safe to unlock, we have all needed data
-
 	// Copy for guessers (masked word)
 	guesserState := baseState
 	guesserState.Word = maskedWord
@@ -407,39 +797,46 @@ func BroadcastGameState(room *internal.Room) {
 		Data: guesserState,
 	}
 
+	// Copy for guessers still guessing (masked word, redacted players, no
+	// correct guessers)
+	redactedState := guesserState
+	redactedState.Players = redactedPlayers
+	redactedState.CorrectGuessers = []internal.PlayerGuess{}
+	gameStateUpdateRedacted := internal.Message[internal.GameStateData]{
+		Type: "game_state_update",
+		Data: redactedState,
+	}
+
 	// Copy for drawer (full word)
 	drawerState := baseState
 	drawerState.Word = fullWord
+	drawerState.WordDifficulty = fullDifficulty
 	gameStateUpdateDrawer := internal.Message[internal.GameStateData]{
 		Type: "game_state_update",
 		Data: drawerState,
 	}
 
-	// 2. Send different data based on player role:
-	if currentDrawer != nil {
-		if err := currentDrawer.Conn.WriteJSON(gameStateUpdateDrawer); err != nil {
-			log.Printf("[BroadcastGameState] Failed to send drawer state to %s: %v",
-				currentDrawer.Username, err)
-			utils.LogGameEvent(room, gameStateUpdateDrawer.Type, map[string]any{
-				"game_state_data": drawerState,
-				"err":             err.Error(),
-			})
-			if websocket.IsCloseError(err) {
-				// CRITICAL FIX: Run removePlayer in goroutine to avoid potential deadlock
-				go func() {
-					log.Printf("[BroadcastGameState] Removing disconnected drawer %s", currentDrawer.Username)
-					removePlayer(currentDrawer)
-				}()
-			}
-		} else {
-			log.Printf("[BroadcastGameState] Sent drawer state to %s", currentDrawer.Username)
+	// 2. Queue each variant for its connected recipients, in order with the
+	// room's other broadcasts (queueing never blocks, so under the lock is fine)
+	informed := make([]*internal.Player, 0, len(room.Players))
+	pending := make([]*internal.Player, 0, len(room.Players))
+	for _, p := range room.Players {
+		switch {
+		case !p.IsConnected:
+			// held seats are sent the state when they reconnect
+		case p == room.Current:
+			queueSend(room, p, gameStateUpdateDrawer)
+		case !roundActive || p.HasGuessed:
+			informed = append(informed, p)
+		default:
+			pending = append(pending, p)
 		}
 	}
-
-	// 3. Broadcast game_state_update message to all other players
-	// IMPROVEMENT: Run in goroutine to avoid blocking
-	go func() {
-		log.Printf("[BroadcastGameState] Broadcasting guesser state to room %s", room.Id)
-		SafeBroadcastToRoomExcept(room, gameStateUpdateGuessers, currentDrawer)
-	}()
+	if len(informed) > 0 {
+		enqueueBroadcast(room, informed, gameStateUpdateGuessers)
+	}
+	if len(pending) > 0 {
+		enqueueBroadcast(room, pending, gameStateUpdateRedacted)
+	}
+	room.Mu.RUnlock()
 }
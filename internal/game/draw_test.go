@@ -0,0 +1,659 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func placePixel(t *testing.T, player *internal.Player, x, y int) {
+	t.Helper()
+	raw := json.RawMessage(fmt.Sprintf(`{"type":"place","x":%d,"y":%d,"color":"#000000","timestamp":1}`, x, y))
+	HandlePixelDrawEnhanced(player, raw)
+}
+
+func withPixelBroadcastWindow(t *testing.T, window time.Duration) {
+	t.Helper()
+	prev := PixelBroadcastWindow
+	PixelBroadcastWindow = window
+	t.Cleanup(func() { PixelBroadcastWindow = prev })
+}
+
+func TestUndoRedoRestoresCanvas(t *testing.T) {
+	room := newTestRoom(t, "undo-redo")
+	drawer := addTestPlayer(room, "drawer")
+	makeDrawer(room, drawer)
+
+	placePixel(t, drawer, 1, 1)
+	placePixel(t, drawer, 2, 2)
+	if got := len(room.CanvasState); got != 2 {
+		t.Fatalf("expected 2 ops on canvas, got %d", got)
+	}
+
+	HandleUndo(drawer)
+	if got := len(room.CanvasState); got != 1 {
+		t.Fatalf("expected 1 op after undo, got %d", got)
+	}
+
+	HandleRedo(drawer)
+	if got := len(room.CanvasState); got != 2 {
+		t.Fatalf("expected 2 ops after redo, got %d", got)
+	}
+	if x := *room.CanvasState[1].X; x != 2 {
+		t.Errorf("expected redone pixel at x=2, got x=%d", x)
+	}
+}
+
+func TestUndoTreatsBatchAsOneStep(t *testing.T) {
+	room := newTestRoom(t, "undo-batch")
+	drawer := addTestPlayer(room, "drawer")
+	makeDrawer(room, drawer)
+
+	placePixel(t, drawer, 1, 1)
+	HandlePixelDrawEnhanced(drawer, json.RawMessage(
+		`{"type":"batch_place","color":"#ff0000","timestamp":2,"pixels":[{"gridX":3,"gridY":3},{"gridX":4,"gridY":4},{"gridX":5,"gridY":5}]}`))
+	if got := len(room.CanvasState); got != 2 {
+		t.Fatalf("expected the batch stored as one op, got %d ops", got)
+	}
+
+	HandleUndo(drawer)
+	if got := len(room.CanvasState); got != 1 || room.CanvasState[0].Type != internal.PixelPlace {
+		t.Fatalf("expected undo to remove the whole batch, canvas is %+v", room.CanvasState)
+	}
+
+	HandleRedo(drawer)
+	if got := len(room.CanvasState); got != 2 {
+		t.Fatalf("expected redo to restore the batch, got %d ops", got)
+	}
+	if batch := room.CanvasState[1]; batch.Type != internal.BatchPlace || len(batch.Pixels) != 3 {
+		t.Errorf("expected the restored batch with 3 pixels, got %+v", batch)
+	}
+}
+
+func TestRedoInvalidatedByNewDraw(t *testing.T) {
+	room := newTestRoom(t, "redo-invalidated")
+	drawer := addTestPlayer(room, "drawer")
+	makeDrawer(room, drawer)
+
+	placePixel(t, drawer, 1, 1)
+	HandleUndo(drawer)
+	placePixel(t, drawer, 3, 3)
+
+	HandleRedo(drawer)
+	if got := len(room.CanvasState); got != 1 {
+		t.Fatalf("expected redo to be a no-op after a new draw, canvas has %d ops", got)
+	}
+	if x := *room.CanvasState[0].X; x != 3 {
+		t.Errorf("expected remaining pixel at x=3, got x=%d", x)
+	}
+}
+
+func TestUndoHistoryDepth(t *testing.T) {
+	prev := UndoHistoryDepth
+	UndoHistoryDepth = 2
+	t.Cleanup(func() { UndoHistoryDepth = prev })
+
+	room := newTestRoom(t, "undo-depth")
+	drawer := addTestPlayer(room, "drawer")
+	makeDrawer(room, drawer)
+
+	for i := 0; i < 4; i++ {
+		placePixel(t, drawer, i, i)
+	}
+	for i := 0; i < 4; i++ {
+		HandleUndo(drawer)
+	}
+
+	if got := len(room.CanvasState); got != 2 {
+		t.Errorf("expected undo to stop after %d steps leaving 2 ops, got %d", UndoHistoryDepth, got)
+	}
+}
+
+func TestUndoRejectedForNonDrawer(t *testing.T) {
+	room := newTestRoom(t, "undo-non-drawer")
+	drawer := addTestPlayer(room, "drawer")
+	guesser := addTestPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	placePixel(t, drawer, 1, 1)
+	HandleUndo(guesser)
+
+	if got := len(room.CanvasState); got != 1 {
+		t.Errorf("expected non-drawer undo to be ignored, canvas has %d ops", got)
+	}
+}
+
+func TestUnknownPixelTypeRejected(t *testing.T) {
+	room := newTestRoom(t, "pixel-unknown-type")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	HandlePixelDrawEnhanced(drawer, json.RawMessage(`{"type":"plcae","x":1,"y":1,"color":"#000000"}`))
+
+	msg, ok := drawerConn.waitFor("error", time.Second)
+	if !ok {
+		t.Fatal("expected an error for an unknown pixel type")
+	}
+	var data internal.ErrorData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("failed to decode error: %v", err)
+	}
+	if data.Code != "invalid_pixel_type" {
+		t.Errorf("expected invalid_pixel_type, got %q", data.Code)
+	}
+	if got := len(room.CanvasState); got != 0 {
+		t.Errorf("expected nothing stored on the canvas, got %d ops", got)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := len(guesserConn.messagesOfType("plcae")); got != 0 {
+		t.Errorf("expected the unknown type not to be rebroadcast, got %d", got)
+	}
+}
+
+func TestPixelColorAndBrushValidation(t *testing.T) {
+	cases := []struct {
+		name, payload string
+		wantCode      string // "" when the op is accepted
+	}{
+		{"hex color", `{"type":"place","x":1,"y":1,"color":"#A1b2C3","brush_size":3}`, ""},
+		{"batch hex color", `{"type":"batch_place","color":"#00ff00","pixels":[{"gridX":2,"gridY":2}]}`, ""},
+		{"named color", `{"type":"place","x":1,"y":1,"color":"red"}`, "invalid_color"},
+		{"short hex", `{"type":"place","x":1,"y":1,"color":"#fff"}`, "invalid_color"},
+		{"markup", `{"type":"batch_place","color":"<img src=x>","pixels":[{"gridX":2,"gridY":2}]}`, "invalid_color"},
+		{"missing color", `{"type":"place","x":1,"y":1}`, "invalid_color"},
+		{"brush too big", `{"type":"place","x":1,"y":1,"color":"#000000","brush_size":9}`, "invalid_brush_size"},
+		{"negative brush", `{"type":"place","x":1,"y":1,"color":"#000000","brush_size":-1}`, "invalid_brush_size"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			room := newTestRoom(t, "pixel-validation")
+			drawer, drawerConn := addConnectedPlayer(room, "drawer")
+			makeDrawer(room, drawer)
+
+			HandlePixelDrawEnhanced(drawer, json.RawMessage(c.payload))
+
+			if c.wantCode == "" {
+				if len(room.CanvasState) != 1 {
+					t.Fatalf("expected the op to be stored, canvas has %d ops", len(room.CanvasState))
+				}
+				if color := room.CanvasState[0].Color; color != strings.ToLower(color) {
+					t.Errorf("expected the color normalized to lowercase, got %q", color)
+				}
+				return
+			}
+			msg, ok := drawerConn.waitFor("error", time.Second)
+			if !ok {
+				t.Fatalf("expected a %s error", c.wantCode)
+			}
+			var data internal.ErrorData
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				t.Fatalf("failed to decode error: %v", err)
+			}
+			if data.Code != c.wantCode {
+				t.Errorf("expected %s, got %q", c.wantCode, data.Code)
+			}
+			if len(room.CanvasState) != 0 {
+				t.Errorf("expected nothing stored, canvas has %d ops", len(room.CanvasState))
+			}
+		})
+	}
+}
+
+func TestFillBroadcastsCoveredCells(t *testing.T) {
+	room := newTestRoom(t, "pixel-fill")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	HandlePixelDrawEnhanced(drawer, json.RawMessage(`{"type":"fill","x":0,"y":0,"color":"#00FF00"}`))
+
+	msg, ok := guesserConn.waitFor(string(internal.BatchPlace), time.Second)
+	if !ok {
+		t.Fatal("expected the fill to be broadcast as a batch_place")
+	}
+	var batch internal.PixelMessage
+	if err := json.Unmarshal(msg.Data, &batch); err != nil {
+		t.Fatalf("bad batch payload: %v", err)
+	}
+	if len(batch.Pixels) != internal.CanvasWidth*internal.CanvasHeight || batch.Color != "#00ff00" {
+		t.Errorf("expected every cell filled with #00ff00, got %d cells of %q", len(batch.Pixels), batch.Color)
+	}
+	if len(room.CanvasState) != 1 || room.CanvasState[0].Type != internal.BatchPlace {
+		t.Errorf("expected the fill stored as one batch, got %+v", room.CanvasState)
+	}
+
+	// Undo takes the whole fill back
+	HandleUndo(drawer)
+	if len(room.CanvasState) != 0 {
+		t.Errorf("expected undo to remove the fill, canvas has %d ops", len(room.CanvasState))
+	}
+}
+
+func TestSafeBroadcastToRoomExceptSkipsExcluded(t *testing.T) {
+	room := newTestRoom(t, "broadcast-except")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+
+	SafeBroadcastToRoomExcept(room, internal.Message[string]{Type: "ping", Data: "hi"}, drawer)
+	flushBroadcasts(t, room, guesserConn)
+	flushBroadcasts(t, room, drawerConn)
+
+	if got := len(guesserConn.messagesOfType("ping")); got != 1 {
+		t.Errorf("expected guesser to receive 1 ping, got %d", got)
+	}
+	if got := len(drawerConn.messagesOfType("ping")); got != 0 {
+		t.Errorf("expected excluded drawer to receive no ping, got %d", got)
+	}
+}
+
+func TestBroadcastGameStateRedactsForPendingGuessers(t *testing.T) {
+	room := newTestRoom(t, "state-redaction")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	solver, solverConn := addConnectedPlayer(room, "solver")
+	_, pendingConn := addConnectedPlayer(room, "pending")
+	makeDrawer(room, drawer)
+	room.CurrentIndex = 0
+	room.Word = "apple"
+	solver.HasGuessed = true
+	solver.TurnStartScore = 100
+	solver.Score = 250 // includes this turn's correct guess
+
+	BroadcastGameState(room)
+
+	solverFor := func(conn *fakeConn) internal.PlayerSnapshot {
+		t.Helper()
+		msg, ok := conn.waitFor("game_state_update", time.Second)
+		if !ok {
+			t.Fatal("expected a game_state_update")
+		}
+		var state internal.GameStateData
+		if err := json.Unmarshal(msg.Data, &state); err != nil {
+			t.Fatalf("failed to decode state: %v", err)
+		}
+		for _, p := range state.Players {
+			if p.ID == solver.Id {
+				return p
+			}
+		}
+		t.Fatal("solver missing from player list")
+		return internal.PlayerSnapshot{}
+	}
+
+	if seen := solverFor(solverConn); !seen.HasGuessed || seen.Score != 250 {
+		t.Errorf("expected a guesser who already solved it to see full state, got %+v", seen)
+	}
+	if seen := solverFor(pendingConn); seen.HasGuessed || seen.Score != 100 {
+		t.Errorf("expected a pending guesser to get a redacted player list with turn-start scores, got %+v", seen)
+	}
+}
+
+func TestBackgroundChangeOnlyByDrawer(t *testing.T) {
+	room := newTestRoom(t, "background-auth")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+	room.CanvasBackground = internal.DefaultCanvasBackground
+
+	HandleBackgroundChange(guesser, json.RawMessage(`{"color":"#000000","grid":true}`))
+	HandleBackgroundChange(drawer, json.RawMessage(`{"color":"black","grid":true}`))
+	if room.CanvasBackground != internal.DefaultCanvasBackground {
+		t.Fatalf("expected background unchanged, got %+v", room.CanvasBackground)
+	}
+
+	HandleBackgroundChange(drawer, json.RawMessage(`{"color":"#112233","grid":true}`))
+	want := internal.CanvasBackground{Color: "#112233", Grid: true}
+	if room.CanvasBackground != want {
+		t.Fatalf("expected drawer's background %+v, got %+v", want, room.CanvasBackground)
+	}
+	if _, ok := guesserConn.waitFor("background_changed", time.Second); !ok {
+		t.Fatal("expected background_changed to be broadcast")
+	}
+	if got := len(guesserConn.messagesOfType("background_changed")); got != 1 {
+		t.Errorf("expected only the drawer's change to be broadcast, got %d", got)
+	}
+}
+
+func TestBackgroundIncludedInGameState(t *testing.T) {
+	room := newTestRoom(t, "background-state")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+	room.CurrentIndex = 0
+	room.Word = "apple"
+
+	HandleBackgroundChange(drawer, json.RawMessage(`{"color":"#abcdef","grid":true}`))
+	BroadcastGameState(room)
+
+	msg, ok := guesserConn.waitFor("game_state_update", time.Second)
+	if !ok {
+		t.Fatal("expected a game_state_update")
+	}
+	var state internal.GameStateData
+	if err := json.Unmarshal(msg.Data, &state); err != nil {
+		t.Fatalf("failed to decode state: %v", err)
+	}
+	if want := (internal.CanvasBackground{Color: "#abcdef", Grid: true}); state.CanvasBackground != want {
+		t.Errorf("expected background %+v in state, got %+v", want, state.CanvasBackground)
+	}
+}
+
+func TestBroadcastGameStateCoalescesRapidCalls(t *testing.T) {
+	room := newTestRoom(t, "state-coalesce")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+	room.CurrentIndex = 0
+	room.Word = "apple"
+
+	for range 10 {
+		BroadcastGameState(room)
+	}
+	room.Mu.Lock()
+	room.RoundNumber = 2
+	room.Mu.Unlock()
+
+	msg, ok := guesserConn.waitFor("game_state_update", time.Second)
+	if !ok {
+		t.Fatal("expected a game_state_update")
+	}
+	time.Sleep(2 * GameStateBroadcastWindow)
+	if got := len(guesserConn.messagesOfType("game_state_update")); got != 1 {
+		t.Errorf("expected rapid calls to produce one broadcast, got %d", got)
+	}
+
+	var state internal.GameStateData
+	if err := json.Unmarshal(msg.Data, &state); err != nil {
+		t.Fatalf("failed to decode state: %v", err)
+	}
+	if state.RoundNumber != 2 {
+		t.Errorf("expected the broadcast to carry the latest state, got round %d", state.RoundNumber)
+	}
+
+	BroadcastGameState(room)
+	time.Sleep(2 * GameStateBroadcastWindow)
+	if got := len(guesserConn.messagesOfType("game_state_update")); got != 2 {
+		t.Errorf("expected a later call to broadcast again, got %d total", got)
+	}
+}
+
+func TestCanvasCompactedPastOpLimit(t *testing.T) {
+	prev := MaxCanvasOpsPerRound
+	MaxCanvasOpsPerRound = 10
+	t.Cleanup(func() { MaxCanvasOpsPerRound = prev })
+	withPixelBroadcastWindow(t, 0)
+
+	room := newTestRoom(t, "canvas-compact")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	for i := range 15 {
+		placePixel(t, drawer, i%5, i/5)
+	}
+	placePixel(t, drawer, 0, 0) // redraw over an existing cell
+
+	room.Mu.RLock()
+	stored := len(room.CanvasState)
+	cells := internal.RenderCanvas(room.CanvasState)
+	room.Mu.RUnlock()
+	if stored > MaxCanvasOpsPerRound {
+		t.Errorf("expected the canvas to be compacted to at most %d ops, got %d", MaxCanvasOpsPerRound, stored)
+	}
+	if len(cells) != 15 {
+		t.Errorf("expected all 15 drawn cells to survive compaction, got %d", len(cells))
+	}
+
+	// Erasing still works on compacted pixels
+	HandlePixelDrawEnhanced(drawer, json.RawMessage(`{"type":"erase","x":1,"y":0,"timestamp":2}`))
+	room.Mu.RLock()
+	_, stillThere := internal.RenderCanvas(room.CanvasState)[internal.GridPosition{GridX: 1, GridY: 0}]
+	room.Mu.RUnlock()
+	if stillThere {
+		t.Error("expected erase to remove a pixel stored in a compacted batch")
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	for _, msg := range guesserConn.messages() {
+		if msg.Type == string(internal.BatchPlace) {
+			t.Errorf("expected compaction to broadcast nothing extra, got %s", msg.Type)
+		}
+	}
+}
+
+// startEmptyCanvasTurn puts drawer mid-turn with the empty-canvas skip armed
+func startEmptyCanvasTurn(t *testing.T, id string) (*internal.Room, *internal.Player, *fakeConn) {
+	t.Helper()
+	prev := EmptyCanvasSkipAfter
+	EmptyCanvasSkipAfter = 50 * time.Millisecond
+	t.Cleanup(func() { EmptyCanvasSkipAfter = prev })
+
+	room := newTestRoom(t, id)
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	room.HasGameStarted = true
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	room.DrawingStarted = true
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+	return room, drawer, guesserConn
+}
+
+func TestClearedCanvasLeftEmptySkipsDrawer(t *testing.T) {
+	room, drawer, guesserConn := startEmptyCanvasTurn(t, "empty-canvas-skip")
+
+	placePixel(t, drawer, 1, 1)
+	ClearCanvas(room, drawer)
+
+	msg, ok := guesserConn.waitFor("drawer_skipped", time.Second)
+	if !ok {
+		t.Fatal("expected the drawer to be skipped after leaving the canvas empty")
+	}
+	var data map[string]string
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad drawer_skipped payload: %v", err)
+	}
+	if data["player_id"] != drawer.Id || data["reason"] != "empty_canvas" {
+		t.Errorf("unexpected drawer_skipped payload %v", data)
+	}
+	if _, ok := guesserConn.waitFor("waiting_phase", time.Second); !ok {
+		t.Error("expected the game to move on to the next turn")
+	}
+	room.Mu.RLock()
+	recorded := len(room.RoundStats)
+	room.Mu.RUnlock()
+	if recorded != 0 {
+		t.Errorf("expected a skipped turn not to be recorded as a completed word, got %d", recorded)
+	}
+}
+
+func TestRedrawAfterClearKeepsTurn(t *testing.T) {
+	room, drawer, guesserConn := startEmptyCanvasTurn(t, "empty-canvas-redraw")
+
+	placePixel(t, drawer, 1, 1)
+	ClearCanvas(room, drawer)
+	placePixel(t, drawer, 2, 2)
+
+	time.Sleep(3 * EmptyCanvasSkipAfter)
+	if got := len(guesserConn.messagesOfType("drawer_skipped")); got != 0 {
+		t.Fatalf("expected no skip once the drawer redrew, got %d", got)
+	}
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Phase != internal.PhaseDrawing || room.Current != drawer {
+		t.Errorf("expected the drawer to keep the turn, got phase=%s", room.Phase)
+	}
+}
+
+func TestErasingEveryPixelArmsEmptyCanvasSkip(t *testing.T) {
+	room, _, _ := startEmptyCanvasTurn(t, "empty-canvas-erase")
+	drawer := room.Current
+
+	placePixel(t, drawer, 1, 1)
+	room.Mu.RLock()
+	armed := !room.CanvasEmptySince.IsZero()
+	room.Mu.RUnlock()
+	if armed {
+		t.Fatal("expected placing a pixel to disarm the empty-canvas skip")
+	}
+
+	HandlePixelDrawEnhanced(drawer, json.RawMessage(`{"type":"erase","x":1,"y":1,"timestamp":2}`))
+	room.Mu.RLock()
+	armed = !room.CanvasEmptySince.IsZero()
+	room.Mu.RUnlock()
+	if !armed {
+		t.Error("expected erasing the only pixel to arm the empty-canvas skip")
+	}
+}
+
+func TestGameStateReportsRemainingMilliseconds(t *testing.T) {
+	room := newTestRoom(t, "state-remaining-ms")
+	_, conn := addConnectedPlayer(room, "alice")
+	room.Timer = &internal.GameTimer{StartTime: time.Now(), Duration: 30 * time.Second, IsActive: true}
+
+	broadcastGameStateNow(room)
+
+	msg, ok := conn.waitFor("game_state_update", time.Second)
+	if !ok {
+		t.Fatal("expected a game_state_update")
+	}
+	var state internal.GameStateData
+	if err := json.Unmarshal(msg.Data, &state); err != nil {
+		t.Fatalf("bad game_state_update payload: %v", err)
+	}
+	if state.TimeRemaining <= 29000 || state.TimeRemaining > 30000 {
+		t.Errorf("expected just under 30000ms remaining, got %d", state.TimeRemaining)
+	}
+}
+
+func TestRapidPixelsBroadcastAsBatches(t *testing.T) {
+	withPixelBroadcastWindow(t, 50*time.Millisecond)
+	room := newTestRoom(t, "pixel-batches")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	const pixels = 100
+	for i := range pixels {
+		placePixel(t, drawer, i%internal.CanvasWidth, i/internal.CanvasWidth)
+	}
+	room.Mu.RLock()
+	stored := len(room.CanvasState)
+	room.Mu.RUnlock()
+	if stored != pixels {
+		t.Errorf("expected the canvas to keep all %d pixels, got %d ops", pixels, stored)
+	}
+	HandlePixelDrawEnhanced(drawer, json.RawMessage(`{"type":"erase","x":0,"y":0,"timestamp":2}`))
+
+	waitForCount(t, guesserConn, string(internal.ErasePixel), 1)
+	var broadcasts, covered int
+	for _, msg := range guesserConn.messages() {
+		switch msg.Type {
+		case string(internal.PixelPlace):
+			broadcasts++
+			covered++
+		case string(internal.BatchPlace):
+			var batch internal.PixelMessage
+			if err := json.Unmarshal(msg.Data, &batch); err != nil {
+				t.Fatalf("bad batch payload: %v", err)
+			}
+			broadcasts++
+			covered += len(batch.Pixels)
+		case string(internal.ErasePixel):
+			if covered != pixels {
+				t.Errorf("expected all %d pixels to arrive before the erase, got %d", pixels, covered)
+			}
+		}
+	}
+	if broadcasts == 0 || broadcasts > pixels/10 {
+		t.Errorf("expected %d rapid pixels to go out in a few batches, got %d broadcasts", pixels, broadcasts)
+	}
+	if got := len(drawerConn.messagesOfType(string(internal.BatchPlace))); got != 0 {
+		t.Errorf("expected the drawer not to be sent their own pixels, got %d batches", got)
+	}
+}
+
+func TestBufferedPixelsFlushAfterWindow(t *testing.T) {
+	withPixelBroadcastWindow(t, 20*time.Millisecond)
+	room := newTestRoom(t, "pixel-flush")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	makeDrawer(room, drawer)
+
+	placePixel(t, drawer, 1, 1)
+	placePixel(t, drawer, 2, 1)
+
+	batches := waitForCount(t, guesserConn, string(internal.BatchPlace), 1)
+	var batch internal.PixelMessage
+	if err := json.Unmarshal(batches[0].Data, &batch); err != nil {
+		t.Fatalf("bad batch payload: %v", err)
+	}
+	if len(batch.Pixels) != 2 || batch.Color != "#000000" {
+		t.Errorf("expected one batch of both pixels, got %+v", batch)
+	}
+}
+
+// startAFKTurn starts a real drawing phase whose AFK skip fires after ~50ms
+func startAFKTurn(t *testing.T, id string) (*internal.Room, *internal.Player, *fakeConn) {
+	t.Helper()
+	prev := AFKDrawerSkipFraction
+	AFKDrawerSkipFraction = 0.05
+	t.Cleanup(func() { AFKDrawerSkipFraction = prev })
+
+	room := newTestRoom(t, id)
+	room.Config.DrawTime = time.Second
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	room.HasGameStarted = true
+	room.PlayerOrder = []string{"drawer", "guesser"}
+	room.Current = drawer
+	room.Word = "apple"
+	StartDrawingPhase(room)
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+	return room, drawer, guesserConn
+}
+
+func TestAFKDrawerSkipped(t *testing.T) {
+	room, drawer, guesserConn := startAFKTurn(t, "afk-skip")
+
+	msg, ok := guesserConn.waitFor("afk_skip", time.Second)
+	if !ok {
+		t.Fatal("expected a drawer who never drew to be skipped")
+	}
+	var data map[string]any
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad afk_skip payload: %v", err)
+	}
+	if data["player_id"] != drawer.Id {
+		t.Errorf("expected afk_skip for %s, got %v", drawer.Id, data)
+	}
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Current == drawer && room.Phase == internal.PhaseDrawing {
+		t.Error("expected the turn to move on after the AFK skip")
+	}
+	if len(room.RoundStats) != 0 {
+		t.Errorf("expected a skipped turn not to be recorded, got %+v", room.RoundStats)
+	}
+}
+
+func TestDrawerWhoDrawsIsNotAFK(t *testing.T) {
+	room, drawer, guesserConn := startAFKTurn(t, "afk-drew")
+
+	placePixel(t, drawer, 3, 3)
+
+	if _, ok := guesserConn.waitFor("afk_skip", 150*time.Millisecond); ok {
+		t.Fatal("expected no AFK skip once the drawer drew a pixel")
+	}
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Current != drawer || room.Phase != internal.PhaseDrawing {
+		t.Errorf("expected the drawer's turn to go on, got phase=%s", room.Phase)
+	}
+}
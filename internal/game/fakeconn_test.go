@@ -0,0 +1,113 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// fakeConn is an in-memory internal.Conn that records every frame written to
+// it and serves queued frames to ReadMessage
+type fakeConn struct {
+	mu      sync.Mutex
+	written []internal.Message[json.RawMessage]
+	notify  chan struct{}
+	reads   chan []byte
+	closed  bool
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{
+		notify: make(chan struct{}, 1),
+		reads:  make(chan []byte, 16),
+	}
+}
+
+func (c *fakeConn) WriteJSON(v any) error {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var msg internal.Message[json.RawMessage]
+	if err := json.Unmarshal(raw, &msg); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.closed {
+		return errors.New("fakeConn: write on closed connection")
+	}
+	c.written = append(c.written, msg)
+
+	select {
+	case c.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *fakeConn) ReadMessage() (int, []byte, error) {
+	frame, ok := <-c.reads
+	if !ok {
+		return 0, nil, io.EOF
+	}
+	return 1, frame, nil
+}
+
+func (c *fakeConn) SetReadDeadline(time.Time) error  { return nil }
+func (c *fakeConn) SetWriteDeadline(time.Time) error { return nil }
+
+func (c *fakeConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.closed {
+		c.closed = true
+		close(c.reads)
+	}
+	return nil
+}
+
+// send queues a client frame for ReadMessage
+func (c *fakeConn) send(msgType string, data any) {
+	raw, _ := json.Marshal(data)
+	frame, _ := json.Marshal(internal.Message[json.RawMessage]{Type: msgType, Data: raw})
+	c.reads <- frame
+}
+
+// messages returns a copy of every frame written so far
+func (c *fakeConn) messages() []internal.Message[json.RawMessage] {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]internal.Message[json.RawMessage](nil), c.written...)
+}
+
+// messagesOfType returns the written frames with the given type, in order
+func (c *fakeConn) messagesOfType(msgType string) []internal.Message[json.RawMessage] {
+	var out []internal.Message[json.RawMessage]
+	for _, msg := range c.messages() {
+		if msg.Type == msgType {
+			out = append(out, msg)
+		}
+	}
+	return out
+}
+
+// waitFor blocks until a frame of msgType has been written or timeout elapses
+func (c *fakeConn) waitFor(msgType string, timeout time.Duration) (internal.Message[json.RawMessage], bool) {
+	deadline := time.After(timeout)
+	for {
+		if found := c.messagesOfType(msgType); len(found) > 0 {
+			return found[0], true
+		}
+		select {
+		case <-c.notify:
+		case <-deadline:
+			return internal.Message[json.RawMessage]{}, false
+		}
+	}
+}
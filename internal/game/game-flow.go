@@ -1,9 +1,12 @@
 package game
 
 import (
+	"cmp"
 	"fmt"
 	"log"
+	"math/rand"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/scythe504/skribblr-backend/internal"
@@ -14,6 +17,9 @@ import (
 // GAME FLOW - ROUND MANAGEMENT
 // =============================================================================
 
+// resultsDisplayDuration is how long final results show before the lobby reopens
+const resultsDisplayDuration = 30 * time.Second
+
 // StartWaitingPhase shows next drawer countdown (10 seconds)
 func StartWaitingPhase(room *internal.Room) {
 	log.Printf("[StartWaitingPhase] Room %s: Function called", room.Id)
@@ -25,7 +31,7 @@ func StartWaitingPhase(room *internal.Room) {
 
 	// 1. Set phase
 	log.Printf("[StartWaitingPhase] Room %s: Setting phase from %s to waiting", room.Id, room.Phase)
-	room.Phase = internal.PhaseWaiting
+	setPhase(room, internal.PhaseWaiting)
 	log.Printf("[StartWaitingPhase] Room %s: Phase set to %s", room.Id, room.Phase)
 
 	// 2. Ensure CurrentIndex is valid
@@ -75,14 +81,36 @@ func StartWaitingPhase(room *internal.Room) {
 	log.Printf("[StartWaitingPhase] Room %s: Clearing round-level data - CorrectGuessers length=%d, CanvasState length=%d",
 		room.Id, len(room.CorrectGuessers), len(room.CanvasState))
 	room.CorrectGuessers = make([]internal.PlayerGuess, 0)
+	room.CreditedGuessers = make(map[string]bool)
 	room.CanvasState = make([]internal.PixelMessage, 0)
+	room.CanvasBackground = internal.DefaultCanvasBackground
+	resetCanvasHistory(room)
+	room.DrawingStarted = false
+	room.RoundRecorded = false
 	log.Printf("[StartWaitingPhase] Room %s: Cleared CorrectGuessers and CanvasState", room.Id)
 
+	// 6. Tell clients to wipe the last turn's drawing. Queued under the lock
+	// so it lands after any of that turn's draw ops still in the queue.
+	if ClearCanvasOnNewTurn {
+		queueBroadcastLocked(room, internal.Message[any]{
+			Type: "canvas_cleared",
+			Data: map[string]any{
+				"room_id":           room.Id,
+				"reason":            "new_turn",
+				"canvas_state":      room.CanvasState,
+				"canvas_background": room.CanvasBackground,
+				"round_number":      room.RoundNumber,
+				"timestamp":         time.Now().UnixMilli(),
+			},
+		}, nil)
+	}
+
 	// Snapshot values to send outside lock
 	roomID := room.Id
 	drawerID := currentDrawer.Id
 	drawerName := currentDrawer.Username
 	roundNum := room.RoundNumber
+	waitDuration := waitingPhaseDuration(room)
 	log.Printf("[StartWaitingPhase] Room %s: Snapshotted values - drawerID=%s, drawerName=%s, roundNum=%d",
 		roomID, drawerID, drawerName, roundNum)
 
@@ -103,12 +131,12 @@ func StartWaitingPhase(room *internal.Room) {
 				"id":       drawerID,
 				"username": drawerName,
 			},
-			"phase":          "waiting",
-			"time_remaining": 10, // displayed seconds
-			"round_number":   roundNum,
+			"phase":             "waiting",
+			"time_remaining_ms": waitDuration.Milliseconds(), // same as the timer below
+			"round_number":      roundNum,
 		},
 	}
-	log.Printf("[StartWaitingPhase] Room %s: Created waiting_phase message with time_remaining=10", roomID)
+	log.Printf("[StartWaitingPhase] Room %s: Created waiting_phase message with time_remaining=%v", roomID, waitDuration)
 
 	log.Printf("[StartWaitingPhase] Room %s: Entering waiting phase. Drawer=%s (%s), round=%d",
 		roomID, drawerID, drawerName, roundNum)
@@ -119,8 +147,8 @@ func StartWaitingPhase(room *internal.Room) {
 
 	// Start a short timer to move to word selection
 	// Use StartPhaseTimer which we assume correctly distinguishes cancel vs natural expiry
-	log.Printf("[StartWaitingPhase] Room %s: Starting 15-second phase timer for word selection transition", roomID)
-	StartPhaseTimer(room, 15*time.Second, func() {
+	log.Printf("[StartWaitingPhase] Room %s: Starting %v phase timer for word selection transition", roomID, waitDuration)
+	StartPhaseTimer(room, waitDuration, func() {
 		log.Printf("[StartWaitingPhase] Room %s: Phase timer expired, starting goroutine for word selection", roomID)
 		// call next phase in a goroutine to avoid blocking the timer goroutine
 		StartWordSelection(room)
@@ -144,11 +172,12 @@ func StartWordSelection(room *internal.Room) {
 		return
 	}
 
-	// generate choices (assumes utils.GenerateWordChoices exists and is safe)
-	words := utils.GenerateWordChoices()
+	// generate choices in the room's language and difficulty mix
+	words := roomWordChoices(room)
 	log.Printf("[StartWordSelection] room=%s: generated word choices=%v", room.Id, words)
 
 	room.WordChoices = words
+	room.WordsRefreshed = false
 
 	// capture the drawer pointer & room id for use outside lock
 	currentDrawer := room.Current
@@ -158,14 +187,49 @@ func StartWordSelection(room *internal.Room) {
 	log.Printf("[StartWordSelection] room=%s: released lock after snapshot", roomID)
 	// --- end critical section ---
 
+	// Start selection timer. If the drawer hasn't selected by timeout, auto-select first word.
+	log.Printf("[StartWordSelection] room=%s: starting selection timer (%v)", roomID, WordSelectionTimeout)
+	StartPhaseTimer(room, WordSelectionTimeout, func() {
+		log.Printf("[StartWordSelection.Timer] room=%s: timer callback triggered", roomID)
+
+		// In the timer callback we'll attempt an idempotent auto-selection.
+		// Acquire lock to check whether the word is already set (someone may have selected it).
+		room.Mu.Lock()
+		alreadyChosen := room.Word != ""
+		choicesCopy := append([]internal.Word(nil), room.WordChoices...) // snapshot choices
+		room.Mu.Unlock()
+
+		if alreadyChosen {
+			log.Printf("[StartWordSelection.Timer] room=%s: word already chosen before timer expiry; skipping auto-select", roomID)
+			return
+		}
+		if len(choicesCopy) == 0 {
+			log.Printf("[StartWordSelection.Timer] room=%s: no choices available for auto-select", roomID)
+			return
+		}
+
+		autoWord := choicesCopy[0].Word
+		log.Printf("[StartWordSelection.Timer] room=%s: auto-selecting word '%s' for drawer %s (%s)",
+			roomID, autoWord, currentDrawer.Id, currentDrawer.Username)
+
+		// call HandleWordSelection asynchronously
+		go HandleWordSelection(currentDrawer, autoWord)
+	})
+
+	// Report what is actually left on the selection timer
+	room.Mu.RLock()
+	timeLimit := room.RemainingTime()
+	room.Mu.RUnlock()
+
 	// Prepare word selection message for the drawer
 	wordSelectionMessage := internal.Message[internal.WordSelectionData]{
 		Type: "word_selection",
 		Data: internal.WordSelectionData{
-			Message:   "Please select a word to draw",
-			RoomId:    roomID,
-			Choices:   words,
-			TimeLimit: 15,
+			Message:    "Please select a word to draw",
+			RoomId:     roomID,
+			Choices:    words,
+			TimeLimit:  timeLimit,
+			CanRefresh: true,
 		},
 	}
 
@@ -179,7 +243,7 @@ func StartWordSelection(room *internal.Room) {
 			roomID, currentDrawer.Id, currentDrawer.Username, err)
 
 		// run selection asynchronously to avoid blocking
-		go HandleWordSelection(currentDrawer, words[0])
+		go HandleWordSelection(currentDrawer, words[0].Word)
 		return
 	}
 
@@ -190,45 +254,61 @@ func StartWordSelection(room *internal.Room) {
 	waitingMessage := internal.Message[any]{
 		Type: "waiting_for_word",
 		Data: map[string]any{
-			"message":        fmt.Sprintf("Waiting for %s to select a word...", currentDrawer.Username),
-			"current_drawer": currentDrawer.Username,
-			"time_remaining": 15,
+			"message":           fmt.Sprintf("Waiting for %s to select a word...", currentDrawer.Username),
+			"current_drawer":    currentDrawer.Username,
+			"time_remaining_ms": timeLimit,
 		},
 	}
-	go func() {
-		log.Printf("[StartWordSelection] room=%s: broadcasting waiting message to all except drawer %s (%s)",
-			roomID, currentDrawer.Id, currentDrawer.Username)
-		SafeBroadcastToRoomExcept(room, waitingMessage, currentDrawer)
-	}()
+	log.Printf("[StartWordSelection] room=%s: broadcasting waiting message to all except drawer %s (%s)",
+		roomID, currentDrawer.Id, currentDrawer.Username)
+	queueBroadcast(room, waitingMessage, currentDrawer)
+}
 
-	// Start selection timer. If the drawer hasn't selected by timeout, auto-select first word.
-	log.Printf("[StartWordSelection] room=%s: starting selection timer (15s)", roomID)
-	StartPhaseTimer(room, 15*time.Second, func() {
-		log.Printf("[StartWordSelection.Timer] room=%s: timer callback triggered", roomID)
+// ResendWordSelection re-sends the pending word choices to a drawer whose
+// connection was replaced mid-selection (e.g. after reconnecting). It does not
+// touch the selection timer, so the original auto-select deadline still applies.
+func ResendWordSelection(player *internal.Player) bool {
+	room := player.Room
+	if room == nil {
+		log.Printf("[ResendWordSelection] player %s: no room reference, aborting", player.Id)
+		return false
+	}
 
-		// In the timer callback we'll attempt an idempotent auto-selection.
-		// Acquire lock to check whether the word is already set (someone may have selected it).
-		room.Mu.Lock()
-		alreadyChosen := room.Word != ""
-		choicesCopy := append([]string(nil), room.WordChoices...) // snapshot choices
-		room.Mu.Unlock()
+	room.Mu.RLock()
+	selecting := room.Phase == internal.PhaseWaiting &&
+		room.Current != nil && room.Current.Id == player.Id &&
+		room.Word == "" && len(room.WordChoices) > 0
+	if !selecting {
+		room.Mu.RUnlock()
+		return false
+	}
 
-		if alreadyChosen {
-			log.Printf("[StartWordSelection.Timer] room=%s: word already chosen before timer expiry; skipping auto-select", roomID)
-			return
-		}
-		if len(choicesCopy) == 0 {
-			log.Printf("[StartWordSelection.Timer] room=%s: no choices available for auto-select", roomID)
-			return
-		}
+	choices := append([]internal.Word(nil), room.WordChoices...)
+	timeLimit := room.RemainingTime()
+	canRefresh := !room.WordsRefreshed
+	roomID := room.Id
+	room.Mu.RUnlock()
 
-		autoWord := choicesCopy[0]
-		log.Printf("[StartWordSelection.Timer] room=%s: auto-selecting word '%s' for drawer %s (%s)",
-			roomID, autoWord, currentDrawer.Id, currentDrawer.Username)
+	wordSelectionMessage := internal.Message[internal.WordSelectionData]{
+		Type: "word_selection",
+		Data: internal.WordSelectionData{
+			Message:    "Please select a word to draw",
+			RoomId:     roomID,
+			Choices:    choices,
+			TimeLimit:  timeLimit,
+			CanRefresh: canRefresh,
+		},
+	}
 
-		// call HandleWordSelection asynchronously
-		go HandleWordSelection(currentDrawer, autoWord)
-	})
+	if err := player.SafeWriteJSON(wordSelectionMessage); err != nil {
+		log.Printf("[ResendWordSelection] room=%s: failed to resend choices to drawer %s (%s): %v",
+			roomID, player.Id, player.Username, err)
+		return false
+	}
+
+	log.Printf("[ResendWordSelection] room=%s: resent word choices to drawer %s (%s)",
+		roomID, player.Id, player.Username)
+	return true
 }
 
 // HandleWordSelection processes drawer's word choice
@@ -257,17 +337,28 @@ func HandleWordSelection(player *internal.Player, selectedWord string) {
 		return
 	}
 
-	// 2. Verify selectedWord exists in room.WordChoices
-	if !slices.Contains(room.WordChoices, selectedWord) {
+	// 2. Verify selectedWord exists in room.WordChoices, ignoring case and
+	// surrounding spaces; the offered spelling is what gets stored
+	choiceIdx := slices.IndexFunc(room.WordChoices, func(w internal.Word) bool {
+		return strings.EqualFold(w.Word, strings.TrimSpace(selectedWord))
+	})
+	if choiceIdx < 0 {
 		log.Printf("[HandleWordSelection] room=%s player=%s chose invalid word: %q",
 			room.Id, player.Id, selectedWord)
 		room.Mu.Unlock()
 		return
 	}
+	selectedWord = room.WordChoices[choiceIdx].Word
 
-	// 3. Set room.Word = selectedWord and clear choices (all under lock)
+	// 3. Set room.Word to the offered word and clear choices (all under lock)
 	room.Word = selectedWord
-	room.WordChoices = make([]string, 0)
+	room.WordEmoji = room.WordChoices[choiceIdx].Emoji
+	room.WordDifficulty = room.WordChoices[choiceIdx].Difficult
+	room.WordChoices = make([]internal.Word, 0)
+	if room.UsedWords == nil {
+		room.UsedWords = make(map[string]bool)
+	}
+	room.UsedWords[strings.ToLower(selectedWord)] = true
 	log.Printf("[HandleWordSelection] room=%s: player=%s selected word '%s'", room.Id, player.Id, selectedWord)
 
 	// Snapshot minimal info for later use (if needed) before unlock
@@ -284,6 +375,89 @@ func HandleWordSelection(player *internal.Player, selectedWord string) {
 	// done
 }
 
+// HandleRandomWordSelection picks one of the drawer's offered words at random
+// and selects it through HandleWordSelection, so the same validation and
+// idempotency apply. Disabled unless RandomWordEnabled is set.
+func HandleRandomWordSelection(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleRandomWordSelection] player %s: no room reference, aborting", player.Id)
+		return
+	}
+	if !RandomWordEnabled {
+		SendErrorToPlayer(player, "random_word_disabled", "Random word selection is turned off")
+		return
+	}
+
+	room.Mu.RLock()
+	// 1. Only the current drawer, while still choosing
+	if room.Current == nil || player.Id != room.Current.Id {
+		log.Printf("[HandleRandomWordSelection] room=%s player=%s (%s) is not current drawer, ignoring",
+			room.Id, player.Id, player.Username)
+		room.Mu.RUnlock()
+		return
+	}
+	if room.Word != "" || len(room.WordChoices) == 0 {
+		log.Printf("[HandleRandomWordSelection] room=%s: no word selection in progress, ignoring request by %s",
+			room.Id, player.Id)
+		room.Mu.RUnlock()
+		return
+	}
+
+	// 2. Pick from the offered choices
+	word := room.WordChoices[rand.Intn(len(room.WordChoices))].Word
+	roomID := room.Id
+	room.Mu.RUnlock()
+
+	log.Printf("[HandleRandomWordSelection] room=%s: drawer %s asked for a random word, picked '%s'",
+		roomID, player.Id, word)
+	// 3. Select it; a selection that lands first makes this a no-op
+	HandleWordSelection(player, word)
+}
+
+// HandleRefreshWords swaps the drawer's three word choices for new ones,
+// once per turn and only before a word is chosen. The new choices go to the
+// drawer alone; the selection timer keeps running.
+func HandleRefreshWords(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleRefreshWords] player %s: no room reference, aborting", player.Id)
+		return
+	}
+
+	room.Mu.Lock()
+	// 1. Only the current drawer, while still choosing
+	if room.Current == nil || player.Id != room.Current.Id {
+		log.Printf("[HandleRefreshWords] room=%s player=%s (%s) is not current drawer, ignoring",
+			room.Id, player.Id, player.Username)
+		room.Mu.Unlock()
+		return
+	}
+	if room.Phase != internal.PhaseWaiting || room.Word != "" || len(room.WordChoices) == 0 {
+		log.Printf("[HandleRefreshWords] room=%s: no word selection in progress, ignoring request by %s",
+			room.Id, player.Id)
+		room.Mu.Unlock()
+		return
+	}
+	// 2. One refresh per turn
+	if room.WordsRefreshed {
+		room.Mu.Unlock()
+		log.Printf("[HandleRefreshWords] room=%s: drawer %s already refreshed this turn", room.Id, player.Id)
+		SendErrorToPlayer(player, "refresh_used", "You can only refresh your words once per turn")
+		return
+	}
+
+	// 3. Replace the choices; a timeout auto-select picks from the new ones
+	room.WordChoices = roomWordChoices(room)
+	room.WordsRefreshed = true
+	log.Printf("[HandleRefreshWords] room=%s: drawer %s refreshed word choices=%v",
+		room.Id, player.Id, room.WordChoices)
+	room.Mu.Unlock()
+
+	// 4. Send them privately, with the time left on the selection timer
+	ResendWordSelection(player)
+}
+
 // StartDrawingPhase begins main drawing/guessing gameplay (75 seconds)
 func StartDrawingPhase(room *internal.Room) {
 	if room == nil {
@@ -306,23 +480,44 @@ func StartDrawingPhase(room *internal.Room) {
 		room.Mu.Unlock()
 		return
 	}
+	// a racing manual selection and auto-select must not start the turn twice
+	if room.DrawingStarted {
+		log.Printf("[StartDrawingPhase] room=%s: drawing already started this turn, ignoring", room.Id)
+		room.Mu.Unlock()
+		return
+	}
+	room.DrawingStarted = true
+	drawDuration := drawingPhaseDuration(room)
+	resetTimeWarnings(room, drawDuration)
 
 	// 1. Set phase
-	room.Phase = internal.PhaseDrawing
+	setPhase(room, internal.PhaseDrawing)
 	log.Printf("[StartDrawingPhase] room=%s: phase set to drawing", room.Id)
+	// The turn starts on a blank canvas, which arms the empty-canvas skip
+	room.CanvasEmptySince = time.Time{}
+	trackEmptyCanvas(room)
+	// Nothing drawn yet this turn; arms the AFK drawer skip below
+	room.HasDrawn = false
+	drawingStartedAt := room.PhaseChangedAt
 
 	// 2. Allow current drawer to draw
 	room.Current.CanDraw = true
 	log.Printf("[StartDrawingPhase] room=%s: drawer=%s can now draw", room.Id, room.Current.Id)
 
-	// 3. Clear previous correct guessers
+	// 3. Clear previous correct guessers, their credits and skip votes
 	room.CorrectGuessers = make([]internal.PlayerGuess, 0)
+	room.CreditedGuessers = make(map[string]bool)
+	room.SkipVotes = make(map[string]bool)
 	log.Printf("[StartDrawingPhase] room=%s: cleared previous correct guessers", room.Id)
 
-	// 4. Reset HasGuessed for all players
+	// 4. Reset HasGuessed for all players and freeze the scores guessers see
+	// until the reveal
 	for _, p := range room.Players {
 		if p != nil {
 			p.HasGuessed = false
+			p.TurnStartScore = p.Score
+			p.TurnStartCorrect = p.CorrectGuesses
+			p.TurnStartGuesses = p.TotalGuesses
 		}
 	}
 	log.Printf("[StartDrawingPhase] room=%s: reset HasGuessed for all players", room.Id)
@@ -331,8 +526,24 @@ func StartDrawingPhase(room *internal.Room) {
 	roomID := room.Id
 	drawer := room.Current     // pointer to drawer player
 	wordForDrawer := room.Word // full word (private to drawer)
-	timeLimit := int64(internal.DrawingPhaseDuration.Seconds())
+	timeLimit := drawDuration.Milliseconds()
 	masked := utils.GetMaskedWord(room.Word)
+	emoji := room.WordEmoji
+	difficulty := guesserWordDifficulty(room)
+	drawerDifficulty := room.WordDifficulty
+	progress := guessProgress(room)
+	roundStartMessage := internal.Message[any]{
+		Type: "round_start",
+		Data: map[string]any{
+			"room_id":        roomID,
+			"round_number":   room.RoundNumber,
+			"max_rounds":     room.MaxRounds,
+			"turn_number":    room.CurrentIndex + 1,
+			"turns_in_round": len(room.PlayerOrder),
+			"current_drawer": map[string]string{"id": drawer.Id, "username": drawer.Username},
+			"time_limit_ms":  timeLimit,
+		},
+	}
 
 	room.Mu.Unlock()
 	log.Printf("[StartDrawingPhase] room=%s: released lock after setup", roomID)
@@ -342,7 +553,7 @@ func StartDrawingPhase(room *internal.Room) {
 		roomID, drawer.Id, masked)
 
 	// 5. Start the phase timer - on expiry, decide next flow.
-	StartPhaseTimer(room, internal.DrawingPhaseDuration, func() {
+	StartPhaseTimer(room, drawDuration, func() {
 		// Timer callback: check whether everyone guessed; perform transition in its own goroutine.
 		go func() {
 			log.Printf("[StartDrawingPhase.Timer] room=%s: timer callback triggered", roomID)
@@ -359,47 +570,144 @@ func StartDrawingPhase(room *internal.Room) {
 			}
 		}()
 	})
-	log.Printf("[StartDrawingPhase] room=%s: phase timer started (%ds)", roomID, timeLimit)
+	log.Printf("[StartDrawingPhase] room=%s: phase timer started (%v)", roomID, drawDuration)
+
+	// 5.1 Skip the drawer if they never start drawing
+	if AFKDrawerSkipFraction > 0 {
+		scheduleAFKDrawerSkip(room, drawer, drawingStartedAt,
+			time.Duration(float64(drawDuration)*AFKDrawerSkipFraction))
+	}
+
+	// 5.5 Optionally hint the word's emoji if nobody gets it for a while
+	if EmojiHintDelay > 0 && emoji != "" {
+		scheduleEmojiHint(room, drawer, wordForDrawer, emoji)
+	}
+
+	// 5.9 Announce the new turn to everyone (drawer included) before any
+	// drawing_phase payloads, so clients can reset their canvas and UI first
+	SafeBroadcastToRoom(room, roundStartMessage)
 
 	// 6. Broadcast masked word to all players except the drawer
 	maskedWord := internal.MaskedWordData{
-		RoomID:     roomID,
-		MaskedWord: masked,
+		RoomID:        roomID,
+		MaskedWord:    masked,
+		Difficulty:    difficulty,
+		Points:        difficulty.BasePoints(),
+		GuessProgress: progress,
 	}
 	maskedWordMessage := internal.Message[any]{
 		Type: "drawing_phase",
 		Data: maskedWord,
 	}
 
-	go func() {
-		log.Printf("[StartDrawingPhase] room=%s: broadcasting masked word to all except drawer=%s",
-			roomID, drawer.Id)
-		SafeBroadcastToRoomExcept(room, maskedWordMessage, drawer)
-	}()
+	log.Printf("[StartDrawingPhase] room=%s: broadcasting masked word to all except drawer=%s",
+		roomID, drawer.Id)
+	queueBroadcast(room, maskedWordMessage, drawer)
 
-	// 7. Send full drawer data (private) to the drawer using safe per-connection writer
+	// 7. Send full drawer data (private) to the drawer, queued so it lands
+	// after round_start
 	drawerData := internal.Message[any]{
 		Type: "drawing_phase",
 		Data: map[string]any{
-			"room_id":        roomID,
-			"current_word":   wordForDrawer,
-			"current_drawer": map[string]string{"id": drawer.Id, "username": drawer.Username},
-			"phase":          internal.PhaseDrawing,
-			"time_remaining": timeLimit,
+			"room_id":           roomID,
+			"current_word":      wordForDrawer,
+			"current_drawer":    map[string]string{"id": drawer.Id, "username": drawer.Username},
+			"phase":             internal.PhaseDrawing,
+			"time_remaining_ms": timeLimit,
+			"guess_progress":    progress,
+			"difficulty":        drawerDifficulty,
+			"points":            drawerDifficulty.BasePoints(),
 		},
 	}
 
 	log.Printf("[StartDrawingPhase] room=%s: sending private drawer data to %s (%s)",
 		roomID, drawer.Id, drawer.Username)
+	// A failed send is logged by the queue; a gone drawer is handled by removal
+	queueSend(room, drawer, drawerData)
+}
+
+// roomLanguage is the language room's words come from: its configured
+// language if that word list is loaded, else utils.DefaultLanguage
+func roomLanguage(room *internal.Room) string {
+	if lang := room.Config.Language; lang != "" && utils.HasLanguage(lang) {
+		return lang
+	}
+	return utils.DefaultLanguage
+}
+
+// roomWordChoices asks room's word provider (or WordSource) for a drawer's
+// word choices, skipping words already drawn this game, falling back to
+// random words if it offers none. Caller must hold room.Mu.
+func roomWordChoices(room *internal.Room) []internal.Word {
+	provider := room.Words
+	if provider == nil {
+		provider = WordSource
+	}
+	if words := provider.GenerateChoices(room.Config, room.UsedWords); len(words) > 0 {
+		return words
+	}
+	log.Printf("[roomWordChoices] room=%s: word provider offered no words, using random ones", room.Id)
+	return utils.RandomWords{}.GenerateChoices(room.Config, room.UsedWords)
+}
 
-	if err := drawer.SafeWriteJSON(drawerData); err != nil {
-		// If sending fails, log and continue; drawer may have disconnected — remove player will handle it.
-		log.Printf("[StartDrawingPhase] room=%s: failed to send drawer data to %s (%s): %v",
-			roomID, drawer.Id, drawer.Username, err)
-	} else {
-		log.Printf("[StartDrawingPhase] room=%s: successfully sent drawer data to %s (%s)",
-			roomID, drawer.Id, drawer.Username)
+// drawingPhaseDuration is how long the current turn's drawing phase lasts:
+// the room's configured DrawTime, else the DrawTimeByDifficulty entry for the
+// chosen word's difficulty, else the fixed DrawingPhaseDuration. Caller must
+// hold room.Mu.
+func drawingPhaseDuration(room *internal.Room) time.Duration {
+	if room.Config.DrawTime > 0 {
+		return room.Config.DrawTime
 	}
+	if d := DrawTimeByDifficulty[room.WordDifficulty]; d > 0 {
+		return d
+	}
+	return internal.DrawingPhaseDuration
+}
+
+// waitingPhaseDuration is the room's configured WaitTime, or the fixed
+// WaitingPhaseDuration. Caller must hold room.Mu.
+func waitingPhaseDuration(room *internal.Room) time.Duration {
+	if room.Config.WaitTime > 0 {
+		return room.Config.WaitTime
+	}
+	return internal.WaitingPhaseDuration
+}
+
+// guesserWordDifficulty is the chosen word's difficulty as guessers may see
+// it: empty when HideWordDifficulty is set. Caller must hold room.Mu.
+func guesserWordDifficulty(room *internal.Room) internal.WordDifficulty {
+	if HideWordDifficulty {
+		return ""
+	}
+	return room.WordDifficulty
+}
+
+// scheduleEmojiHint sends guessers the word's emoji after EmojiHintDelay if the
+// same turn is still running and nobody has guessed correctly yet
+func scheduleEmojiHint(room *internal.Room, drawer *internal.Player, word, emoji string) {
+	time.AfterFunc(EmojiHintDelay, func() {
+		if room.Context != nil && room.Context.Err() != nil {
+			return
+		}
+
+		room.Mu.RLock()
+		stillGuessing := room.Phase == internal.PhaseDrawing && room.Current == drawer &&
+			room.Word == word && len(room.CorrectGuessers) == 0
+		roomID := room.Id
+		room.Mu.RUnlock()
+		if !stillGuessing {
+			return
+		}
+
+		log.Printf("[StartDrawingPhase] room=%s: no correct guesses yet, sending emoji hint", roomID)
+		SafeBroadcastToRoomExcept(room, internal.Message[any]{
+			Type: "emoji_hint",
+			Data: map[string]any{
+				"room_id": roomID,
+				"emoji":   emoji,
+			},
+		}, drawer)
+	})
 }
 
 // StartRevealingPhase shows word and round results (8 seconds)
@@ -410,14 +718,15 @@ func StartRevealingPhase(room *internal.Room) {
 		log.Println("[StartRevealingPhase] nil room, abort")
 		return
 	}
+	// cancel active drawing timer (use CancelPhaseTimer helper if available)
+	// using CancelPhaseTimer keeps a single place for timer cleanup semantics.
+	// It takes the room lock itself, so call it before locking.
+	CancelPhaseTimer(room)
+
 	room.Mu.Lock()
 
 	// set phase
-	room.Phase = internal.PhaseRevealing
-
-	// cancel active drawing timer (use CancelPhaseTimer helper if available)
-	// using CancelPhaseTimer keeps a single place for timer cleanup semantics
-	CancelPhaseTimer(room)
+	setPhase(room, internal.PhaseRevealing)
 
 	// ensure nobody can draw
 	for _, p := range room.Players {
@@ -426,32 +735,15 @@ func StartRevealingPhase(room *internal.Room) {
 		}
 	}
 
-	// create round stats entry (populate fields that we know exist)
-	rs := internal.RoundStats{
-		RoundNumber:     room.RoundNumber,
-		DrawerId:        "",
-		Word:            room.Word,
-		CorrectGuessers: room.CorrectGuessers,
-		TotalGuesses:    len(room.CorrectGuessers),
-		StartTime:       time.Time{},
-		EndTime:         time.Now(),
-	}
-	if room.Current != nil {
-		rs.DrawerId = room.Current.Id
-	}
-	if room.Timer != nil {
-		rs.StartTime = room.Timer.StartTime
-	}
-
-	// append to round stats
-	room.RoundStats = append(room.RoundStats, rs)
+	// create and record the round stats entry
+	rs := recordRoundStats(room)
 
 	// compute next drawer index and next player snapshot (safe while holding lock)
 	var nextPlayerPublic *internal.Player = nil
 	var nextIndex int = -1
-	if len(room.PlayerOrder) > 0 {
-		// next index = (currentIndex+1) % len
-		nextIndex = (room.CurrentIndex + 1) % len(room.PlayerOrder)
+	if idx, _ := nextTurnIndex(room); idx >= 0 {
+		// next player after CurrentIndex who can take a turn
+		nextIndex = idx
 		nextID := room.PlayerOrder[nextIndex]
 		if p := room.Players[nextID]; p != nil {
 			// use ToPublicPlayer to avoid sending Conn/Room in messages
@@ -467,11 +759,13 @@ func StartRevealingPhase(room *internal.Room) {
 		}
 	}
 
-	// Determine simple end-of-game condition (conservative):
-	// here we treat the game as ended if room.RoundNumber >= room.MaxRounds.
-	// NOTE: if you want different rules (e.g. end when we've completed all player turns
-	// in the last round), adjust this logic below.
-	isGameEndedNow := room.RoundNumber >= room.MaxRounds
+	// Same end-of-game rule as NextRound: after everyone drew in the final round
+	isGameEndedNow := room.IsGameOver()
+
+	var scoreboard []internal.RoundScore
+	if ShowRoundScoreboard {
+		scoreboard = roundScoreboard(room, rs)
+	}
 
 	// Snapshot some fields for broadcasting after unlock
 	roundNum := room.RoundNumber
@@ -491,10 +785,12 @@ func StartRevealingPhase(room *internal.Room) {
 		Word:            word,
 		DrawerID:        drawerID,
 		CorrectGuessers: rs.CorrectGuessers,
+		DrawerPoints:    rs.DrawerPoints,
 		DrawerUsername:  drawerName,
 		NextDrawer:      nextPlayerPublic,
 		FinalScores:     finalScores,
 		IsGameEnded:     isGameEndedNow,
+		Scoreboard:      scoreboard,
 	}
 	roundEndMessage := internal.Message[any]{
 		Type: "round_end",
@@ -506,14 +802,13 @@ func StartRevealingPhase(room *internal.Room) {
 
 	// broadcast (SafeBroadcastToRoom snapshots connections internally)
 	SafeBroadcastToRoom(room, roundEndMessage)
+	NotifyWebhook(room, roundEndMessage.Type, roundEndMessage.Data)
 
 	// 3) Start reveal timer: after 8s either EndGame or NextRound
 	onRevealComplete := func() {
 		// Re-check end condition under lock at expiry time (more accurate than earlier snapshot)
 		room.Mu.Lock()
-		shouldEnd := room.RoundNumber > room.MaxRounds ||
-			(room.RoundNumber == room.MaxRounds && room.CurrentIndex == len(room.PlayerOrder)-1)
-
+		shouldEnd := room.IsGameOver()
 		room.Mu.Unlock()
 
 		if shouldEnd {
@@ -528,6 +823,92 @@ func StartRevealingPhase(room *internal.Room) {
 	StartPhaseTimer(room, 8*time.Second, onRevealComplete)
 }
 
+// recordRoundStats builds the stats entry for the turn that just finished and
+// appends it to room.RoundStats, once per turn. That is also when the drawer
+// is awarded their points, since a turn can end through StartRevealingPhase
+// or straight through NextRound. Caller must hold room.Mu.
+func recordRoundStats(room *internal.Room) internal.RoundStats {
+	// populate fields that we know exist
+	rs := internal.RoundStats{
+		RoundNumber:     room.RoundNumber,
+		DrawerId:        "",
+		Word:            room.Word,
+		CorrectGuessers: room.CorrectGuessers,
+		TotalGuesses:    len(room.CorrectGuessers),
+		StartTime:       time.Time{},
+		EndTime:         now(),
+	}
+	if room.Current != nil {
+		rs.DrawerId = room.Current.Id
+		rs.DrawerUsername = room.Current.Username
+	}
+	if room.Timer != nil {
+		rs.StartTime = room.Timer.StartTime
+	}
+
+	if !room.RoundRecorded {
+		if room.Current != nil {
+			rs.DrawerPoints = CalculateDrawerPoints(rs.CorrectGuessers, roundGuessers(room), drawingPhaseDuration(room))
+			room.Current.Score += rs.DrawerPoints
+		}
+		room.RoundStats = append(room.RoundStats, rs)
+		room.RoundRecorded = true
+	}
+	return rs
+}
+
+// roundGuessers counts the players who could guess this turn: everyone but
+// the drawer who is still connected or already guessed. Caller must hold room.Mu.
+func roundGuessers(room *internal.Room) int {
+	count := 0
+	for _, p := range room.Players {
+		if p != room.Current && (p.IsConnected || p.HasGuessed) {
+			count++
+		}
+	}
+	return count
+}
+
+// guessProgress counts the correct guessers against everyone who could guess
+// this turn, or is nil outside drawing or when ShowGuessProgress is off.
+// Caller must hold room.Mu.
+func guessProgress(room *internal.Room) *internal.GuessProgress {
+	if !ShowGuessProgress || room.Phase != internal.PhaseDrawing {
+		return nil
+	}
+	return &internal.GuessProgress{
+		Guessed: len(room.CorrectGuessers),
+		Total:   max(roundGuessers(room), len(room.CorrectGuessers)),
+	}
+}
+
+// roundScoreboard lists every player's points from the round in rs (their
+// correct guess, or the drawer's points) with their new total, highest total
+// first. Caller must hold room.Mu.
+func roundScoreboard(room *internal.Room, rs internal.RoundStats) []internal.RoundScore {
+	roundPoints := make(map[string]int, len(rs.CorrectGuessers)+1)
+	for _, g := range rs.CorrectGuessers {
+		roundPoints[g.PlayerID] += g.Points
+	}
+	if rs.DrawerId != "" {
+		roundPoints[rs.DrawerId] += rs.DrawerPoints
+	}
+
+	scoreboard := make([]internal.RoundScore, 0, len(room.Players))
+	for _, p := range room.Players {
+		scoreboard = append(scoreboard, internal.RoundScore{
+			PlayerID:    p.Id,
+			Username:    p.Username,
+			RoundPoints: roundPoints[p.Id],
+			TotalScore:  p.Score,
+		})
+	}
+	slices.SortFunc(scoreboard, func(a, b internal.RoundScore) int {
+		return cmp.Or(cmp.Compare(b.TotalScore, a.TotalScore), cmp.Compare(a.Username, b.Username))
+	})
+	return scoreboard
+}
+
 // NextRound advances to next player or ends game
 func NextRound(room *internal.Room) {
 	if room == nil {
@@ -541,35 +922,56 @@ func NextRound(room *internal.Room) {
 	room.Mu.Lock()
 	log.Printf("[NextRound] room=%s: updated player order=%v", room.Id, room.PlayerOrder)
 
-	// No players left → end game
-	if len(room.PlayerOrder) == 0 {
+	// Record a drawn turn that ended without a reveal (everyone guessed or
+	// time ran out). The order update cleared Current if the drawer left.
+	if room.DrawingStarted && room.Current != nil && room.Word != "" {
+		recordRoundStats(room)
+	}
+	room.TurnsPlayed++
+
+	// No players left (or only held seats) → end game
+	if !slices.ContainsFunc(room.PlayerOrder, room.CanTakeTurn) {
 		room.Mu.Unlock()
 		log.Printf("[NextRound] room=%s: no players left, ending game", room.Id)
 		go EndGame(room) // async, don’t block
 		return
 	}
 
-	// Advance index with wraparound
+	// Last turn of the final round just finished → end game
+	if room.IsGameOver() {
+		rn := room.RoundNumber
+		room.Mu.Unlock()
+		log.Printf("[NextRound] room=%s: final turn of round %d/%d done → ending game",
+			room.Id, rn, room.MaxRounds)
+		go EndGame(room) // async
+		return
+	}
+
+	// Hard turn cap reached, however many rounds are left → end game
+	if MaxGameTurns > 0 && room.TurnsPlayed >= MaxGameTurns {
+		turns := room.TurnsPlayed
+		room.Mu.Unlock()
+		log.Printf("[NextRound] room=%s: hit the cap of %d turns → ending game", room.Id, turns)
+		go EndGame(room) // async
+		return
+	}
+
+	// Advance index with wraparound (index may be -1 if the first drawer
+	// left), skipping held seats
 	prevIndex := room.CurrentIndex
-	room.CurrentIndex = (room.CurrentIndex + 1) % len(room.PlayerOrder)
+	var wrapped bool
+	room.CurrentIndex, wrapped = nextTurnIndex(room)
 	room.Word = ""
-	wrapped := room.CurrentIndex == 0
+	room.WordEmoji = ""
+	room.WordDifficulty = ""
 	log.Printf("[NextRound] room=%s: advanced index prev=%d new=%d wrapped=%v",
 		room.Id, prevIndex, room.CurrentIndex, wrapped)
 
 	if wrapped {
 		room.RoundNumber++
 		log.Printf("[NextRound] room=%s: round incremented to %d", room.Id, room.RoundNumber)
-
-		if room.RoundNumber > room.MaxRounds {
-			rn := room.RoundNumber
-			room.Mu.Unlock()
-			log.Printf("[NextRound] room=%s: round %d > maxRounds %d → ending game",
-				room.Id, rn, room.MaxRounds)
-			go EndGame(room) // async
-			return
-		}
 	}
+	reorderRemainingDrawers(room)
 
 	// Assign new drawer
 	nextPlayerID := room.PlayerOrder[room.CurrentIndex]
@@ -602,6 +1004,64 @@ func NextRound(room *internal.Room) {
 	log.Printf("[NextRound] room=%s: started waiting phase goroutine", room.Id)
 }
 
+// nextTurnIndex is the index in PlayerOrder of the first player after
+// CurrentIndex who can take a turn, skipping held seats, and whether the
+// rotation wrapped around to reach them; -1 if nobody can.
+// Caller must hold room.Mu.
+func nextTurnIndex(room *internal.Room) (int, bool) {
+	idx, wrapped := room.CurrentIndex, false
+	for range room.PlayerOrder {
+		next := (idx + 1) % len(room.PlayerOrder)
+		if next <= idx {
+			wrapped = true
+		}
+		idx = next
+		if room.CanTakeTurn(room.PlayerOrder[idx]) {
+			return idx, wrapped
+		}
+	}
+	return -1, wrapped
+}
+
+// reorderRemainingDrawers applies DrawerOrderStrategy to the players who have
+// not drawn yet this round (PlayerOrder from CurrentIndex on), so everyone
+// still draws once per round. Ties keep their rotation order.
+// Caller must hold room.Mu.
+func reorderRemainingDrawers(room *internal.Room) {
+	var favorLow bool
+	switch DrawerOrderStrategy {
+	case DrawerOrderComeback:
+		favorLow = true
+	case DrawerOrderLeaders:
+		favorLow = false
+	default:
+		return
+	}
+
+	score := func(id string) int {
+		if p := room.Players[id]; p != nil {
+			return p.Score
+		}
+		return 0
+	}
+	remaining := room.PlayerOrder[room.CurrentIndex:]
+	slices.SortStableFunc(remaining, func(a, b string) int {
+		// held seats wait at the back so one isn't picked to draw next
+		if canA, canB := room.CanTakeTurn(a), room.CanTakeTurn(b); canA != canB {
+			if canA {
+				return -1
+			}
+			return 1
+		}
+		if favorLow {
+			return cmp.Compare(score(a), score(b))
+		}
+		return cmp.Compare(score(b), score(a))
+	})
+	log.Printf("[NextRound] room=%s: %s order for the rest of round %d: %v",
+		room.Id, DrawerOrderStrategy, room.RoundNumber, remaining)
+}
+
 // EndGame finishes game and shows final results
 func EndGame(room *internal.Room) {
 	if room == nil {
@@ -609,11 +1069,13 @@ func EndGame(room *internal.Room) {
 		return
 	}
 
+	// Cleanup timers first: CancelPhaseTimer takes the room lock itself
+	CancelPhaseTimer(room)
+
 	room.Mu.Lock()
 
-	// Set ended phase & cleanup timers
-	room.Phase = internal.PhaseEnded
-	CancelPhaseTimer(room)
+	// Set ended phase
+	setPhase(room, internal.PhaseEnded)
 
 	// Snapshot room ID for logging
 	roomID := room.Id
@@ -622,6 +1084,9 @@ func EndGame(room *internal.Room) {
 
 	// Compute results outside lock
 	resultData := CalculateFinalResults(room)
+	room.Mu.Lock()
+	room.LastResults = &resultData
+	room.Mu.Unlock()
 
 	// Broadcast final leaderboard
 	resultMessage := internal.Message[any]{
@@ -630,10 +1095,60 @@ func EndGame(room *internal.Room) {
 	}
 	log.Printf("[EndGame] room=%s: broadcasting final results", roomID)
 	SafeBroadcastToRoom(room, resultMessage)
+	NotifyWebhook(room, resultMessage.Type, resultMessage.Data)
 
 	// Start 30s timer to reset to lobby (async)
-	StartPhaseTimer(room, 30*time.Second, func() {
+	StartPhaseTimer(room, resultsDisplayDuration, func() {
 		log.Printf("[EndGame.timer] room=%s: returning to lobby", roomID)
 		go ResetRoomToLobby(room)
 	})
 }
+
+// AbortGame ends a game that can't go on, e.g. too few players are left.
+// Unlike EndGame there are no awards: game_ended only carries the aborted
+// flag and reason, and the room goes straight back to the lobby (or after
+// the usual results window if SkipToLobbyOnAbort is off) with scores kept.
+func AbortGame(room *internal.Room, reason string) {
+	if room == nil {
+		log.Println("[AbortGame] nil room, abort")
+		return
+	}
+	CancelPhaseTimer(room)
+
+	room.Mu.Lock()
+	// A game already showing its results has nothing left to abort
+	if !room.HasGameStarted || room.Phase == internal.PhaseEnded {
+		room.Mu.Unlock()
+		ResetRoomToLobby(room)
+		return
+	}
+	setPhase(room, internal.PhaseEnded)
+	resultData := internal.FinalResults{
+		Aborted:      true,
+		AbortReason:  reason,
+		RoundsPlayed: room.RoundNumber,
+		TotalPlayers: len(room.Players),
+	}
+	room.LastResults = &resultData
+	roomID := room.Id
+	skipToLobby := SkipToLobbyOnAbort
+	room.Mu.Unlock()
+
+	resultMessage := internal.Message[any]{
+		Type: "game_ended",
+		Data: resultData,
+	}
+	log.Printf("[AbortGame] room=%s: game aborted (%s)", roomID, reason)
+	SafeBroadcastToRoom(room, resultMessage)
+	NotifyWebhook(room, resultMessage.Type, resultMessage.Data)
+
+	// Nobody won an aborted game, so scores are kept for when it resumes
+	if skipToLobby {
+		PauseRoomToLobby(room)
+		return
+	}
+	StartPhaseTimer(room, resultsDisplayDuration, func() {
+		log.Printf("[AbortGame.timer] room=%s: returning to lobby", roomID)
+		go PauseRoomToLobby(room)
+	})
+}
@@ -0,0 +1,1092 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+	"github.com/scythe504/skribblr-backend/internal/utils"
+)
+
+// withWordChoices sets drawer as current and offers choices, as StartWordSelection would
+func withWordChoices(room *internal.Room, drawer *internal.Player, choices ...string) {
+	room.Phase = internal.PhaseWaiting
+	room.Current = drawer
+	room.WordChoices = make([]internal.Word, 0, len(choices))
+	for _, c := range choices {
+		room.WordChoices = append(room.WordChoices, internal.Word{
+			Word:      c,
+			Difficult: internal.DifficultyEasy,
+			Points:    internal.DifficultyEasy.BasePoints(),
+		})
+	}
+}
+
+func TestHandleWordSelectionValidatesOfferedChoices(t *testing.T) {
+	room := newTestRoom(t, "word-selection")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+
+	HandleWordSelection(drawer, "zebra")
+	room.Mu.RLock()
+	word := room.Word
+	room.Mu.RUnlock()
+	if word != "" {
+		t.Fatalf("expected non-offered word to be rejected, room word is %q", word)
+	}
+
+	HandleWordSelection(drawer, "bird")
+	room.Mu.RLock()
+	word = room.Word
+	room.Mu.RUnlock()
+	if word != "bird" {
+		t.Fatalf("expected offered word to be selected, room word is %q", word)
+	}
+}
+
+func TestWordSelectionMapsCaseVariantToOfferedWord(t *testing.T) {
+	room := newTestRoom(t, "word-selection-case")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	withWordChoices(room, drawer, "cat", "Eiffel Tower", "DNA")
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleWordSelection(drawer, "  eiffel TOWER ")
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Word != "Eiffel Tower" {
+		t.Fatalf("expected the offered spelling to be stored, room word is %q", room.Word)
+	}
+}
+
+func TestHandleWordSelectionRejectsNonDrawer(t *testing.T) {
+	room := newTestRoom(t, "word-selection-non-drawer")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, _ := addConnectedPlayer(room, "guesser")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+
+	HandleWordSelection(guesser, "cat")
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Word != "" {
+		t.Fatalf("expected selection by non-drawer to be ignored, room word is %q", room.Word)
+	}
+}
+
+func TestResendWordSelectionToReconnectedDrawer(t *testing.T) {
+	room := newTestRoom(t, "resend-selection")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+	timerBefore := room.Timer
+
+	// Same identity, fresh connection
+	conn := newFakeConn()
+	reconnected := &internal.Player{Id: drawer.Id, Username: drawer.Username, Conn: conn, Room: room, IsConnected: true}
+
+	if !ResendWordSelection(reconnected) {
+		t.Fatal("expected choices to be resent to the reconnected drawer")
+	}
+
+	msgs := conn.messagesOfType("word_selection")
+	if len(msgs) != 1 {
+		t.Fatalf("expected exactly one word_selection frame, got %d", len(msgs))
+	}
+	var data internal.WordSelectionData
+	if err := json.Unmarshal(msgs[0].Data, &data); err != nil {
+		t.Fatalf("failed to decode word_selection: %v", err)
+	}
+	if len(data.Choices) != 3 || data.Choices[0].Word != "cat" || data.Choices[1].Word != "bird" || data.Choices[2].Word != "apple" {
+		t.Errorf("expected the same choices to be resent, got %+v", data.Choices)
+	}
+	if room.Timer != timerBefore {
+		t.Error("expected the selection timer not to be restarted")
+	}
+
+	HandleWordSelection(reconnected, "apple")
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Word != "apple" {
+		t.Errorf("expected reconnected drawer to be able to select, room word is %q", room.Word)
+	}
+}
+
+func TestResendWordSelectionIgnoresNonDrawer(t *testing.T) {
+	room := newTestRoom(t, "resend-selection-guesser")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, conn := addConnectedPlayer(room, "guesser")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+
+	if ResendWordSelection(guesser) {
+		t.Fatal("expected no resend for a player who is not drawing")
+	}
+	if got := len(conn.messagesOfType("word_selection")); got != 0 {
+		t.Errorf("expected guesser to receive no choices, got %d frames", got)
+	}
+}
+
+// withClock pins the game clock to the returned pointer's value for the test
+func withClock(t *testing.T, start time.Time) *time.Time {
+	t.Helper()
+	current := start
+	prev := now
+	now = func() time.Time { return current }
+	t.Cleanup(func() { now = prev })
+	return &current
+}
+
+func TestEndGameReportsDurations(t *testing.T) {
+	base := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	clock := withClock(t, base)
+
+	room := newTestRoom(t, "durations")
+	_, conn := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	room.StartedAt = now()
+	room.RoundStats = []internal.RoundStats{
+		{RoundNumber: 1, StartTime: base.Add(15 * time.Second), EndTime: base.Add(105 * time.Second)},  // 90s
+		{RoundNumber: 1, StartTime: base.Add(130 * time.Second), EndTime: base.Add(250 * time.Second)}, // 120s
+		{RoundNumber: 2}, // never timed
+	}
+	*clock = base.Add(5 * time.Minute)
+
+	EndGame(room)
+
+	msg, ok := conn.waitFor("game_ended", time.Second)
+	if !ok {
+		t.Fatal("expected game_ended to be broadcast")
+	}
+	var results internal.FinalResults
+	if err := json.Unmarshal(msg.Data, &results); err != nil {
+		t.Fatalf("bad game_ended payload: %v", err)
+	}
+	if want := (5 * time.Minute).Milliseconds(); results.GameDurationMs != want {
+		t.Errorf("expected game duration %dms, got %dms", want, results.GameDurationMs)
+	}
+	if want := (105 * time.Second).Milliseconds(); results.AvgRoundDurationMs != want {
+		t.Errorf("expected average round duration %dms, got %dms", want, results.AvgRoundDurationMs)
+	}
+}
+
+func TestRandomWordSelectsOfferedChoice(t *testing.T) {
+	room := newTestRoom(t, "random-word")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	guesser, _ := addConnectedPlayer(room, "guesser")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleRandomWordSelection(guesser)
+	room.Mu.RLock()
+	word := room.Word
+	room.Mu.RUnlock()
+	if word != "" {
+		t.Fatalf("expected random_word from a non-drawer to be ignored, room word is %q", word)
+	}
+
+	HandleRandomWordSelection(drawer)
+	room.Mu.RLock()
+	word = room.Word
+	room.Mu.RUnlock()
+	if !slices.Contains([]string{"cat", "bird", "apple"}, word) {
+		t.Fatalf("expected one of the offered words to be selected, room word is %q", word)
+	}
+	if _, ok := drawerConn.waitFor("drawing_phase", time.Second); !ok {
+		t.Fatal("expected the drawing phase to start")
+	}
+
+	// Already chosen: a second request changes nothing
+	HandleRandomWordSelection(drawer)
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Word != word {
+		t.Errorf("expected the selected word to stay %q, got %q", word, room.Word)
+	}
+}
+
+func TestRandomWordDisabled(t *testing.T) {
+	prev := RandomWordEnabled
+	RandomWordEnabled = false
+	t.Cleanup(func() { RandomWordEnabled = prev })
+
+	room := newTestRoom(t, "random-word-off")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+
+	HandleRandomWordSelection(drawer)
+	if _, ok := drawerConn.waitFor("error", time.Second); !ok {
+		t.Error("expected the drawer to be told random_word is off")
+	}
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Word != "" {
+		t.Errorf("expected no word to be selected, got %q", room.Word)
+	}
+}
+
+func TestRefreshWordsOncePerTurn(t *testing.T) {
+	room := newTestRoom(t, "refresh-words")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	guesser, guesserConn := addConnectedPlayer(room, "guesser")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+
+	// Only the drawer may refresh
+	HandleRefreshWords(guesser)
+	if room.WordsRefreshed {
+		t.Fatal("expected a guesser's refresh to be ignored")
+	}
+
+	HandleRefreshWords(drawer)
+	msg, ok := drawerConn.waitFor("word_selection", time.Second)
+	if !ok {
+		t.Fatal("expected the drawer to get new choices")
+	}
+	var data internal.WordSelectionData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad word_selection payload: %v", err)
+	}
+	room.Mu.RLock()
+	refreshed := append([]internal.Word(nil), room.WordChoices...)
+	room.Mu.RUnlock()
+	if len(data.Choices) != 3 || data.CanRefresh || !slices.Equal(data.Choices, refreshed) {
+		t.Errorf("expected the 3 new choices with no refresh left, got %+v", data)
+	}
+	if got := len(guesserConn.messagesOfType("word_selection")); got != 0 {
+		t.Errorf("expected refreshed choices to reach the drawer only, guesser got %d", got)
+	}
+
+	// A second refresh is refused and leaves the choices alone
+	HandleRefreshWords(drawer)
+	if _, ok := drawerConn.waitFor("error", time.Second); !ok {
+		t.Error("expected the drawer to be told the refresh is used up")
+	}
+	if got := len(drawerConn.messagesOfType("word_selection")); got != 1 {
+		t.Errorf("expected one word_selection, got %d", got)
+	}
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if !slices.Equal(room.WordChoices, refreshed) {
+		t.Errorf("expected choices to stay %v, got %v", refreshed, room.WordChoices)
+	}
+}
+
+func TestRefreshWordsFollowsRoomMix(t *testing.T) {
+	room := newTestRoom(t, "refresh-words-mix")
+	room.Config.WordMix = internal.WordMixMedium
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+
+	HandleRefreshWords(drawer)
+	if _, ok := drawerConn.waitFor("word_selection", time.Second); !ok {
+		t.Fatal("expected the drawer to get new choices")
+	}
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	for _, choice := range room.WordChoices {
+		if choice.Difficult != internal.DifficultyMedium {
+			t.Errorf("expected only medium words for an all-medium room, got %+v", room.WordChoices)
+			break
+		}
+	}
+}
+
+func TestRefreshWordsAfterSelectionIgnored(t *testing.T) {
+	room := newTestRoom(t, "refresh-words-late")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+	room.Word = "cat"
+
+	HandleRefreshWords(drawer)
+	time.Sleep(20 * time.Millisecond)
+	if got := len(drawerConn.messagesOfType("word_selection")); got != 0 || room.WordsRefreshed {
+		t.Errorf("expected no refresh once the word is chosen, got %d word_selection", got)
+	}
+}
+
+// fixedWords is a WordProvider offering the same medium words every turn
+type fixedWords []string
+
+func (f fixedWords) GenerateChoices(internal.RoomConfig, map[string]bool) []internal.Word {
+	words := make([]internal.Word, 0, len(f))
+	for _, w := range f {
+		words = append(words, internal.Word{
+			Word:      w,
+			Count:     len(w),
+			Difficult: internal.DifficultyMedium,
+			Points:    internal.DifficultyMedium.BasePoints(),
+		})
+	}
+	return words
+}
+
+func TestInjectedWordProviderDrivesRound(t *testing.T) {
+	room := newTestRoom(t, "word-provider")
+	room.Words = fixedWords{"lighthouse"}
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	guesser, guesserConn := addConnectedPlayer(room, "guesser")
+	room.HasGameStarted = true
+	room.Current = drawer
+	room.Phase = internal.PhaseWaiting
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	StartWordSelection(room)
+	msg, ok := drawerConn.waitFor("word_selection", time.Second)
+	if !ok {
+		t.Fatal("expected the drawer to be offered words")
+	}
+	var data internal.WordSelectionData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad word_selection payload: %v", err)
+	}
+	if len(data.Choices) != 1 || data.Choices[0].Word != "lighthouse" {
+		t.Fatalf("expected the injected word to be offered, got %+v", data.Choices)
+	}
+
+	HandleWordSelection(drawer, "lighthouse")
+	if _, ok := guesserConn.waitFor("drawing_phase", time.Second); !ok {
+		t.Fatal("expected drawing to start with the injected word")
+	}
+	HandleGuessEnhanced(guesser, "lighthouse")
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if !guesser.HasGuessed || guesser.Score == 0 {
+		t.Errorf("expected the known word to be guessed and scored, got guessed=%v score=%d",
+			guesser.HasGuessed, guesser.Score)
+	}
+}
+
+func TestNoWordRepeatsWithinGame(t *testing.T) {
+	room := newTestRoom(t, "no-repeats")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	addConnectedPlayer(room, "guesser")
+	room.HasGameStarted = true
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	// The longest game a room can be configured for: 10 rounds of 12 players
+	drawn := make(map[string]bool)
+	for turn := 1; turn <= internal.MaxRoomRounds*internal.MaxRoomPlayers; turn++ {
+		room.Mu.Lock()
+		room.Phase = internal.PhaseWaiting
+		room.Current = drawer
+		room.Word = ""
+		room.DrawingStarted = false
+		room.WordChoices = roomWordChoices(room)
+		choices := append([]internal.Word(nil), room.WordChoices...)
+		room.Mu.Unlock()
+
+		for _, choice := range choices {
+			if drawn[strings.ToLower(choice.Word)] {
+				t.Fatalf("turn %d: %q was offered again after being drawn", turn, choice.Word)
+			}
+		}
+		word := choices[turn%len(choices)].Word
+		drawn[strings.ToLower(word)] = true
+		HandleWordSelection(drawer, word)
+		waitForCount(t, drawerConn, "drawing_phase", turn)
+		CancelPhaseTimer(room)
+	}
+
+	// Pausing keeps the used words; a new game may draw them again
+	PauseRoomToLobby(room)
+	room.Mu.RLock()
+	kept := len(room.UsedWords)
+	room.Mu.RUnlock()
+	if kept != len(drawn) {
+		t.Errorf("expected a paused game to keep its %d used words, got %d", len(drawn), kept)
+	}
+	ResetRoomToLobby(room)
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if len(room.UsedWords) != 0 {
+		t.Errorf("expected a lobby reset to clear used words, got %d", len(room.UsedWords))
+	}
+}
+
+func TestConcurrentWordSelectionStartsDrawingOnce(t *testing.T) {
+	room := newTestRoom(t, "selection-race")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	addConnectedPlayer(room, "guesser")
+	withWordChoices(room, drawer, "cat", "bird", "apple")
+
+	// Manual picks, the auto-select fallback and stray phase starts all at once
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 8; i++ {
+		wg.Add(3)
+		go func() { defer wg.Done(); <-start; HandleWordSelection(drawer, "bird") }()
+		go func() { defer wg.Done(); <-start; HandleWordSelection(drawer, "cat") }()
+		go func() { defer wg.Done(); <-start; StartDrawingPhase(room) }()
+	}
+	close(start)
+	wg.Wait()
+
+	if _, ok := drawerConn.waitFor("drawing_phase", time.Second); !ok {
+		t.Fatal("expected the drawing phase to start")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := len(drawerConn.messagesOfType("drawing_phase")); got != 1 {
+		t.Errorf("expected drawing to start exactly once, drawer got %d drawing_phase frames", got)
+	}
+	CancelPhaseTimer(room)
+}
+
+func TestGameEndsAfterEveryoneDrawsInFinalRound(t *testing.T) {
+	for players := 1; players <= 4; players++ {
+		t.Run(fmt.Sprintf("%d players", players), func(t *testing.T) {
+			room := newTestRoom(t, fmt.Sprintf("game-over-%d", players))
+			room.MaxRounds = 2
+			var conn *fakeConn
+			for i := 0; i < players; i++ {
+				_, c := addConnectedPlayer(room, fmt.Sprintf("p%d", i))
+				if conn == nil {
+					conn = c
+				}
+			}
+			room.HasGameStarted = true
+			room.Current = room.Players[room.PlayerOrder[0]]
+			room.Phase = internal.PhaseDrawing
+
+			turns := 1
+			for turns <= players*room.MaxRounds {
+				NextRound(room)
+				deadline := time.Now().Add(time.Second)
+				for len(conn.messagesOfType("waiting_phase")) < turns &&
+					len(conn.messagesOfType("game_ended")) == 0 && time.Now().Before(deadline) {
+					time.Sleep(2 * time.Millisecond)
+				}
+				if len(conn.messagesOfType("game_ended")) > 0 {
+					break
+				}
+				turns++
+			}
+
+			if _, ok := conn.waitFor("game_ended", time.Second); !ok {
+				t.Fatalf("expected the game to end, got %d turns", turns)
+			}
+			if want := players * room.MaxRounds; turns != want {
+				t.Errorf("expected %d turns (every player draws each round), got %d", want, turns)
+			}
+		})
+	}
+}
+
+func TestTurnCapEndsLongGame(t *testing.T) {
+	prev := MaxGameTurns
+	MaxGameTurns = 5
+	t.Cleanup(func() { MaxGameTurns = prev })
+
+	room := newTestRoom(t, "turn-cap")
+	// Far more rounds than any config allows today
+	room.MaxRounds = 1000
+	_, conn := addConnectedPlayer(room, "p0")
+	addConnectedPlayer(room, "p1")
+	addConnectedPlayer(room, "p2")
+	room.HasGameStarted = true
+	room.Current = room.Players[room.PlayerOrder[0]]
+	room.Phase = internal.PhaseDrawing
+
+	for turn := 1; turn < MaxGameTurns; turn++ {
+		NextRound(room)
+		waitForCount(t, conn, "waiting_phase", turn)
+	}
+	if got := len(conn.messagesOfType("game_ended")); got != 0 {
+		t.Fatalf("expected the game to go on below the cap, got %d game_ended", got)
+	}
+
+	NextRound(room)
+	if _, ok := conn.waitFor("game_ended", time.Second); !ok {
+		t.Fatalf("expected the game to end after %d turns", MaxGameTurns)
+	}
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.RoundNumber >= room.MaxRounds {
+		t.Errorf("expected the cap to end the game early, got round %d/%d", room.RoundNumber, room.MaxRounds)
+	}
+}
+
+func TestNewTurnBroadcastsEmptyCanvas(t *testing.T) {
+	startNextTurn := func(t *testing.T, id string) *fakeConn {
+		t.Helper()
+		room := newTestRoom(t, id)
+		drawer, _ := addConnectedPlayer(room, "drawer")
+		_, conn := addConnectedPlayer(room, "guesser")
+		room.HasGameStarted = true
+		makeDrawer(room, drawer)
+		room.CanvasState = []internal.PixelMessage{{Type: internal.PixelPlace, Color: "#000000"}}
+		t.Cleanup(func() { CancelPhaseTimer(room) })
+
+		NextRound(room)
+		waitForCount(t, conn, "waiting_phase", 1)
+		return conn
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		conn := startNextTurn(t, "new-turn-canvas")
+		msg, ok := conn.waitFor("canvas_cleared", time.Second)
+		if !ok {
+			t.Fatal("expected canvas_cleared when the next turn starts")
+		}
+		var data struct {
+			Reason      string                  `json:"reason"`
+			CanvasState []internal.PixelMessage `json:"canvas_state"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatalf("bad canvas_cleared payload: %v", err)
+		}
+		if data.Reason != "new_turn" || data.CanvasState == nil || len(data.CanvasState) != 0 {
+			t.Errorf("expected an empty canvas for the new turn, got %+v", data)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		prev := ClearCanvasOnNewTurn
+		ClearCanvasOnNewTurn = false
+		t.Cleanup(func() { ClearCanvasOnNewTurn = prev })
+
+		conn := startNextTurn(t, "new-turn-canvas-off")
+		if _, ok := conn.waitFor("canvas_cleared", 50*time.Millisecond); ok {
+			t.Error("expected no canvas_cleared with ClearCanvasOnNewTurn off")
+		}
+	})
+}
+
+func TestFinalResultsCreditEachDrawnWord(t *testing.T) {
+	room := newTestRoom(t, "word-credits")
+	alice, conn := addConnectedPlayer(room, "alice")
+	bob, _ := addConnectedPlayer(room, "bob")
+	carol, _ := addConnectedPlayer(room, "carol")
+	room.HasGameStarted = true
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	playTurn := func(turn int, drawer *internal.Player, word string, guessers ...*internal.Player) {
+		t.Helper()
+		room.Mu.Lock()
+		makeDrawer(room, drawer)
+		room.Word = word
+		room.DrawingStarted = true
+		room.Timer.StartTime = time.Now()
+		room.Mu.Unlock()
+
+		for _, g := range guessers {
+			HandleGuessEnhanced(g, word)
+		}
+		// Everyone guessing ends the turn on its own; otherwise time runs out
+		if len(guessers) < 2 {
+			CancelPhaseTimer(room)
+			NextRound(room)
+		}
+		deadline := time.Now().Add(time.Second)
+		for len(conn.messagesOfType("waiting_phase")) < turn && time.Now().Before(deadline) {
+			time.Sleep(2 * time.Millisecond)
+		}
+	}
+	playTurn(1, alice, "castle", bob, carol)
+	playTurn(2, bob, "anchor", carol)
+
+	credits := CalculateFinalResults(room).WordCredits
+	if len(credits) != 2 {
+		t.Fatalf("expected a credit per completed word, got %+v", credits)
+	}
+	want := []struct {
+		word, drawer string
+		guessers     []string
+	}{
+		{"castle", "alice", []string{"bob", "carol"}},
+		{"anchor", "bob", []string{"carol"}},
+	}
+	for i, w := range want {
+		got := credits[i]
+		if got.Word != w.word || got.DrawerID != w.drawer || got.DrawerUsername != w.drawer {
+			t.Errorf("credit %d: expected %s drawn by %s, got %+v", i, w.word, w.drawer, got)
+		}
+		if len(got.CorrectGuessers) != len(w.guessers) {
+			t.Errorf("credit %d: expected guessers %v, got %+v", i, w.guessers, got.CorrectGuessers)
+			continue
+		}
+		for j, id := range w.guessers {
+			if got.CorrectGuessers[j].PlayerID != id {
+				t.Errorf("credit %d: expected guesser %d to be %s, got %s", i, j, id, got.CorrectGuessers[j].PlayerID)
+			}
+		}
+	}
+}
+
+func TestNaturalAndAbortedGameEndPayloads(t *testing.T) {
+	t.Run("natural", func(t *testing.T) {
+		room := newTestRoom(t, "end-natural")
+		alice, conn := addConnectedPlayer(room, "alice")
+		addConnectedPlayer(room, "bob")
+		room.HasGameStarted = true
+		alice.Score = 120
+		t.Cleanup(func() { CancelPhaseTimer(room) })
+
+		EndGame(room)
+
+		msg, ok := conn.waitFor("game_ended", time.Second)
+		if !ok {
+			t.Fatal("expected game_ended")
+		}
+		var results internal.FinalResults
+		if err := json.Unmarshal(msg.Data, &results); err != nil {
+			t.Fatalf("bad game_ended payload: %v", err)
+		}
+		if results.Aborted || len(results.Leaderboard) != 2 || results.MVP == nil || results.MVP.PlayerID != "alice" {
+			t.Errorf("expected full results with awards, got %+v", results)
+		}
+	})
+
+	t.Run("aborted", func(t *testing.T) {
+		room := newTestRoom(t, "end-aborted")
+		alice, conn := addConnectedPlayer(room, "alice")
+		bob, _ := addConnectedPlayer(room, "bob")
+		room.HasGameStarted = true
+		makeDrawer(room, bob)
+		room.Word = "apple"
+		alice.Score = 120
+		t.Cleanup(func() { CancelPhaseTimer(room) })
+
+		removePlayer(bob)
+
+		msg, ok := conn.waitFor("game_ended", time.Second)
+		if !ok {
+			t.Fatal("expected game_ended when too few players are left")
+		}
+		var results internal.FinalResults
+		if err := json.Unmarshal(msg.Data, &results); err != nil {
+			t.Fatalf("bad game_ended payload: %v", err)
+		}
+		if !results.Aborted || results.AbortReason != "not_enough_players" {
+			t.Errorf("expected an aborted payload with its reason, got %+v", results)
+		}
+		if results.MVP != nil || results.FastestGuess != nil || len(results.Leaderboard) != 0 {
+			t.Errorf("expected no awards for an aborted game, got %+v", results)
+		}
+		if _, ok := conn.waitFor("lobby_reset", time.Second); !ok {
+			t.Error("expected the room to go straight back to the lobby")
+		}
+		room.Mu.RLock()
+		phase, score := room.Phase, alice.Score
+		room.Mu.RUnlock()
+		if phase != internal.PhaseLobby {
+			t.Errorf("expected lobby phase, got %s", phase)
+		}
+		if score != 120 {
+			t.Errorf("expected an aborted game to keep scores, alice has %d", score)
+		}
+	})
+}
+
+func TestEmojiHintSentWhenNobodyGuesses(t *testing.T) {
+	prev := EmojiHintDelay
+	EmojiHintDelay = 20 * time.Millisecond
+	t.Cleanup(func() { EmojiHintDelay = prev })
+
+	room := newTestRoom(t, "emoji-hint")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	withWordChoices(room, drawer, "cat", "banana")
+	room.WordChoices[1].Emoji = "🍌"
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleWordSelection(drawer, "banana")
+
+	msg, ok := guesserConn.waitFor("emoji_hint", time.Second)
+	if !ok {
+		t.Fatal("expected guessers to get the emoji hint")
+	}
+	var data map[string]string
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad emoji_hint payload: %v", err)
+	}
+	if data["emoji"] != "🍌" {
+		t.Errorf("expected the chosen word's emoji, got %q", data["emoji"])
+	}
+	if got := len(drawerConn.messagesOfType("emoji_hint")); got != 0 {
+		t.Errorf("expected the drawer not to get the hint, got %d", got)
+	}
+}
+
+func TestEmojiHintSkippedAfterCorrectGuess(t *testing.T) {
+	prev := EmojiHintDelay
+	EmojiHintDelay = 50 * time.Millisecond
+	t.Cleanup(func() { EmojiHintDelay = prev })
+
+	room := newTestRoom(t, "emoji-hint-guessed")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, guesserConn := addConnectedPlayer(room, "guesser")
+	addConnectedPlayer(room, "other")
+	withWordChoices(room, drawer, "banana")
+	room.WordChoices[0].Emoji = "🍌"
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleWordSelection(drawer, "banana")
+	if _, ok := guesserConn.waitFor("drawing_phase", time.Second); !ok {
+		t.Fatal("expected the drawing phase to start")
+	}
+	HandleGuessEnhanced(guesser, "banana")
+
+	time.Sleep(100 * time.Millisecond)
+	if got := len(guesserConn.messagesOfType("emoji_hint")); got != 0 {
+		t.Errorf("expected no hint once someone guessed, got %d", got)
+	}
+}
+
+func TestWaitingPhaseAdvertisesItsTimer(t *testing.T) {
+	room := newTestRoom(t, "waiting-time")
+	_, conn := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	room.PlayerOrder = []string{"alice", "bob"}
+	room.CurrentIndex = 0
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	StartWaitingPhase(room)
+
+	msg, ok := conn.waitFor("waiting_phase", time.Second)
+	if !ok {
+		t.Fatal("expected waiting_phase to be broadcast")
+	}
+	var data struct {
+		TimeRemaining int64 `json:"time_remaining_ms"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad waiting_phase payload: %v", err)
+	}
+
+	room.Mu.RLock()
+	scheduled := room.Timer.Duration
+	room.Mu.RUnlock()
+	if scheduled != internal.WaitingPhaseDuration {
+		t.Errorf("expected a %v waiting timer, got %v", internal.WaitingPhaseDuration, scheduled)
+	}
+	if time.Duration(data.TimeRemaining)*time.Millisecond != scheduled {
+		t.Errorf("advertised %dms but scheduled %v", data.TimeRemaining, scheduled)
+	}
+}
+
+func TestDrawerOrderStrategies(t *testing.T) {
+	cases := []struct {
+		strategy  string
+		lastIndex int // index of the player who just drew
+		want      string
+	}{
+		{DrawerOrderRoundRobin, 0, "b"},
+		{DrawerOrderComeback, 0, "d"},
+		{DrawerOrderLeaders, 0, "c"},
+		// end of the round: the whole rotation is re-ranked, including "a"
+		{DrawerOrderComeback, 3, "d"},
+		{DrawerOrderLeaders, 3, "a"},
+	}
+	for _, tc := range cases {
+		t.Run(fmt.Sprintf("%s_after_%d", tc.strategy, tc.lastIndex), func(t *testing.T) {
+			prev := DrawerOrderStrategy
+			DrawerOrderStrategy = tc.strategy
+			t.Cleanup(func() { DrawerOrderStrategy = prev })
+
+			room := newTestRoom(t, "drawer-order")
+			for id, score := range map[string]int{"a": 50, "b": 10, "c": 30, "d": 5} {
+				p, _ := addConnectedPlayer(room, id)
+				p.Score = score
+			}
+			room.PlayerOrder = []string{"a", "b", "c", "d"}
+			room.HasGameStarted = true
+			room.CurrentIndex = tc.lastIndex
+			t.Cleanup(func() { CancelPhaseTimer(room) })
+
+			NextRound(room)
+
+			room.Mu.RLock()
+			defer room.Mu.RUnlock()
+			if room.Current == nil || room.Current.Id != tc.want {
+				t.Fatalf("expected %s to draw next, got %v (order %v)", tc.want, room.Current, room.PlayerOrder)
+			}
+			if len(room.PlayerOrder) != 4 {
+				t.Errorf("expected every player to stay in the rotation, got %v", room.PlayerOrder)
+			}
+		})
+	}
+}
+
+func TestRoundStartPrecedesDrawingPhase(t *testing.T) {
+	room := newTestRoom(t, "round-start")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	room.RoundNumber = 2
+	room.CurrentIndex = 0
+	withWordChoices(room, drawer, "apple")
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleWordSelection(drawer, "apple")
+
+	for name, conn := range map[string]*fakeConn{"drawer": drawerConn, "guesser": guesserConn} {
+		if _, ok := conn.waitFor("drawing_phase", time.Second); !ok {
+			t.Fatalf("expected %s to get drawing_phase", name)
+		}
+		var order []string
+		for _, msg := range conn.messages() {
+			if msg.Type == "round_start" || msg.Type == "drawing_phase" {
+				order = append(order, msg.Type)
+			}
+		}
+		if len(order) != 2 || order[0] != "round_start" {
+			t.Errorf("expected %s to get round_start before drawing_phase, got %v", name, order)
+		}
+	}
+
+	msg := guesserConn.messagesOfType("round_start")[0]
+	var data struct {
+		RoundNumber   int               `json:"round_number"`
+		TurnNumber    int               `json:"turn_number"`
+		TurnsInRound  int               `json:"turns_in_round"`
+		CurrentDrawer map[string]string `json:"current_drawer"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad round_start payload: %v", err)
+	}
+	if data.RoundNumber != 2 || data.TurnNumber != 1 || data.TurnsInRound != 2 || data.CurrentDrawer["id"] != drawer.Id {
+		t.Errorf("unexpected round_start payload %+v", data)
+	}
+}
+
+func TestGuesserDrawingPhaseWordDifficulty(t *testing.T) {
+	if !HideWordDifficulty {
+		t.Error("expected guessers not to see the word's difficulty by default")
+	}
+	for _, hide := range []bool{false, true} {
+		t.Run(fmt.Sprintf("hide=%v", hide), func(t *testing.T) {
+			prev := HideWordDifficulty
+			HideWordDifficulty = hide
+			t.Cleanup(func() { HideWordDifficulty = prev })
+
+			room := newTestRoom(t, "word-difficulty")
+			drawer, drawerConn := addConnectedPlayer(room, "drawer")
+			_, guesserConn := addConnectedPlayer(room, "guesser")
+			withWordChoices(room, drawer, "banana")
+			room.WordChoices[0].Difficult = internal.DifficultyHard
+			room.WordChoices[0].Points = internal.DifficultyHard.BasePoints()
+			t.Cleanup(func() { CancelPhaseTimer(room) })
+
+			HandleWordSelection(drawer, "banana")
+
+			msg, ok := guesserConn.waitFor("drawing_phase", time.Second)
+			if !ok {
+				t.Fatal("expected guessers to get drawing_phase")
+			}
+			var data internal.MaskedWordData
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				t.Fatalf("bad drawing_phase payload: %v", err)
+			}
+			wantDifficulty, wantPoints := internal.DifficultyHard, internal.DifficultyHard.BasePoints()
+			if hide {
+				wantDifficulty, wantPoints = "", 0
+			}
+			if data.Difficulty != wantDifficulty || data.Points != wantPoints {
+				t.Errorf("expected difficulty=%q points=%d, got %q/%d",
+					wantDifficulty, wantPoints, data.Difficulty, data.Points)
+			}
+
+			broadcastGameStateNow(room)
+			msg, ok = guesserConn.waitFor("game_state_update", time.Second)
+			if !ok {
+				t.Fatal("expected guessers to get game_state_update")
+			}
+			var state internal.GameStateData
+			if err := json.Unmarshal(msg.Data, &state); err != nil {
+				t.Fatalf("bad game_state_update payload: %v", err)
+			}
+			if state.WordDifficulty != wantDifficulty {
+				t.Errorf("expected game state difficulty %q, got %q", wantDifficulty, state.WordDifficulty)
+			}
+
+			// The drawer sees the difficulty either way
+			msg, ok = drawerConn.waitFor("drawing_phase", time.Second)
+			if !ok {
+				t.Fatal("expected the drawer to get drawing_phase")
+			}
+			var drawerData struct {
+				Difficulty internal.WordDifficulty `json:"difficulty"`
+				Points     int                     `json:"points"`
+			}
+			if err := json.Unmarshal(msg.Data, &drawerData); err != nil {
+				t.Fatalf("bad drawing_phase payload: %v", err)
+			}
+			if drawerData.Difficulty != internal.DifficultyHard || drawerData.Points != internal.DifficultyHard.BasePoints() {
+				t.Errorf("expected the drawer to see hard/%d, got %+v", internal.DifficultyHard.BasePoints(), drawerData)
+			}
+			msg, ok = drawerConn.waitFor("game_state_update", time.Second)
+			if !ok {
+				t.Fatal("expected the drawer to get game_state_update")
+			}
+			if err := json.Unmarshal(msg.Data, &state); err != nil {
+				t.Fatalf("bad game_state_update payload: %v", err)
+			}
+			if state.WordDifficulty != internal.DifficultyHard {
+				t.Errorf("expected the drawer's game state to carry the difficulty, got %q", state.WordDifficulty)
+			}
+		})
+	}
+}
+
+func TestDrawTimeByDifficulty(t *testing.T) {
+	prev := DrawTimeByDifficulty
+	DrawTimeByDifficulty = map[internal.WordDifficulty]time.Duration{
+		internal.DifficultyEasy:   60 * time.Second,
+		internal.DifficultyMedium: 80 * time.Second,
+		internal.DifficultyHard:   100 * time.Second,
+	}
+	t.Cleanup(func() { DrawTimeByDifficulty = prev })
+
+	timeLimit := func(difficulty internal.WordDifficulty) int64 {
+		t.Helper()
+		room := newTestRoom(t, "draw-time-"+string(difficulty))
+		drawer, _ := addConnectedPlayer(room, "drawer")
+		_, guesserConn := addConnectedPlayer(room, "guesser")
+		withWordChoices(room, drawer, "banana")
+		room.WordChoices[0].Difficult = difficulty
+		t.Cleanup(func() { CancelPhaseTimer(room) })
+
+		HandleWordSelection(drawer, "banana")
+		msg, ok := guesserConn.waitFor("round_start", time.Second)
+		if !ok {
+			t.Fatal("expected round_start")
+		}
+		var data struct {
+			TimeLimit int64 `json:"time_limit_ms"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatalf("bad round_start payload: %v", err)
+		}
+		return data.TimeLimit
+	}
+
+	easy, hard := timeLimit(internal.DifficultyEasy), timeLimit(internal.DifficultyHard)
+	if easy != 60000 || hard != 100000 {
+		t.Errorf("expected easy=60000ms hard=100000ms, got easy=%dms hard=%dms", easy, hard)
+	}
+
+	// Without an entry the fixed duration applies
+	DrawTimeByDifficulty = map[internal.WordDifficulty]time.Duration{}
+	if got, want := timeLimit(internal.DifficultyHard), internal.DrawingPhaseDuration.Milliseconds(); got != want {
+		t.Errorf("expected the fixed %dms in fixed-duration mode, got %dms", want, got)
+	}
+}
+
+func TestRoomLanguagePicksItsWordList(t *testing.T) {
+	// A language code no other test loads, unloaded again afterwards
+	const lang = "xx-rooms"
+	words := map[string]bool{"uno": true, "dos": true, "tres": true, "cuatro": true, "ochenta": true, "novecientos": true}
+	path := filepath.Join(t.TempDir(), "xx.csv")
+	if err := os.WriteFile(path, []byte("word\nuno\ndos\ntres\ncuatro\nochenta\nnovecientos\n"), 0o644); err != nil {
+		t.Fatalf("failed to write word list: %v", err)
+	}
+	if utils.HasLanguage(lang) {
+		t.Fatalf("expected %s not to be loaded yet", lang)
+	}
+	if err := utils.LoadLanguageWords(lang, path, ""); err != nil {
+		t.Fatalf("failed to load word list: %v", err)
+	}
+	t.Cleanup(func() { utils.UnloadLanguageWords(lang) })
+
+	for _, c := range []struct {
+		language string
+		loaded   bool
+	}{
+		{lang, true},
+		{"zz", false}, // not loaded: falls back to the default words
+	} {
+		room := newTestRoom(t, "room-language-"+c.language)
+		room.Config.Language = c.language
+		drawer, _ := addConnectedPlayer(room, "drawer")
+		makeDrawer(room, drawer)
+		room.Phase = internal.PhaseWaiting
+		t.Cleanup(func() { CancelPhaseTimer(room) })
+
+		StartWordSelection(room)
+
+		room.Mu.RLock()
+		choices := room.WordChoices
+		room.Mu.RUnlock()
+		if len(choices) == 0 {
+			t.Fatalf("%s: expected word choices", c.language)
+		}
+		for _, choice := range choices {
+			if words[choice.Word] != c.loaded {
+				t.Errorf("%s: unexpected choice %q (from list=%v)", c.language, choice.Word, words[choice.Word])
+			}
+		}
+	}
+}
+
+func TestMostAccurateAward(t *testing.T) {
+	type guesses struct{ correct, total, score int }
+	tests := []struct {
+		name    string
+		players map[string]guesses
+		want    string // "" for no award
+	}{
+		{
+			name: "best ratio wins over most correct",
+			players: map[string]guesses{
+				"alice": {correct: 3, total: 4, score: 100},
+				"bob":   {correct: 5, total: 10, score: 300},
+			},
+			want: "alice",
+		},
+		{
+			name: "too few guesses to qualify",
+			players: map[string]guesses{
+				"alice": {correct: 2, total: 2, score: 100},
+				"bob":   {correct: 2, total: 4, score: 50},
+			},
+			want: "bob",
+		},
+		{
+			name: "tie goes to more correct guesses",
+			players: map[string]guesses{
+				"alice": {correct: 3, total: 6, score: 200},
+				"bob":   {correct: 5, total: 10, score: 100},
+			},
+			want: "bob",
+		},
+		{
+			name: "full tie goes to the higher score",
+			players: map[string]guesses{
+				"alice": {correct: 2, total: 4, score: 100},
+				"bob":   {correct: 2, total: 4, score: 200},
+			},
+			want: "bob",
+		},
+		{
+			name: "nobody guessed right",
+			players: map[string]guesses{
+				"alice": {correct: 0, total: 5},
+				"bob":   {correct: 0, total: 0},
+			},
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			room := newTestRoom(t, "most-accurate")
+			for id, g := range tt.players {
+				player := addTestPlayer(room, id)
+				player.CorrectGuesses, player.TotalGuesses, player.Score = g.correct, g.total, g.score
+			}
+
+			got := CalculateFinalResults(room).MostAccurate
+			switch {
+			case tt.want == "" && got != nil:
+				t.Errorf("expected no most accurate award, got %+v", *got)
+			case tt.want != "" && (got == nil || got.PlayerID != tt.want):
+				t.Errorf("expected %s to be most accurate, got %+v", tt.want, got)
+			}
+		})
+	}
+}
@@ -1,17 +1,25 @@
 package game
 
 import (
+	"fmt"
 	"log"
-	"strings"
+	"math"
+	"slices"
 	"time"
+	"unicode/utf8"
 
 	"github.com/scythe504/skribblr-backend/internal"
+	"github.com/scythe504/skribblr-backend/internal/utils"
 )
 
 // =============================================================================
 // GUESS HANDLING
 // =============================================================================
 
+// MaxDrawerPoints is what the drawer earns in a turn where every guesser
+// gets the word straight away
+const MaxDrawerPoints = 200
+
 // HandleGuessEnhanced processes player guesses with enhanced scoring
 func HandleGuessEnhanced(player *internal.Player, guess string) {
 	// Defensive nil checks
@@ -25,10 +33,20 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 		return
 	}
 
-	// Normalize incoming guess
-	cleanedGuess := strings.ToLower(strings.TrimSpace(guess))
+	// Reject oversized guesses before doing any work on them
+	if length := utf8.RuneCountInString(guess); length > MaxGuessLength {
+		log.Printf("[HandleGuessEnhanced] room=%s player=%s guess too long (%d characters), rejecting",
+			room.Id, player.Id, length)
+		SendErrorToPlayer(player, "guess_too_long",
+			fmt.Sprintf("Guesses can be at most %d characters", MaxGuessLength))
+		return
+	}
 
 	room.Mu.Lock()
+
+	// Normalize incoming guess
+	cleanedGuess := utils.NormalizeGuess(guess, room.IgnoreArticles)
+
 	// Basic validations under lock
 	if room.Current != nil && player.Id == room.Current.Id {
 		// Drawer cannot guess
@@ -42,9 +60,15 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 		log.Printf("[HandleGuessEnhanced] room=%s player=%s already guessed, ignoring", room.Id, player.Id)
 		return
 	}
+	if room.CreditedGuessers[player.Id] {
+		// Credited this turn even though HasGuessed says otherwise; never twice
+		room.Mu.Unlock()
+		log.Printf("[HandleGuessEnhanced] room=%s player=%s already credited this turn, ignoring", room.Id, player.Id)
+		return
+	}
 
 	// Normalize target word for comparison (room.Word may have original casing)
-	target := strings.ToLower(strings.TrimSpace(room.Word))
+	target := utils.NormalizeGuess(room.Word, room.IgnoreArticles)
 
 	// Incorrect guess path
 	if target == "" || target != cleanedGuess {
@@ -62,8 +86,30 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 
 		// Snapshot roomID for logs / broadcast and unlock before I/O
 		roomID := room.Id
+		muted := player.IsMuted
+		isClose := utils.IsCloseGuess(cleanedGuess, target, CloseGuessDistance)
 		room.Mu.Unlock()
 
+		// A near miss stays private so it can't hand the word to everyone else
+		if isClose {
+			log.Printf("[HandleGuessEnhanced] room=%s player=%s guessed close: %q", roomID, player.Id, guess)
+			closeMessage := internal.Message[any]{
+				Type: "close_guess",
+				Data: map[string]any{
+					"room_id": roomID,
+					"guess":   guess,
+					"message": "You're very close!",
+				},
+			}
+			go func() {
+				if err := player.SafeWriteJSON(closeMessage); err != nil {
+					log.Printf("[HandleGuessEnhanced] room=%s: failed to send close guess to %s: %v",
+						roomID, player.Id, err)
+				}
+			}()
+			return
+		}
+
 		log.Printf("[HandleGuessEnhanced] room=%s player=%s guessed incorrect: %q", roomID, player.Id, guess)
 
 		guessMessage := internal.Message[any]{
@@ -74,8 +120,37 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 			},
 		}
 
-		// Broadcast asynchronously so we don't block the websocket reader
-		go SafeBroadcastToRoom(room, guessMessage)
+		// Queue the broadcast so we don't block the websocket reader.
+		// Muted players only see their own guesses.
+		if muted {
+			go func() {
+				if err := player.SafeWriteJSON(guessMessage); err != nil {
+					log.Printf("[HandleGuessEnhanced] room=%s: failed to echo muted guess to %s: %v",
+						roomID, player.Id, err)
+				}
+			}()
+		} else {
+			queueBroadcast(room, guessMessage, nil)
+		}
+
+		// Optionally nudge the guesser privately with a hot/cold hint
+		if ProximityHintsEnabled && target != "" {
+			band := utils.ProximityBand(cleanedGuess, target)
+			hintMessage := internal.Message[any]{
+				Type: "proximity_hint",
+				Data: map[string]any{
+					"room_id": roomID,
+					"guess":   guess,
+					"band":    band,
+				},
+			}
+			go func() {
+				if err := player.SafeWriteJSON(hintMessage); err != nil {
+					log.Printf("[HandleGuessEnhanced] room=%s: failed to send proximity hint to %s: %v",
+						roomID, player.Id, err)
+				}
+			}()
+		}
 		return
 	}
 
@@ -91,20 +166,10 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 	// position is 1-based ordering of correct guessers
 	position := len(room.CorrectGuessers) + 1
 
-	// Determine difficulty (same logic you had)
-	var diff internal.WordDifficulty
-	switch {
-	case len(room.Word) >= 3 && len(room.Word) <= 5:
-		diff = internal.DifficultyEasy
-	case len(room.Word) > 5 && len(room.Word) <= 8:
-		diff = internal.DifficultyMedium
-	default:
-		// treat very short or empty as easy, long words as hard
-		if len(room.Word) > 8 {
-			diff = internal.DifficultyHard
-		} else {
-			diff = internal.DifficultyEasy
-		}
+	// Difficulty comes from the chosen word; fall back to its length if unset
+	diff := room.WordDifficulty
+	if diff == "" {
+		diff = utils.WordDifficultyByLength(utf8.RuneCountInString(room.Word))
 	}
 
 	// Calculate points (assumes this function exists and takes duration, position, difficulty)
@@ -116,20 +181,23 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 		Username:  player.Username,
 		GuessTime: int(timeTakenMs),
 		IsCorrect: true,
+		Points:    points,
 	}
 
-	// Apply state updates under lock
+	// Apply state updates under lock, together, so the guesser is credited
+	// exactly once: the entry the drawer is scored from, the points and both
+	// already-guessed guards
 	room.CorrectGuessers = append(room.CorrectGuessers, playerGuess)
 
 	player.Score += points
 	player.TotalGuesses++
 	player.CorrectGuesses++
 	player.HasGuessed = true
-
-	// Award drawer points (rule you used)
-	if room.Current != nil {
-		room.Current.Score += 50
+	if room.CreditedGuessers == nil {
+		room.CreditedGuessers = make(map[string]bool)
 	}
+	room.CreditedGuessers[player.Id] = true
+	// The drawer is scored once, when the turn ends (see recordRoundStats)
 
 	// Snapshot data for broadcasting and next-step decision
 	resultData := internal.GameResultData{
@@ -159,17 +227,28 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 		}
 	}
 
-	room.Mu.Unlock() // release lock before any I/O
-
-	// Broadcast the guess result (async)
+	// Queue the guess result for the drawer and everyone who has guessed
+	// (the guesser included). Players still guessing aren't told who got it;
+	// the game state refresh below shows them the progress, if enabled.
 	resultMessage := internal.Message[any]{
 		Type: "guess_result",
 		Data: resultData,
 	}
+	informed := make([]*internal.Player, 0, len(room.Players))
+	for _, p := range room.Players {
+		if p.IsConnected && (p == room.Current || p.HasGuessed) {
+			informed = append(informed, p)
+		}
+	}
+	enqueueBroadcast(room, informed, resultMessage)
+
+	room.Mu.Unlock()
+
 	log.Printf("[HandleGuessEnhanced] room=%s player=%s guessed CORRECT (pos=%d points=%d timeMs=%d)",
 		roomID, player.Id, position, points, timeTakenMs)
 
-	go SafeBroadcastToRoom(room, resultMessage)
+	// Refresh everyone's guess progress
+	BroadcastGameState(room)
 
 	// If everyone guessed, cancel timer and advance round
 	if allGuessed {
@@ -180,25 +259,50 @@ func HandleGuessEnhanced(player *internal.Player, guess string) {
 	}
 }
 
+// CalculateDrawerPoints scores the drawer at the end of a turn from the
+// fraction of the eligible guessers who got the word and how fast they were on
+// average: instant guesses are worth full marks, guesses at the buzzer half.
+// Nobody guessing earns nothing, and each guesser counts once however many
+// entries they have.
+func CalculateDrawerPoints(guessers []internal.PlayerGuess, eligible int, drawTime time.Duration) int {
+	// 0. Keep each guesser's first entry only
+	seen := make(map[string]bool, len(guessers))
+	guessers = slices.DeleteFunc(slices.Clone(guessers), func(g internal.PlayerGuess) bool {
+		dup := seen[g.PlayerID]
+		seen[g.PlayerID] = true
+		return dup
+	})
+	if len(guessers) == 0 {
+		return 0
+	}
+	// 1. Share of the room that guessed (leavers who guessed still count)
+	eligible = max(eligible, len(guessers))
+	fraction := float64(len(guessers)) / float64(eligible)
+
+	// 2. Average speed, 1 for instant down to 0 at the end of the turn
+	speed := 1.0
+	if drawTime > 0 {
+		var total time.Duration
+		for _, g := range guessers {
+			total += time.Duration(g.GuessTime) * time.Millisecond
+		}
+		avg := total / time.Duration(len(guessers))
+		speed = 1 - min(max(avg.Seconds()/drawTime.Seconds(), 0), 1)
+	}
+
+	// 3. Scale the maximum by both
+	return int(math.Round(MaxDrawerPoints * fraction * (0.5 + 0.5*speed)))
+}
+
 // CalculateGuessPoints determines points based on speed, position, and difficulty
 func CalculateGuessPoints(timeTaken time.Duration, position int, wordDifficulty internal.WordDifficulty) int {
 	// TODO:
 	// 1. Set base points by difficulty:
 	t := timeTaken.Seconds()
 	p := position
-	var basePoints int
+	//    - Easy: 100 points, Medium: 150 points, Hard: 200 points
+	basePoints := wordDifficulty.BasePoints()
 	finalPoints := 0
-	switch wordDifficulty {
-	//    - Easy: 100 points
-	case internal.DifficultyEasy:
-		basePoints = 100
-		//    - Medium: 150 points
-	case internal.DifficultyMedium:
-		basePoints = 150
-		//    - Hard: 200 points
-	case internal.DifficultyHard:
-		basePoints = 200
-	}
 
 	// 2. Apply speed bonus (faster = more points):
 	var speedMultiplier float32
@@ -212,8 +316,8 @@ func CalculateGuessPoints(timeTaken time.Duration, position int, wordDifficulty
 	case t >= 30 && t < 60:
 		//    - < 60s: 100% multiplier
 		speedMultiplier = 1
-	case t > 60:
-		//    - > 60s: 75% multiplier
+	case t >= 60:
+		//    - >= 60s: 75% multiplier
 		speedMultiplier = 0.75
 	}
 
@@ -0,0 +1,485 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func TestOversizedGuessRejected(t *testing.T) {
+	room := newTestRoom(t, "guess-too-long")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, guesserConn := addConnectedPlayer(room, "guesser")
+	_, otherConn := addConnectedPlayer(room, "other")
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+
+	HandleGuessEnhanced(guesser, strings.Repeat("a", MaxGuessLength+1))
+
+	msg, ok := guesserConn.waitFor("error", time.Second)
+	if !ok {
+		t.Fatal("expected an error frame for an oversized guess")
+	}
+	var data internal.ErrorData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("failed to decode error: %v", err)
+	}
+	if data.Code != "guess_too_long" {
+		t.Errorf("expected guess_too_long, got %q", data.Code)
+	}
+	if guesser.TotalGuesses != 0 {
+		t.Errorf("expected oversized guess not to count, TotalGuesses=%d", guesser.TotalGuesses)
+	}
+
+	time.Sleep(50 * time.Millisecond)
+	if got := len(otherConn.messagesOfType("guess_message")); got != 0 {
+		t.Errorf("expected oversized guess not to be broadcast, got %d", got)
+	}
+}
+
+func TestCloseGuessIsPrivate(t *testing.T) {
+	room := newTestRoom(t, "guess-close")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, guesserConn := addConnectedPlayer(room, "guesser")
+	_, otherConn := addConnectedPlayer(room, "other")
+	makeDrawer(room, drawer)
+	room.Word = "Apple"
+
+	HandleGuessEnhanced(guesser, "APLE")
+
+	msg, ok := guesserConn.waitFor("close_guess", time.Second)
+	if !ok {
+		t.Fatal("expected a close_guess nudge for a guess one edit away")
+	}
+	if strings.Contains(strings.ToLower(string(msg.Data)), "apple") {
+		t.Errorf("expected close_guess not to reveal the word, got %s", msg.Data)
+	}
+
+	// Two edits away is an ordinary wrong guess
+	HandleGuessEnhanced(guesser, "amble")
+	if _, ok := otherConn.waitFor("guess_message", time.Second); !ok {
+		t.Fatal("expected a distant guess to be broadcast")
+	}
+	if got := len(guesserConn.messagesOfType("close_guess")); got != 1 {
+		t.Errorf("expected no close_guess for a distance-2 guess, got %d total", got)
+	}
+	for _, m := range otherConn.messagesOfType("guess_message") {
+		if strings.Contains(string(m.Data), "APLE") {
+			t.Errorf("expected the close guess not to be broadcast, got %s", m.Data)
+		}
+	}
+	if guesser.TotalGuesses != 2 {
+		t.Errorf("expected both guesses to count, TotalGuesses=%d", guesser.TotalGuesses)
+	}
+}
+
+func TestNormalGuessAccepted(t *testing.T) {
+	room := newTestRoom(t, "guess-normal")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, _ := addConnectedPlayer(room, "guesser")
+	_, otherConn := addConnectedPlayer(room, "other")
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+
+	HandleGuessEnhanced(guesser, strings.Repeat("b", MaxGuessLength))
+
+	if _, ok := otherConn.waitFor("guess_message", time.Second); !ok {
+		t.Fatal("expected a normal-length guess to be broadcast")
+	}
+	if guesser.TotalGuesses != 1 {
+		t.Errorf("expected guess to be counted, TotalGuesses=%d", guesser.TotalGuesses)
+	}
+}
+
+func TestIgnoreArticlesMatchesEitherDirection(t *testing.T) {
+	cases := []struct {
+		word, guess string
+		ignore      bool
+		want        bool
+	}{
+		{"eiffel tower", "the eiffel tower", true, true},
+		{"the eiffel tower", "Eiffel Tower", true, true},
+		{"an apple", "a apple", true, true},
+		{"eiffel tower", "the eiffel tower", false, false},
+	}
+	for _, c := range cases {
+		room := newTestRoom(t, "guess-articles")
+		drawer, _ := addConnectedPlayer(room, "drawer")
+		guesser, _ := addConnectedPlayer(room, "guesser")
+		addConnectedPlayer(room, "other")
+		makeDrawer(room, drawer)
+		room.Word = c.word
+		room.IgnoreArticles = c.ignore
+
+		HandleGuessEnhanced(guesser, c.guess)
+
+		room.Mu.RLock()
+		got := guesser.HasGuessed
+		room.Mu.RUnlock()
+		if got != c.want {
+			t.Errorf("word=%q guess=%q ignore=%v: expected correct=%v, got %v",
+				c.word, c.guess, c.ignore, c.want, got)
+		}
+	}
+}
+
+func TestCalculateGuessPoints(t *testing.T) {
+	cases := []struct {
+		name       string
+		timeTaken  time.Duration
+		position   int
+		difficulty internal.WordDifficulty
+		want       int
+	}{
+		{"easy fast first", 5 * time.Second, 1, internal.DifficultyEasy, 150},
+		{"easy slow fourth", 70 * time.Second, 4, internal.DifficultyEasy, 30},
+		{"medium fast first", 5 * time.Second, 1, internal.DifficultyMedium, 225},
+		{"medium slow fourth", 70 * time.Second, 4, internal.DifficultyMedium, 45},
+		{"hard fast first", 5 * time.Second, 1, internal.DifficultyHard, 300},
+		{"hard slow fourth", 70 * time.Second, 4, internal.DifficultyHard, 60},
+		{"exactly a minute", 60 * time.Second, 1, internal.DifficultyEasy, 75},
+		{"second at twenty seconds", 20 * time.Second, 2, internal.DifficultyEasy, 100},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := CalculateGuessPoints(c.timeTaken, c.position, c.difficulty); got != c.want {
+				t.Errorf("CalculateGuessPoints(%v, %d, %q) = %d, want %d",
+					c.timeTaken, c.position, c.difficulty, got, c.want)
+			}
+		})
+	}
+}
+
+func TestCalculateDrawerPoints(t *testing.T) {
+	guesses := func(ms ...int) []internal.PlayerGuess {
+		out := make([]internal.PlayerGuess, 0, len(ms))
+		for i, m := range ms {
+			out = append(out, internal.PlayerGuess{PlayerID: fmt.Sprintf("p%d", i), GuessTime: m, IsCorrect: true})
+		}
+		return out
+	}
+	turn := 80 * time.Second
+
+	cases := []struct {
+		name     string
+		guessers []internal.PlayerGuess
+		eligible int
+		want     int
+	}{
+		{"nobody guessed", nil, 4, 0},
+		{"everyone instantly", guesses(0, 0, 0, 0), 4, MaxDrawerPoints},
+		{"everyone at the buzzer", guesses(80000, 80000), 2, MaxDrawerPoints / 2},
+		{"half the room instantly", guesses(0, 0), 4, MaxDrawerPoints / 2},
+		{"one of two, halfway through", guesses(40000), 2, 75},
+		{"guesser who left still counts", guesses(0, 0), 1, MaxDrawerPoints},
+		{"duplicate entry counted once", append(guesses(0), guesses(0)...), 2, MaxDrawerPoints / 2},
+	}
+	for _, c := range cases {
+		if got := CalculateDrawerPoints(c.guessers, c.eligible, turn); got != c.want {
+			t.Errorf("%s: expected %d drawer points, got %d", c.name, c.want, got)
+		}
+	}
+}
+
+func TestDoubleCorrectGuessCreditedOnce(t *testing.T) {
+	room := newTestRoom(t, "double-guess")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	alice, _ := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	room.DrawingStarted = true
+	room.Timer.StartTime = time.Now()
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleGuessEnhanced(alice, "apple")
+	room.Mu.Lock()
+	score := alice.Score
+	// As if HasGuessed were lost, e.g. by a future reconnect path
+	alice.HasGuessed = false
+	room.Mu.Unlock()
+	HandleGuessEnhanced(alice, "apple")
+
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+	if len(room.CorrectGuessers) != 1 || alice.Score != score || alice.CorrectGuesses != 1 {
+		t.Fatalf("expected one credit for alice, got %d entries, score %d (was %d)",
+			len(room.CorrectGuessers), alice.Score, score)
+	}
+	rs := recordRoundStats(room)
+	want := CalculateDrawerPoints(rs.CorrectGuessers, 2, drawingPhaseDuration(room))
+	if want == 0 || rs.DrawerPoints != want || drawer.Score != want {
+		t.Errorf("expected the drawer credited for one guesser (%d), got %d (score %d)",
+			want, rs.DrawerPoints, drawer.Score)
+	}
+}
+
+func TestDrawerScoredAtRoundEnd(t *testing.T) {
+	t.Run("nobody guessed", func(t *testing.T) {
+		room := newTestRoom(t, "drawer-points-none")
+		drawer, drawerConn := addConnectedPlayer(room, "drawer")
+		addConnectedPlayer(room, "alice")
+		addConnectedPlayer(room, "bob")
+		makeDrawer(room, drawer)
+		room.Word = "apple"
+		room.Timer.StartTime = time.Now()
+		t.Cleanup(func() { CancelPhaseTimer(room) })
+
+		StartRevealingPhase(room)
+
+		if _, ok := drawerConn.waitFor("round_end", time.Second); !ok {
+			t.Fatal("expected round_end when the round is revealed")
+		}
+		room.Mu.RLock()
+		defer room.Mu.RUnlock()
+		if drawer.Score != 0 || room.RoundStats[0].DrawerPoints != 0 {
+			t.Errorf("expected no drawer points when nobody guessed, got score %d", drawer.Score)
+		}
+	})
+
+	t.Run("everybody guessed quickly", func(t *testing.T) {
+		room := newTestRoom(t, "drawer-points-all")
+		drawer, _ := addConnectedPlayer(room, "drawer")
+		alice, _ := addConnectedPlayer(room, "alice")
+		bob, _ := addConnectedPlayer(room, "bob")
+		room.PlayerOrder = []string{"drawer", "alice", "bob"}
+		makeDrawer(room, drawer)
+		room.Word = "apple"
+		room.DrawingStarted = true
+		room.Timer.StartTime = time.Now()
+		t.Cleanup(func() { CancelPhaseTimer(room) })
+
+		HandleGuessEnhanced(alice, "apple")
+		room.Mu.RLock()
+		midTurn := drawer.Score
+		room.Mu.RUnlock()
+		if midTurn != 0 {
+			t.Errorf("expected no drawer points before the turn ends, got %d", midTurn)
+		}
+		// The last guess ends the turn without a reveal
+		HandleGuessEnhanced(bob, "apple")
+
+		room.Mu.RLock()
+		defer room.Mu.RUnlock()
+		if drawer.Score < MaxDrawerPoints*9/10 {
+			t.Errorf("expected close to %d drawer points for two instant guesses, got %d", MaxDrawerPoints, drawer.Score)
+		}
+		if len(room.RoundStats) != 1 || room.RoundStats[0].DrawerPoints != drawer.Score {
+			t.Errorf("expected the round stats to record the drawer's %d points, got %+v", drawer.Score, room.RoundStats)
+		}
+	})
+}
+
+func TestRoundEndCarriesEachGuessersPoints(t *testing.T) {
+	room := newTestRoom(t, "round-end-points")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	first, _ := addConnectedPlayer(room, "first")
+	second, _ := addConnectedPlayer(room, "second")
+	addConnectedPlayer(room, "stumped")
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	room.Timer.StartTime = time.Now()
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleGuessEnhanced(first, "apple")
+	HandleGuessEnhanced(second, "apple")
+	// The drawing timer runs out before "stumped" guesses
+	StartRevealingPhase(room)
+
+	msg, ok := drawerConn.waitFor("round_end", time.Second)
+	if !ok {
+		t.Fatal("expected round_end when the round is revealed")
+	}
+	var data internal.RoundEndData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad round_end payload: %v", err)
+	}
+	if len(data.CorrectGuessers) != 2 {
+		t.Fatalf("expected both guessers, got %+v", data.CorrectGuessers)
+	}
+	for i, p := range []*internal.Player{first, second} {
+		got := data.CorrectGuessers[i]
+		if got.PlayerID != p.Id {
+			t.Errorf("position %d: expected %s, got %s", i+1, p.Id, got.PlayerID)
+		}
+		if got.Points <= 0 || got.Points != p.Score {
+			t.Errorf("%s: expected round points %d, got %d", p.Id, p.Score, got.Points)
+		}
+	}
+	if data.CorrectGuessers[0].Points <= data.CorrectGuessers[1].Points {
+		t.Errorf("expected the first guesser to earn more, got %+v", data.CorrectGuessers)
+	}
+}
+
+func TestRoundEndScoreboardSumsIntoTotals(t *testing.T) {
+	room := newTestRoom(t, "round-end-scoreboard")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	first, _ := addConnectedPlayer(room, "first")
+	second, _ := addConnectedPlayer(room, "second")
+	stumped, _ := addConnectedPlayer(room, "stumped")
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	room.Timer.StartTime = time.Now()
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	before := map[string]int{}
+	for _, p := range []*internal.Player{drawer, first, second, stumped} {
+		p.Score = 100
+		before[p.Id] = p.Score
+	}
+
+	HandleGuessEnhanced(first, "apple")
+	HandleGuessEnhanced(second, "apple")
+	StartRevealingPhase(room)
+
+	msg, ok := drawerConn.waitFor("round_end", time.Second)
+	if !ok {
+		t.Fatal("expected round_end when the round is revealed")
+	}
+	var data internal.RoundEndData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad round_end payload: %v", err)
+	}
+	if len(data.Scoreboard) != 4 {
+		t.Fatalf("expected a scoreboard line per player, got %+v", data.Scoreboard)
+	}
+	rounds := map[string]int{}
+	for i, line := range data.Scoreboard {
+		if line.TotalScore != before[line.PlayerID]+line.RoundPoints {
+			t.Errorf("%s: expected total %d + %d, got %d",
+				line.PlayerID, before[line.PlayerID], line.RoundPoints, line.TotalScore)
+		}
+		if i > 0 && line.TotalScore > data.Scoreboard[i-1].TotalScore {
+			t.Errorf("expected the scoreboard sorted by total, got %+v", data.Scoreboard)
+		}
+		rounds[line.PlayerID] = line.RoundPoints
+	}
+	if rounds["drawer"] != data.DrawerPoints || data.DrawerPoints <= 0 {
+		t.Errorf("expected the drawer's points for two guessers (%d), got %d", data.DrawerPoints, rounds["drawer"])
+	}
+	if rounds["stumped"] != 0 || rounds["first"] <= rounds["second"] {
+		t.Errorf("unexpected round points %v", rounds)
+	}
+}
+
+func TestRequestStatsRepliesPrivately(t *testing.T) {
+	room := newTestRoom(t, "request-stats")
+	alice, aliceConn := addConnectedPlayer(room, "alice")
+	_, bobConn := addConnectedPlayer(room, "bob")
+	alice.TotalGuesses, alice.CorrectGuesses = 2, 1
+
+	HandleRequestStats(alice)
+
+	msg, ok := aliceConn.waitFor("player_stats", time.Second)
+	if !ok {
+		t.Fatal("expected player_stats to be sent to the requester")
+	}
+	var stats map[string]any
+	if err := json.Unmarshal(msg.Data, &stats); err != nil {
+		t.Fatalf("bad player_stats payload: %v", err)
+	}
+	if stats["accuracy"] != 0.5 || stats["player_id"] != "alice" {
+		t.Errorf("unexpected stats %v", stats)
+	}
+	if got := len(bobConn.messagesOfType("player_stats")); got != 0 {
+		t.Errorf("expected stats not to reach other players, got %d", got)
+	}
+}
+
+func TestGuessProgressUpdatesAsPlayersGuess(t *testing.T) {
+	room := newTestRoom(t, "guess-progress")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	alice, aliceConn := addConnectedPlayer(room, "alice")
+	bob, _ := addConnectedPlayer(room, "bob")
+	addConnectedPlayer(room, "carol")
+	room.Current = drawer
+	room.Word = "apple"
+	StartDrawingPhase(room)
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	msg, ok := aliceConn.waitFor("drawing_phase", time.Second)
+	if !ok {
+		t.Fatal("expected drawing_phase")
+	}
+	var masked internal.MaskedWordData
+	if err := json.Unmarshal(msg.Data, &masked); err != nil {
+		t.Fatalf("bad drawing_phase payload: %v", err)
+	}
+	if p := masked.GuessProgress; p == nil || p.Guessed != 0 || p.Total != 3 {
+		t.Fatalf("expected 0/3 guessed at the start of the turn, got %+v", p)
+	}
+
+	latestProgress := func(want int) *internal.GuessProgress {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for {
+			if msgs := aliceConn.messagesOfType("game_state_update"); len(msgs) > 0 {
+				var state internal.GameStateData
+				if err := json.Unmarshal(msgs[len(msgs)-1].Data, &state); err != nil {
+					t.Fatalf("bad game_state_update payload: %v", err)
+				}
+				if p := state.GuessProgress; p != nil && p.Guessed == want {
+					return p
+				}
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("expected a game_state_update with %d guessed", want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+
+	HandleGuessEnhanced(alice, "apple")
+	if p := latestProgress(1); p.Total != 3 {
+		t.Errorf("expected 1/3 guessed, got %+v", p)
+	}
+	HandleGuessEnhanced(bob, "apple")
+	if p := latestProgress(2); p.Total != 3 {
+		t.Errorf("expected 2/3 guessed, got %+v", p)
+	}
+}
+
+func TestPendingGuesserNotToldWhoGuessed(t *testing.T) {
+	room := newTestRoom(t, "guess-private")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	solver, solverConn := addConnectedPlayer(room, "solver")
+	_, pendingConn := addConnectedPlayer(room, "pending")
+	addConnectedPlayer(room, "other")
+	room.Current = drawer
+	room.Word = "apple"
+	StartDrawingPhase(room)
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleGuessEnhanced(solver, "apple")
+	for name, conn := range map[string]*fakeConn{"drawer": drawerConn, "solver": solverConn} {
+		if _, ok := conn.waitFor("guess_result", time.Second); !ok {
+			t.Errorf("expected the %s to get the guess_result", name)
+		}
+	}
+	BroadcastGameState(room)
+	flushBroadcasts(t, room, pendingConn)
+
+	for _, msg := range pendingConn.messages() {
+		switch msg.Type {
+		case "guess_result":
+			t.Errorf("expected no guess_result for a pending guesser, got %s", msg.Data)
+		case "game_state_update":
+			var state internal.GameStateData
+			if err := json.Unmarshal(msg.Data, &state); err != nil {
+				t.Fatalf("bad game_state_update payload: %v", err)
+			}
+			if len(state.CorrectGuessers) != 0 {
+				t.Errorf("expected no correct guessers for a pending guesser, got %+v", state.CorrectGuessers)
+			}
+			for _, p := range state.Players {
+				if p.ID == solver.Id && (p.HasGuessed || p.Score != 0 || p.CorrectGuesses != 0 || p.TotalGuesses != 0) {
+					t.Errorf("expected the solver's turn-start stats, got %+v", p)
+				}
+			}
+		}
+	}
+}
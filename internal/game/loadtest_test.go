@@ -0,0 +1,91 @@
+package game
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// discardConn is an in-memory connection that pays the JSON encoding cost of
+// every write but never touches the network
+type discardConn struct{}
+
+func (discardConn) WriteJSON(v any) error {
+	_, err := json.Marshal(v)
+	return err
+}
+
+func (discardConn) ReadMessage() (int, []byte, error) {
+	return 0, nil, errors.New("discardConn: read not supported")
+}
+
+func (discardConn) SetReadDeadline(time.Time) error  { return nil }
+func (discardConn) SetWriteDeadline(time.Time) error { return nil }
+func (discardConn) Close() error                     { return nil }
+
+// spawnSyntheticPlayers adds n connected players backed by discardConn to room
+func spawnSyntheticPlayers(room *internal.Room, n int) []*internal.Player {
+	room.Mu.Lock()
+	defer room.Mu.Unlock()
+
+	players := make([]*internal.Player, 0, n)
+	for i := 0; i < n; i++ {
+		player := &internal.Player{
+			Id:           fmt.Sprintf("synthetic-%d", i),
+			Username:     fmt.Sprintf("bot%d", i),
+			Conn:         discardConn{},
+			Room:         room,
+			IsConnected:  true,
+			CanvasWidth:  internal.CanvasWidth,
+			CanvasHeight: internal.CanvasHeight,
+		}
+		room.Players[player.Id] = player
+		room.PlayerOrder = append(room.PlayerOrder, player.Id)
+		players = append(players, player)
+	}
+	return players
+}
+
+// drainBroadcasts blocks until room's broadcast worker has sent everything
+// queued so far
+func drainBroadcasts(room *internal.Room) {
+	queue, _ := room.BroadcastQueue(BroadcastQueueSize)
+	done := make(chan struct{})
+	queue <- func() { close(done) }
+	<-done
+}
+
+// BenchmarkSafeBroadcastToRoom measures delivering one broadcast to every
+// player: each iteration waits for the worker to finish the send, so the
+// cost grows with the player count rather than timing the enqueue alone
+func BenchmarkSafeBroadcastToRoom(b *testing.B) {
+	prev := log.Writer()
+	log.SetOutput(io.Discard)
+	b.Cleanup(func() { log.SetOutput(prev) })
+
+	x, y := 3, 4
+	msg := internal.Message[internal.PixelMessage]{
+		Type: string(internal.PixelPlace),
+		Data: internal.PixelMessage{Type: internal.PixelPlace, X: &x, Y: &y, Color: "#ff0000"},
+	}
+
+	for _, n := range []int{2, 8, 32, 128} {
+		b.Run(fmt.Sprintf("players=%d", n), func(b *testing.B) {
+			room := newTestRoom(b, fmt.Sprintf("bench-%d", n))
+			spawnSyntheticPlayers(room, n)
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				SafeBroadcastToRoom(room, msg)
+				drainBroadcasts(room)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package game
 import (
 	"fmt"
 	"log"
+	"math/rand"
 	"time"
 
 	"github.com/scythe504/skribblr-backend/internal"
@@ -24,27 +25,21 @@ func HandlePlayerReady(player *internal.Player, ready bool) {
 		log.Printf("[HandlePlayerReady] Room %s not in lobby phase (phase=%v)",
 			room.Id, room.Phase)
 		room.Mu.Unlock()
+		SendErrorToPlayer(player, "not_in_lobby", "Ready status can only change in the lobby")
 		return
 	}
 
 	// Update state
 	player.IsReady = ready
+	player.IsIdle = false
+	if !ready {
+		player.UnreadySince = time.Now()
+	}
 	room.PlayersReady[player.Id] = ready
 
 	// Prepare snapshot for broadcast
-	lobbyUpdate := internal.Message[any]{
-		Type: "lobby_update",
-		Data: map[string]any{
-			"player_id":     player.Id,
-			"username":      player.Username,
-			"is_ready":      ready,
-			"ready_count":   len(room.PlayersReady),
-			"total_players": len(room.Players),
-		},
-	}
-
-	allReady := room.AreAllPlayersReady()
-	enoughPlayers := len(room.Players) >= MinPlayersToStart
+	lobbyUpdate := lobbyUpdateMessage(room, player)
+	canStart := readyToStart(room)
 
 	log.Printf("[HandlePlayerReady] Room %s: Player %s (%s) ready=%v, ReadyCount=%d/%d",
 		room.Id, player.Id, player.Username, ready, len(room.PlayersReady), len(room.Players))
@@ -52,11 +47,16 @@ func HandlePlayerReady(player *internal.Player, ready bool) {
 	room.Mu.Unlock()
 	// --- End critical section ---
 
-	// Safe broadcast (async, no lock held)
-	go SafeBroadcastToRoom(room, lobbyUpdate)
+	// Safe broadcast (queued, no lock held)
+	queueBroadcast(room, lobbyUpdate, nil)
+
+	// Un-readying restarts the idle clock
+	if !ready {
+		scheduleLobbyIdleCheck(player)
+	}
 
 	// If all players ready, try starting game
-	if allReady && enoughPlayers {
+	if canStart {
 		log.Printf("[HandlePlayerReady] Room %s: All players ready. Starting game...", room.Id)
 		go func() {
 			if err := StartGame(room); err != nil {
@@ -66,17 +66,62 @@ func HandlePlayerReady(player *internal.Player, ready bool) {
 	}
 }
 
+// lobbyUpdateMessage announces player's ready state. Caller must hold room.Mu.
+func lobbyUpdateMessage(room *internal.Room, player *internal.Player) internal.Message[any] {
+	return internal.Message[any]{
+		Type: "lobby_update",
+		Data: map[string]any{
+			"player_id":     player.Id,
+			"username":      player.Username,
+			"is_ready":      player.IsReady,
+			"ready_count":   len(room.PlayersReady),
+			"total_players": len(room.Players),
+		},
+	}
+}
+
+// readyToStart reports whether every connected player is ready and there are
+// enough of them to start. Caller must hold room.Mu.
+func readyToStart(room *internal.Room) bool {
+	return room.AreAllPlayersReady() && room.GetPlayerCount() >= GetGameConfig().MinPlayersToStart
+}
+
+// HandleStartGame starts player's room's game if player is its host
+func HandleStartGame(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleStartGame] Player %s has no room reference", player.Username)
+		return
+	}
+
+	room.Mu.RLock()
+	isHost := room.HostId == player.Id
+	room.Mu.RUnlock()
+	if !isHost {
+		log.Printf("[HandleStartGame] room=%s: non-host %s tried to start the game", room.Id, player.Id)
+		SendErrorToPlayer(player, "not_host", "Only the host can start the game")
+		return
+	}
+
+	if err := StartGame(room); err != nil {
+		log.Printf("[HandleStartGame] room=%s: host %s could not start the game: %v", room.Id, player.Id, err)
+		SendErrorToPlayer(player, "cannot_start", err.Error())
+	}
+}
+
 // StartGame initializes a new game when conditions are met.
 func StartGame(room *internal.Room) error {
 	// --- Critical section ---
+	minPlayers := GetGameConfig().MinPlayersToStart
 	room.Mu.Lock()
 
-	if len(room.Players) < MinPlayersToStart {
+	// Seats held for dropped players don't count until they reconnect
+	if connected := room.GetPlayerCount(); connected < minPlayers {
 		log.Printf("[StartGame] Room %s: Not enough players (%d/%d)",
-			room.Id, len(room.Players), MinPlayersToStart)
+			room.Id, connected, minPlayers)
 		room.Mu.Unlock()
 		return fmt.Errorf("not enough players to start game: %d/%d",
-			len(room.Players), MinPlayersToStart)
+			connected, minPlayers)
 	}
 	if !room.AreAllPlayersReady() {
 		log.Printf("[StartGame] Room %s: Not all players ready", room.Id)
@@ -86,38 +131,60 @@ func StartGame(room *internal.Room) error {
 
 	// Initialize state
 	room.HasGameStarted = true
+	room.StartedAt = now()
 	room.RoundNumber = 1
+	room.TurnsPlayed = 0
 	room.CurrentIndex = 0
 	room.RoundStats = make([]internal.RoundStats, 0)
+	room.DepartedPlayers = make(map[string]*internal.Player)
 	room.ResetPlayerGuessState()
 
-	// Build PlayerOrder
+	// Build PlayerOrder (shuffled so the rotation is not biased by join order)
 	room.PlayerOrder = make([]string, 0, len(room.Players))
 	for playerId, isReady := range room.PlayersReady {
 		if player := room.Players[playerId]; player != nil && player.IsConnected && isReady {
 			room.PlayerOrder = append(room.PlayerOrder, playerId)
 		}
 	}
+	rand.Shuffle(len(room.PlayerOrder), func(i, j int) {
+		room.PlayerOrder[i], room.PlayerOrder[j] = room.PlayerOrder[j], room.PlayerOrder[i]
+	})
+
+	// Ready flags only mean something in the lobby; drop them for the game
+	room.PlayersReady = make(map[string]bool)
+	for _, p := range room.Players {
+		p.IsReady = false
+	}
 
 	// Snapshot
 	playerOrderCopy := append([]string(nil), room.PlayerOrder...)
-	playersSnapshot := make([]map[string]any, 0, len(room.Players))
+	playersSnapshot := make([]*internal.Player, 0, len(room.Players))
 	for _, p := range room.Players {
-		playersSnapshot = append(playersSnapshot, map[string]any{
-			"id":       p.Id,
-			"username": p.Username,
-			"score":    p.Score,
-		})
+		playersSnapshot = append(playersSnapshot, p.ToPublicPlayer())
+	}
+
+	gameStartedData := map[string]any{
+		"message":       "Game has started!",
+		"room_id":       room.Id,
+		"players_count": len(playerOrderCopy),
+		"players":       playersSnapshot,
+		"config":        roomConfigData(room),
+	}
+	if RevealDrawOrder {
+		drawOrder := make([]map[string]any, 0, len(playerOrderCopy))
+		for idx, playerId := range playerOrderCopy {
+			drawOrder = append(drawOrder, map[string]any{
+				"position": idx + 1,
+				"id":       playerId,
+				"username": room.Players[playerId].Username,
+			})
+		}
+		gameStartedData["draw_order"] = drawOrder
 	}
 
 	gameStartedMsg := internal.Message[any]{
 		Type: "game_started",
-		Data: map[string]any{
-			"message":       "Game has started!",
-			"room_id":       room.Id,
-			"players_count": len(playerOrderCopy),
-			"players":       playersSnapshot,
-		},
+		Data: gameStartedData,
 	}
 
 	log.Printf("[StartGame] Room %s: Initialized game. Round=%d, PlayerOrder=%v",
@@ -133,25 +200,46 @@ func StartGame(room *internal.Room) error {
 	log.Printf("[StartGame] Room %s: Broadcasting game_started to %d players",
 		room.Id, len(playerOrderCopy))
 	SafeBroadcastToRoom(room, gameStartedMsg)
+	NotifyWebhook(room, gameStartedMsg.Type, gameStartedMsg.Data)
 
 	return nil
 }
 
-// ResetRoomToLobby returns room to waiting-for-players state
+// ResetRoomToLobby returns room to waiting-for-players state for a new game:
+// scores are wiped along with the rest of the game state. Used once a game's
+// results have been shown.
 func ResetRoomToLobby(room *internal.Room) {
+	resetRoomToLobby(room, false)
+}
+
+// PauseRoomToLobby returns room to waiting-for-players state but keeps every
+// player's score, so a game cut short (e.g. by a mass disconnect) picks up
+// where it left off once enough players ready up again. StartGame never
+// touches scores; only ResetRoomToLobby clears them.
+func PauseRoomToLobby(room *internal.Room) {
+	resetRoomToLobby(room, true)
+}
+
+func resetRoomToLobby(room *internal.Room, keepScores bool) {
 	// TODO:
-	room.Mu.Lock()
-	// 1. Cancel all active timers
+	// 1. Cancel all active timers (CancelPhaseTimer takes the room lock itself)
 	CancelPhaseTimer(room)
+	room.Mu.Lock()
 	// 2. Set Phase = PhaseLobby
-	room.Phase = internal.PhaseLobby
+	setPhase(room, internal.PhaseLobby)
 	// 3. Set HasGameStarted = false
 	room.HasGameStarted = false
 	// 4. Reset all game state variables
 	room.CorrectGuessers = make([]internal.PlayerGuess, 0)
+	room.CreditedGuessers = make(map[string]bool)
 	room.Word = ""
+	room.WordEmoji = ""
+	room.WordDifficulty = ""
 	room.RoundNumber = 1
-	room.WordChoices = make([]string, 0, 3)
+	room.TurnsPlayed = 0
+	room.WordChoices = make([]internal.Word, 0, 3)
+	room.DrawingStarted = false
+	room.RoundRecorded = false
 	room.Current = nil
 	room.CurrentIndex = 0
 	room.PlayerOrder = make([]string, 0)
@@ -159,29 +247,137 @@ func ResetRoomToLobby(room *internal.Room) {
 	room.PlayersReady = make(map[string]bool)
 	for playerId := range room.Players {
 		room.Players[playerId].IsReady = false
+		room.Players[playerId].IsIdle = false
+		room.Players[playerId].UnreadySince = time.Now()
 		room.Players[playerId].ResetRoundState()
 	}
-	// 6. Clear scores, round stats, canvas state
+	// 6. Clear round stats, canvas state and, for a new game, scores
 	room.CanvasState = make([]internal.PixelMessage, 0)
+	room.CanvasBackground = internal.DefaultCanvasBackground
+	resetCanvasHistory(room)
 	room.RoundStats = make([]internal.RoundStats, 0)
-	for playerID := range room.Players {
-		room.Players[playerID].Score = 0
+	room.DepartedPlayers = make(map[string]*internal.Player)
+	room.LastResults = nil
+	// A new game may draw any word again; a paused one carries on avoiding them
+	if !keepScores {
+		for _, p := range room.Players {
+			p.Score = 0
+		}
+		room.UsedWords = nil
+	}
+	// 7. Broadcast lobby_reset message, with player copies since it is
+	// serialized after the lock is released
+	publicPlayers := make(map[string]*internal.Player, len(room.Players))
+	for id, p := range room.Players {
+		publicPlayers[id] = p.ToPublicPlayer()
+	}
+	resetMessage := fmt.Sprintf("Lobby %s has been reset for new game", room.Id)
+	if keepScores {
+		resetMessage = fmt.Sprintf("Game in %s paused, scores are kept for when it resumes", room.Id)
 	}
-	// 7. Broadcast lobby_reset message
 	lobbyResetMessage := internal.Message[any]{
 		Type: "lobby_reset",
 		Data: map[string]any{
-			"message":          fmt.Sprintf("Lobby %s has been reset for new game", room.Id),
+			"message":          resetMessage,
+			"scores_kept":      keepScores,
 			"room_id":          room.Id,
 			"timestamp":        time.Now().UnixMilli(),
-			"players":          room.Players,
+			"players":          publicPlayers,
 			"phase":            room.Phase,
-			"current_drawer":   room.Current,
+			"current_drawer":   nil, // cleared above
 			"round_number":     room.RoundNumber,
 			"max_rounds":       room.MaxRounds,
 			"correct_guessers": room.CorrectGuessers,
 		},
 	}
+	lobbyPlayers := make([]*internal.Player, 0, len(room.Players))
+	for _, p := range room.Players {
+		lobbyPlayers = append(lobbyPlayers, p)
+	}
 	room.Mu.Unlock()
 	SafeBroadcastToRoom(room, lobbyResetMessage)
+
+	// 8. Everyone is unready again, so restart the idle clocks
+	for _, p := range lobbyPlayers {
+		scheduleLobbyIdleCheck(p)
+	}
+}
+
+// scheduleLobbyIdleCheck arms a one-shot check that flags player as idle if
+// they are still unready in the lobby after LobbyIdleTimeout
+func scheduleLobbyIdleCheck(player *internal.Player) {
+	timeout := LobbyIdleTimeout
+	if timeout <= 0 {
+		return
+	}
+	room := player.Room
+	time.AfterFunc(timeout, func() {
+		if room != nil && room.Context != nil && room.Context.Err() != nil {
+			return
+		}
+		checkLobbyIdle(player)
+	})
+}
+
+// checkLobbyIdle applies LobbyIdleAction to player if they never readied up
+func checkLobbyIdle(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		return
+	}
+
+	room.Mu.Lock()
+	stillIdle := room.Phase == internal.PhaseLobby &&
+		room.Players[player.Id] == player &&
+		player.IsConnected && !player.IsReady && !player.IsIdle &&
+		time.Since(player.UnreadySince) >= LobbyIdleTimeout
+	if !stillIdle {
+		room.Mu.Unlock()
+		return
+	}
+
+	action := LobbyIdleAction
+	if action != LobbyIdleKick {
+		action = LobbyIdleExclude
+		player.IsIdle = true
+	}
+
+	idleMessage := internal.Message[any]{
+		Type: "player_idle",
+		Data: map[string]any{
+			"player_id": player.Id,
+			"username":  player.Username,
+			"action":    action,
+			"message":   fmt.Sprintf("%s has been idle in the lobby", player.Username),
+		},
+	}
+
+	readyPlayers := 0
+	for _, p := range room.Players {
+		if p.IsConnected && p.IsReady {
+			readyPlayers++
+		}
+	}
+	canStart := action == LobbyIdleExclude && room.AreAllPlayersReady() && readyPlayers >= GetGameConfig().MinPlayersToStart
+	room.Mu.Unlock()
+
+	log.Printf("[checkLobbyIdle] Room %s: Player %s (%s) idle in lobby, action=%s",
+		room.Id, player.Id, player.Username, action)
+	SafeBroadcastToRoom(room, idleMessage)
+
+	if action == LobbyIdleKick {
+		if player.Conn != nil {
+			// The read loop notices the closed connection and runs removePlayer
+			player.Conn.Close()
+		}
+		return
+	}
+
+	// Excluding the idle player may be all that was blocking the start
+	if canStart {
+		log.Printf("[checkLobbyIdle] Room %s: Remaining players ready, starting game", room.Id)
+		if err := StartGame(room); err != nil {
+			log.Printf("[checkLobbyIdle] Failed to start game in room %s: %v", room.Id, err)
+		}
+	}
 }
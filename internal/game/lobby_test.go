@@ -0,0 +1,314 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// readyUp marks every player in room as ready, as HandlePlayerReady would
+func readyUp(room *internal.Room) {
+	for id, p := range room.Players {
+		p.IsReady = true
+		room.PlayersReady[id] = true
+	}
+}
+
+func TestGameStartedIncludesDrawOrder(t *testing.T) {
+	room := newTestRoom(t, "draw-order")
+	_, conn := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	addConnectedPlayer(room, "carol")
+	readyUp(room)
+
+	if err := StartGame(room); err != nil {
+		t.Fatalf("StartGame failed: %v", err)
+	}
+
+	msg, ok := conn.waitFor("game_started", time.Second)
+	if !ok {
+		t.Fatal("expected game_started to be broadcast")
+	}
+
+	var data struct {
+		DrawOrder []struct {
+			Position int    `json:"position"`
+			Id       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"draw_order"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("failed to decode game_started: %v", err)
+	}
+
+	room.Mu.RLock()
+	order := append([]string(nil), room.PlayerOrder...)
+	room.Mu.RUnlock()
+
+	if len(data.DrawOrder) != 3 || len(order) != 3 {
+		t.Fatalf("expected 3 entries in draw order, got %d (room order %v)", len(data.DrawOrder), order)
+	}
+	for idx, entry := range data.DrawOrder {
+		if entry.Position != idx+1 || entry.Id != order[idx] || entry.Username != order[idx] {
+			t.Errorf("draw_order[%d] = %+v; want position %d id %s", idx, entry, idx+1, order[idx])
+		}
+	}
+}
+
+func TestGameStartedCarriesPublicPlayersAndConfig(t *testing.T) {
+	room := newTestRoom(t, "started-payload")
+	_, conn := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	readyUp(room)
+
+	if err := StartGame(room); err != nil {
+		t.Fatalf("StartGame failed: %v", err)
+	}
+	msg, ok := conn.waitFor("game_started", time.Second)
+	if !ok {
+		t.Fatal("expected game_started to be broadcast")
+	}
+
+	var data struct {
+		Players   []map[string]any `json:"players"`
+		DrawOrder []struct {
+			Id string `json:"id"`
+		} `json:"draw_order"`
+		Config struct {
+			MaxRounds  int   `json:"max_rounds"`
+			DrawTimeMs int64 `json:"draw_time_ms"`
+			MaxPlayers int   `json:"max_players"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("failed to decode game_started: %v", err)
+	}
+
+	if len(data.Players) != 2 {
+		t.Fatalf("expected 2 players, got %d", len(data.Players))
+	}
+	for _, p := range data.Players {
+		for _, field := range []string{"id", "username", "score", "is_ready", "is_connected"} {
+			if _, ok := p[field]; !ok {
+				t.Errorf("expected public player field %q, got %v", field, p)
+			}
+		}
+	}
+	if len(data.DrawOrder) != 2 {
+		t.Errorf("expected the draw order for both players, got %+v", data.DrawOrder)
+	}
+	if data.Config.MaxRounds != room.MaxRounds ||
+		data.Config.DrawTimeMs != internal.DrawingPhaseDuration.Milliseconds() ||
+		data.Config.MaxPlayers != GetGameConfig().MaxPlayersPerRoom {
+		t.Errorf("unexpected config %+v", data.Config)
+	}
+}
+
+func TestReadyFlagsClearedDuringGame(t *testing.T) {
+	room := newTestRoom(t, "ready-cleared")
+	alice, aliceConn := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	readyUp(room)
+
+	if err := StartGame(room); err != nil {
+		t.Fatalf("StartGame failed: %v", err)
+	}
+	room.Mu.RLock()
+	readyCount, orderLen := len(room.PlayersReady), len(room.PlayerOrder)
+	room.Mu.RUnlock()
+	if readyCount != 0 {
+		t.Errorf("expected PlayersReady to be empty during the game, got %d entries", readyCount)
+	}
+	if orderLen != 2 {
+		t.Errorf("expected both ready players in the draw order, got %d", orderLen)
+	}
+
+	// A stray player_ready mid-game is rejected and leaves the map untouched
+	HandlePlayerReady(alice, true)
+	if _, ok := aliceConn.waitFor("error", time.Second); !ok {
+		t.Error("expected a mid-game player_ready to be rejected")
+	}
+	room.Mu.RLock()
+	readyCount = len(room.PlayersReady)
+	room.Mu.RUnlock()
+	if readyCount != 0 {
+		t.Errorf("expected a mid-game player_ready to be ignored, got %d entries", readyCount)
+	}
+}
+
+func withLobbyIdle(t *testing.T, timeout time.Duration, action string) {
+	t.Helper()
+	prevTimeout, prevAction := LobbyIdleTimeout, LobbyIdleAction
+	LobbyIdleTimeout, LobbyIdleAction = timeout, action
+	t.Cleanup(func() { LobbyIdleTimeout, LobbyIdleAction = prevTimeout, prevAction })
+}
+
+func TestIdlePlayerExcludedAndGameStarts(t *testing.T) {
+	withLobbyIdle(t, 20*time.Millisecond, LobbyIdleExclude)
+
+	room := newTestRoom(t, "idle-exclude")
+	_, aliceConn := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	afk, _ := addConnectedPlayer(room, "afk")
+	room.PlayerOrder = nil
+	for _, id := range []string{"alice", "bob"} {
+		room.Players[id].IsReady = true
+		room.PlayersReady[id] = true
+	}
+	afk.UnreadySince = time.Now()
+
+	if room.AreAllPlayersReady() {
+		t.Fatal("expected the unready player to block the start initially")
+	}
+
+	scheduleLobbyIdleCheck(afk)
+
+	if _, ok := aliceConn.waitFor("player_idle", time.Second); !ok {
+		t.Fatal("expected a player_idle broadcast")
+	}
+	if _, ok := aliceConn.waitFor("game_started", time.Second); !ok {
+		t.Fatal("expected the game to start once the idle player was excluded")
+	}
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if !afk.IsIdle {
+		t.Error("expected idle player to be flagged")
+	}
+	for _, id := range room.PlayerOrder {
+		if id == afk.Id {
+			t.Error("expected idle player to be left out of the draw order")
+		}
+	}
+}
+
+func TestIdlePlayerKicked(t *testing.T) {
+	withLobbyIdle(t, 20*time.Millisecond, LobbyIdleKick)
+
+	room := newTestRoom(t, "idle-kick")
+	_, aliceConn := addConnectedPlayer(room, "alice")
+	afk, afkConn := addConnectedPlayer(room, "afk")
+	afk.UnreadySince = time.Now()
+
+	scheduleLobbyIdleCheck(afk)
+
+	if _, ok := aliceConn.waitFor("player_idle", time.Second); !ok {
+		t.Fatal("expected a player_idle broadcast")
+	}
+	afkConn.mu.Lock()
+	closed := afkConn.closed
+	afkConn.mu.Unlock()
+	if !closed {
+		t.Error("expected idle player's connection to be closed")
+	}
+}
+
+func TestIdleCheckSkippedForClosedRoom(t *testing.T) {
+	withLobbyIdle(t, 20*time.Millisecond, LobbyIdleExclude)
+
+	room := newTestRoom(t, "idle-closed")
+	_, aliceConn := addConnectedPlayer(room, "alice")
+	afk, _ := addConnectedPlayer(room, "afk")
+	afk.UnreadySince = time.Now()
+
+	scheduleLobbyIdleCheck(afk)
+	room.Cancel()
+
+	if _, ok := aliceConn.waitFor("player_idle", 100*time.Millisecond); ok {
+		t.Error("expected no idle check once the room was closed")
+	}
+}
+
+func TestReadyPlayerNotFlaggedIdle(t *testing.T) {
+	withLobbyIdle(t, 10*time.Millisecond, LobbyIdleExclude)
+
+	room := newTestRoom(t, "idle-ready")
+	player, _ := addConnectedPlayer(room, "alice")
+	player.UnreadySince = time.Now()
+	player.IsReady = true
+
+	checkLobbyIdle(player)
+	time.Sleep(20 * time.Millisecond)
+	checkLobbyIdle(player)
+
+	if player.IsIdle {
+		t.Error("expected a ready player never to be flagged idle")
+	}
+}
+
+func TestLobbyResetScores(t *testing.T) {
+	tests := []struct {
+		name       string
+		reset      func(*internal.Room)
+		wantScore  int
+		scoresKept bool
+	}{
+		{name: "new game wipes scores", reset: ResetRoomToLobby, wantScore: 0},
+		{name: "pause keeps scores", reset: PauseRoomToLobby, wantScore: 150, scoresKept: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			withLobbyIdle(t, 0, LobbyIdleExclude)
+			room := newTestRoom(t, "lobby-reset-scores")
+			alice, conn := addConnectedPlayer(room, "alice")
+			bob, _ := addConnectedPlayer(room, "bob")
+			room.HasGameStarted = true
+			makeDrawer(room, bob)
+			alice.Score = 150
+			room.RoundStats = append(room.RoundStats, internal.RoundStats{RoundNumber: 1, Word: "apple"})
+
+			tt.reset(room)
+
+			msg, ok := conn.waitFor("lobby_reset", time.Second)
+			if !ok {
+				t.Fatal("expected lobby_reset")
+			}
+			var data struct {
+				ScoresKept bool                       `json:"scores_kept"`
+				Players    map[string]internal.Player `json:"players"`
+			}
+			if err := json.Unmarshal(msg.Data, &data); err != nil {
+				t.Fatalf("bad lobby_reset payload: %v", err)
+			}
+			if data.ScoresKept != tt.scoresKept || data.Players["alice"].Score != tt.wantScore {
+				t.Errorf("expected scores_kept=%v and alice at %d, got %v and %d",
+					tt.scoresKept, tt.wantScore, data.ScoresKept, data.Players["alice"].Score)
+			}
+
+			room.Mu.RLock()
+			defer room.Mu.RUnlock()
+			if alice.Score != tt.wantScore {
+				t.Errorf("expected alice to have %d points, got %d", tt.wantScore, alice.Score)
+			}
+			if room.Phase != internal.PhaseLobby || room.HasGameStarted || len(room.RoundStats) != 0 {
+				t.Errorf("expected a fresh lobby either way, got phase=%s started=%v stats=%d",
+					room.Phase, room.HasGameStarted, len(room.RoundStats))
+			}
+		})
+	}
+}
+
+func TestGameAfterPauseResumesScores(t *testing.T) {
+	withLobbyIdle(t, 0, LobbyIdleExclude)
+	room := newTestRoom(t, "lobby-resume-scores")
+	alice, _ := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	alice.Score = 90
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	PauseRoomToLobby(room)
+	room.Mu.Lock()
+	readyUp(room)
+	room.Mu.Unlock()
+	if err := StartGame(room); err != nil {
+		t.Fatalf("expected the game to start again: %v", err)
+	}
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if alice.Score != 90 {
+		t.Errorf("expected the resumed game to keep alice's 90 points, got %d", alice.Score)
+	}
+}
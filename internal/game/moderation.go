@@ -0,0 +1,149 @@
+package game
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// =============================================================================
+// MODERATION
+// =============================================================================
+
+// HandleMutePlayer lets the room host mute or unmute another player. Muted
+// players' wrong guesses are not shown to others; correct guesses still score.
+func HandleMutePlayer(sender *internal.Player, targetId string, muted bool) {
+	room := sender.Room
+	if room == nil {
+		log.Printf("[HandleMutePlayer] Player %s has no room reference", sender.Username)
+		return
+	}
+
+	room.Mu.Lock()
+	if room.HostId != sender.Id {
+		room.Mu.Unlock()
+		log.Printf("[HandleMutePlayer] room=%s: non-host %s tried to change mute state", room.Id, sender.Id)
+		SendErrorToPlayer(sender, "not_host", "Only the host can mute players")
+		return
+	}
+	target, ok := room.Players[targetId]
+	if !ok || target.Id == sender.Id {
+		room.Mu.Unlock()
+		log.Printf("[HandleMutePlayer] room=%s: invalid mute target %q from %s", room.Id, targetId, sender.Id)
+		SendErrorToPlayer(sender, "invalid_target", "No such player to mute")
+		return
+	}
+	if target.IsMuted == muted {
+		room.Mu.Unlock()
+		return
+	}
+	target.IsMuted = muted
+
+	action := "unmuted"
+	if muted {
+		action = "muted"
+	}
+	muteMessage := internal.Message[any]{
+		Type: "player_muted",
+		Data: map[string]any{
+			"room_id":   room.Id,
+			"player_id": target.Id,
+			"username":  target.Username,
+			"is_muted":  muted,
+			"by":        sender.Id,
+			"message":   fmt.Sprintf("%s was %s by the host", target.Username, action),
+			"timestamp": time.Now().UnixMilli(),
+		},
+	}
+	roomID := room.Id
+	room.Mu.Unlock()
+
+	log.Printf("[HandleMutePlayer] room=%s: %s %s player %s", roomID, sender.Id, action, target.Id)
+	queueBroadcast(room, muteMessage, nil)
+}
+
+// HandleKickPlayer lets the room host remove another player from the room.
+// The kicked player is told why and cannot reclaim their seat by reconnecting;
+// everyone else gets player_kicked. Kicking the drawer ends their turn like a
+// normal disconnect.
+func HandleKickPlayer(sender *internal.Player, targetId string) {
+	room := sender.Room
+	if room == nil {
+		log.Printf("[HandleKickPlayer] Player %s has no room reference", sender.Username)
+		return
+	}
+
+	room.Mu.Lock()
+	if room.HostId != sender.Id {
+		room.Mu.Unlock()
+		log.Printf("[HandleKickPlayer] room=%s: non-host %s tried to kick %q", room.Id, sender.Id, targetId)
+		SendErrorToPlayer(sender, "not_host", "Only the host can kick players")
+		return
+	}
+	target, ok := room.Players[targetId]
+	if !ok || target.Id == sender.Id {
+		room.Mu.Unlock()
+		log.Printf("[HandleKickPlayer] room=%s: invalid kick target %q from %s", room.Id, targetId, sender.Id)
+		SendErrorToPlayer(sender, "invalid_target", "No such player to kick")
+		return
+	}
+	// No held seat for a kicked player
+	target.SessionToken = ""
+	kickedMessage := internal.Message[any]{
+		Type: "kicked",
+		Data: map[string]any{
+			"room_id": room.Id,
+			"message": "You were removed from the room by the host",
+		},
+	}
+	playerKickedMessage := internal.Message[any]{
+		Type: "player_kicked",
+		Data: map[string]any{
+			"room_id":   room.Id,
+			"player_id": target.Id,
+			"username":  target.Username,
+			"by":        sender.Id,
+			"message":   fmt.Sprintf("%s was kicked by %s", target.Username, sender.Username),
+			"timestamp": time.Now().UnixMilli(),
+		},
+	}
+	roomID := room.Id
+	room.Mu.Unlock()
+
+	log.Printf("[HandleKickPlayer] room=%s: %s kicked player %s (%s)", roomID, sender.Id, target.Id, target.Username)
+	if err := target.SafeWriteJSON(kickedMessage); err != nil {
+		log.Printf("[HandleKickPlayer] room=%s: failed to notify kicked player %s: %v", roomID, target.Id, err)
+	}
+	removePlayer(target)
+	if target.Conn != nil {
+		target.Conn.Close()
+	}
+	SafeBroadcastToRoom(room, playerKickedMessage)
+}
+
+// nextHost picks who inherits the host role: the remaining player who joined
+// earliest, preferring connected players. Caller must hold room.Mu.
+func nextHost(room *internal.Room) *internal.Player {
+	var next *internal.Player
+	for _, p := range room.Players {
+		if next == nil || (p.IsConnected && !next.IsConnected) ||
+			(p.IsConnected == next.IsConnected && p.JoinedAt.Before(next.JoinedAt)) {
+			next = p
+		}
+	}
+	return next
+}
+
+// hostChangedMessage announces room's current host. Caller must hold room.Mu.
+func hostChangedMessage(room *internal.Room, host *internal.Player) internal.Message[any] {
+	return internal.Message[any]{
+		Type: "host_changed",
+		Data: map[string]any{
+			"room_id":  room.Id,
+			"host_id":  host.Id,
+			"username": host.Username,
+		},
+	}
+}
@@ -0,0 +1,199 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func TestMutedPlayerGuessesHiddenButStillScore(t *testing.T) {
+	room := newTestRoom(t, "mute")
+	host, _ := addConnectedPlayer(room, "host")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	noisy, noisyConn := addConnectedPlayer(room, "noisy")
+	room.HostId = host.Id
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+
+	HandleMutePlayer(host, noisy.Id, true)
+	if !noisy.IsMuted {
+		t.Fatal("expected host to be able to mute a player")
+	}
+
+	hostConn := host.Conn.(*fakeConn)
+	if _, ok := hostConn.waitFor("player_muted", time.Second); !ok {
+		t.Fatal("expected player_muted broadcast")
+	}
+
+	HandleGuessEnhanced(noisy, "banana")
+	if _, ok := noisyConn.waitFor("guess_message", time.Second); !ok {
+		t.Fatal("expected muted player to still see their own guess")
+	}
+	time.Sleep(50 * time.Millisecond)
+	if got := len(hostConn.messagesOfType("guess_message")); got != 0 {
+		t.Errorf("expected muted player's guess to be hidden from others, got %d", got)
+	}
+
+	HandleGuessEnhanced(noisy, "apple")
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if !noisy.HasGuessed || noisy.Score == 0 {
+		t.Errorf("expected muted player's correct guess to score, HasGuessed=%v Score=%d",
+			noisy.HasGuessed, noisy.Score)
+	}
+}
+
+func TestNonHostCannotMute(t *testing.T) {
+	room := newTestRoom(t, "mute-non-host")
+	host, _ := addConnectedPlayer(room, "host")
+	rogue, rogueConn := addConnectedPlayer(room, "rogue")
+	room.HostId = host.Id
+
+	HandleMutePlayer(rogue, host.Id, true)
+
+	if host.IsMuted {
+		t.Error("expected non-host mute to be rejected")
+	}
+	if _, ok := rogueConn.waitFor("error", time.Second); !ok {
+		t.Error("expected an error reply to the non-host")
+	}
+}
+
+func TestHostAssignedOnJoinAndTransferredOnLeave(t *testing.T) {
+	alice, _ := joinTestRoom(t, "host-transfer", "alice")
+	time.Sleep(time.Millisecond)
+	bob, bobConn := joinTestRoom(t, "host-transfer", "bob")
+	time.Sleep(time.Millisecond)
+	_, carolConn := joinTestRoom(t, "host-transfer", "carol")
+	room := alice.Room
+
+	room.Mu.RLock()
+	host := room.HostId
+	room.Mu.RUnlock()
+	if host != alice.Id {
+		t.Fatalf("expected the first player to join to be host, got %q", host)
+	}
+
+	removePlayer(alice)
+
+	room.Mu.RLock()
+	host = room.HostId
+	room.Mu.RUnlock()
+	if host != bob.Id {
+		t.Fatalf("expected the host role to pass to the next-joined player, got %q", host)
+	}
+	for _, conn := range []*fakeConn{bobConn, carolConn} {
+		msg, ok := conn.waitFor("host_changed", time.Second)
+		if !ok {
+			t.Fatal("expected host_changed to be broadcast")
+		}
+		var data map[string]string
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatalf("bad host_changed payload: %v", err)
+		}
+		if data["host_id"] != bob.Id {
+			t.Errorf("expected host_changed to name bob, got %v", data)
+		}
+	}
+}
+
+func TestOnlyHostCanStartGame(t *testing.T) {
+	room := newTestRoom(t, "host-start")
+	host, _ := addConnectedPlayer(room, "host")
+	guest, guestConn := addConnectedPlayer(room, "guest")
+	room.HostId = host.Id
+	readyUp(room)
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	HandleStartGame(guest)
+	msg, ok := guestConn.waitFor("error", time.Second)
+	if !ok {
+		t.Fatal("expected the non-host to get an error")
+	}
+	var errData internal.ErrorData
+	if err := json.Unmarshal(msg.Data, &errData); err != nil || errData.Code != "not_host" {
+		t.Errorf("expected a not_host error, got %+v (%v)", errData, err)
+	}
+	room.Mu.RLock()
+	started := room.HasGameStarted
+	room.Mu.RUnlock()
+	if started {
+		t.Fatal("expected a non-host start_game to be ignored")
+	}
+
+	HandleStartGame(host)
+	room.Mu.RLock()
+	started = room.HasGameStarted
+	room.Mu.RUnlock()
+	if !started {
+		t.Error("expected the host to be able to start the game")
+	}
+}
+
+func TestHostCanKickButOthersCannot(t *testing.T) {
+	host, _ := joinTestRoom(t, "host-kick", "host")
+	guest, guestConn := joinTestRoom(t, "host-kick", "guest")
+	rogue, rogueConn := joinTestRoom(t, "host-kick", "rogue")
+	room := host.Room
+
+	HandleKickPlayer(rogue, guest.Id)
+	if _, ok := rogueConn.waitFor("error", time.Second); !ok {
+		t.Error("expected a non-host kick to be rejected")
+	}
+	room.Mu.RLock()
+	_, present := room.Players[guest.Id]
+	room.Mu.RUnlock()
+	if !present {
+		t.Fatal("expected the target to stay after a non-host kick")
+	}
+
+	HandleKickPlayer(host, guest.Id)
+	if _, ok := guestConn.waitFor("kicked", time.Second); !ok {
+		t.Error("expected the kicked player to be told")
+	}
+	msg, ok := rogueConn.waitFor("player_kicked", time.Second)
+	if !ok {
+		t.Fatal("expected player_kicked to be broadcast")
+	}
+	var data map[string]any
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad player_kicked payload: %v", err)
+	}
+	if data["player_id"] != guest.Id || data["by"] != host.Id {
+		t.Errorf("expected player_kicked to name who was removed and by whom, got %v", data)
+	}
+	room.Mu.RLock()
+	_, present = room.Players[guest.Id]
+	room.Mu.RUnlock()
+	if present {
+		t.Error("expected the host to be able to kick a player")
+	}
+	// The closed connection's handler removing them again is harmless
+	removePlayer(guest)
+}
+
+func TestKickingDrawerAdvancesRound(t *testing.T) {
+	players, conns := joinMidGame(t, "kick-drawer", "host", "drawer", "guesser")
+	host, drawer := players[0], players[1]
+	room := host.Room
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+	room.Mu.Lock()
+	room.PlayerOrder = []string{drawer.Id, host.Id, "guesser"}
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	room.Mu.Unlock()
+
+	HandleKickPlayer(host, drawer.Id)
+
+	if _, ok := conns[2].waitFor("waiting_phase", time.Second); !ok {
+		t.Fatal("expected kicking the drawer to move on to the next turn")
+	}
+	room.Mu.RLock()
+	current, word := room.Current, room.Word
+	room.Mu.RUnlock()
+	if current == drawer || word == "apple" {
+		t.Errorf("expected a new turn without the kicked drawer, got current=%v word=%q", current, word)
+	}
+}
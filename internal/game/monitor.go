@@ -0,0 +1,112 @@
+package game
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// =============================================================================
+// STUCK ROOM MONITOR
+// =============================================================================
+
+var (
+	// StuckRoomCheckInterval is how often the monitor looks for stuck rooms
+	StuckRoomCheckInterval = 10 * time.Second
+	// StuckPhaseGrace is added to a phase's expected length before the room is
+	// considered stuck
+	StuckPhaseGrace = 15 * time.Second
+)
+
+// setPhase moves room to phase and records when it happened.
+// Caller must hold room.Mu.
+func setPhase(room *internal.Room, phase internal.GamePhase) {
+	room.Phase = phase
+	room.PhaseChangedAt = time.Now()
+}
+
+// phaseMaxDuration is the longest room's current phase should last, or 0 for
+// phases that may legitimately last forever (the lobby). Caller must hold
+// room.Mu.
+func phaseMaxDuration(room *internal.Room) time.Duration {
+	switch room.Phase {
+	case internal.PhaseWaiting:
+		// waiting timer, then the drawer's word selection (same phase)
+		return waitingPhaseDuration(room) + WordSelectionTimeout
+	case internal.PhaseDrawing:
+		return drawingPhaseDuration(room)
+	case internal.PhaseRevealing:
+		return internal.RevealingPhaseDuration
+	case internal.PhaseEnded:
+		return resultsDisplayDuration
+	default:
+		return 0
+	}
+}
+
+// StartStuckRoomMonitor periodically recovers rooms whose phase stopped
+// advancing (e.g. a timer goroutine died) until ctx is cancelled
+func StartStuckRoomMonitor(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(StuckRoomCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkStuckRooms()
+			}
+		}
+	}()
+}
+
+func checkStuckRooms() {
+	RoomsMu.RLock()
+	rooms := make([]*internal.Room, 0, len(Rooms))
+	for _, room := range Rooms {
+		rooms = append(rooms, room)
+	}
+	RoomsMu.RUnlock()
+
+	for _, room := range rooms {
+		recoverIfStuck(room)
+	}
+}
+
+// recoverIfStuck forces room out of a phase that has overrun its expected
+// length plus StuckPhaseGrace. Paused timers are waiting on purpose and are
+// left alone. Reports whether a recovery was attempted.
+func recoverIfStuck(room *internal.Room) bool {
+	room.Mu.Lock()
+	limit := phaseMaxDuration(room)
+	paused := room.Timer != nil && room.Timer.IsPaused
+	stalled := time.Since(room.PhaseChangedAt)
+	if limit == 0 || paused || room.PhaseChangedAt.IsZero() || stalled <= limit+StuckPhaseGrace {
+		room.Mu.Unlock()
+		return false
+	}
+	phase := room.Phase
+	roomID := room.Id
+	// Restart the clock so a failed recovery is retried a full window later
+	// rather than on every check
+	room.PhaseChangedAt = time.Now()
+	room.Mu.Unlock()
+
+	log.Printf("[recoverIfStuck] ALERT room=%s: phase %s has not advanced for %v (limit %v), recovering",
+		roomID, phase, stalled.Round(time.Second), limit+StuckPhaseGrace)
+	NotifyWebhook(room, "room_stuck", map[string]any{
+		"phase":      phase,
+		"stalled_ms": stalled.Milliseconds(),
+	})
+
+	CancelPhaseTimer(room)
+	if phase == internal.PhaseEnded {
+		ResetRoomToLobby(room)
+	} else {
+		NextRound(room)
+	}
+	return true
+}
@@ -0,0 +1,87 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func TestStalledDrawingPhaseIsRecovered(t *testing.T) {
+	room := newTestRoom(t, "stuck-drawing")
+	alice, conn := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	room.HasGameStarted = true
+	makeDrawer(room, alice)
+	room.Word = "apple"
+	room.PhaseChangedAt = time.Now().Add(-(internal.DrawingPhaseDuration + StuckPhaseGrace + time.Second))
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	if !recoverIfStuck(room) {
+		t.Fatal("expected the stalled drawing phase to be recovered")
+	}
+	if _, ok := conn.waitFor("waiting_phase", time.Second); !ok {
+		t.Fatal("expected recovery to move on to the next turn")
+	}
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Phase != internal.PhaseWaiting || room.Current == alice {
+		t.Errorf("expected the next drawer's waiting phase, got phase=%s drawer=%v", room.Phase, room.Current.Id)
+	}
+	if time.Since(room.PhaseChangedAt) > time.Second {
+		t.Errorf("expected the phase clock to restart, last change %v", room.PhaseChangedAt)
+	}
+}
+
+func TestHealthyRoomsAreLeftAlone(t *testing.T) {
+	room := newTestRoom(t, "not-stuck")
+	alice, _ := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	room.HasGameStarted = true
+	makeDrawer(room, alice)
+	room.PhaseChangedAt = time.Now().Add(-internal.DrawingPhaseDuration)
+
+	if recoverIfStuck(room) {
+		t.Error("expected a drawing phase within its time plus grace to be left alone")
+	}
+
+	room.Timer.IsPaused = true
+	room.PhaseChangedAt = time.Now().Add(-time.Hour)
+	if recoverIfStuck(room) {
+		t.Error("expected a paused phase to be left alone")
+	}
+
+	lobby := newTestRoom(t, "idle-lobby")
+	lobby.PhaseChangedAt = time.Now().Add(-time.Hour)
+	if recoverIfStuck(lobby) {
+		t.Error("expected the lobby never to count as stuck")
+	}
+}
+
+func TestPausedTimeDoesNotCountAsStuck(t *testing.T) {
+	room := newTestRoom(t, "stuck-paused")
+	alice, _ := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	room.HasGameStarted = true
+	makeDrawer(room, alice)
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	StartPhaseTimer(room, time.Minute, func() {})
+	if !PausePhaseTimer(room) {
+		t.Fatal("expected the drawing timer to pause")
+	}
+	// A long pause: the phase started well over its limit ago
+	room.Mu.Lock()
+	limit := phaseMaxDuration(room) + StuckPhaseGrace
+	room.PhaseChangedAt = time.Now().Add(-limit)
+	room.Timer.PausedAt = room.PhaseChangedAt.Add(time.Second)
+	room.Mu.Unlock()
+
+	if !ResumePhaseTimer(room) {
+		t.Fatal("expected the drawing timer to resume")
+	}
+	if recoverIfStuck(room) {
+		t.Error("expected time spent paused not to count towards the phase's limit")
+	}
+}
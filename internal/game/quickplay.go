@@ -0,0 +1,82 @@
+package game
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+	"github.com/scythe504/skribblr-backend/internal/utils"
+)
+
+// =============================================================================
+// QUICK PLAY ROOM POOL
+// =============================================================================
+
+// QuickPlayRoomPrefix starts the id of every room the quick play pool creates
+const QuickPlayRoomPrefix = "quick-"
+
+var (
+	// QuickPlayPoolSize is how many empty public lobby rooms are kept ready so
+	// GetJoinableRoom never has to wait for one to be created (0 disables)
+	QuickPlayPoolSize = 2
+	// QuickPlayPoolInterval is how often the pool is topped up
+	QuickPlayPoolInterval = 2 * time.Second
+)
+
+// StartQuickPlayPool fills the quick play pool and keeps it topped up until
+// ctx is cancelled. The pool size and interval are read once, here.
+func StartQuickPlayPool(ctx context.Context) {
+	size, interval := QuickPlayPoolSize, QuickPlayPoolInterval
+	if size <= 0 {
+		log.Println("[StartQuickPlayPool] Quick play pool disabled")
+		return
+	}
+
+	go func() {
+		topUpQuickPlayPool(size)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				topUpQuickPlayPool(size)
+			}
+		}
+	}()
+}
+
+// topUpQuickPlayPool creates default-config lobby rooms until at least size
+// empty ones exist. Pool rooms that fill up or empty out (and are cleaned up)
+// are replaced here. Returns how many rooms it created.
+func topUpQuickPlayPool(size int) int {
+	missing := size - countEmptyQuickPlayRooms()
+	for range missing {
+		room := getOrCreateRoom(QuickPlayRoomPrefix+utils.GenerateID(8), internal.RoomConfig{})
+		log.Printf("[topUpQuickPlayPool] room=%s added to the quick play pool", room.Id)
+	}
+	return max(missing, 0)
+}
+
+// countEmptyQuickPlayRooms counts pool rooms still waiting for their first player
+func countEmptyQuickPlayRooms() int {
+	RoomsMu.RLock()
+	defer RoomsMu.RUnlock()
+
+	empty := 0
+	for id, room := range Rooms {
+		if !strings.HasPrefix(id, QuickPlayRoomPrefix) {
+			continue
+		}
+		room.Mu.RLock()
+		if room.Phase == internal.PhaseLobby && len(room.Players) == 0 {
+			empty++
+		}
+		room.Mu.RUnlock()
+	}
+	return empty
+}
@@ -0,0 +1,128 @@
+package game
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// withEmptyRooms gives the test an empty room map, restoring the old one
+// (and closing any rooms the test left behind) afterwards
+func withEmptyRooms(t *testing.T) {
+	t.Helper()
+	RoomsMu.Lock()
+	prevRooms := Rooms
+	Rooms = make(map[string]*internal.Room)
+	RoomsMu.Unlock()
+
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		for _, room := range Rooms {
+			room.Cancel()
+		}
+		Rooms = prevRooms
+		RoomsMu.Unlock()
+	})
+}
+
+// quickPlayRooms lists the pool rooms currently registered
+func quickPlayRooms() []*internal.Room {
+	RoomsMu.RLock()
+	defer RoomsMu.RUnlock()
+	var rooms []*internal.Room
+	for id, room := range Rooms {
+		if strings.HasPrefix(id, QuickPlayRoomPrefix) {
+			rooms = append(rooms, room)
+		}
+	}
+	return rooms
+}
+
+func TestQuickPlayPoolFillsOnce(t *testing.T) {
+	withEmptyRooms(t)
+
+	if created := topUpQuickPlayPool(3); created != 3 {
+		t.Fatalf("expected an empty server to get 3 pool rooms, got %d", created)
+	}
+	if created := topUpQuickPlayPool(3); created != 0 {
+		t.Errorf("expected a full pool to be left alone, created %d", created)
+	}
+	for _, room := range quickPlayRooms() {
+		if room.Phase != internal.PhaseLobby || room.Config != (internal.RoomConfig{}) {
+			t.Errorf("expected pool room %s to be a default lobby, got phase=%s config=%+v",
+				room.Id, room.Phase, room.Config)
+		}
+	}
+	if id := GetJoinableRoom(); !strings.HasPrefix(id, QuickPlayRoomPrefix) {
+		t.Errorf("expected quick play to hand out a pool room, got %q", id)
+	}
+}
+
+func TestQuickPlayPoolReplenishedAfterRoomsFillOrEmpty(t *testing.T) {
+	withEmptyRooms(t)
+	topUpQuickPlayPool(2)
+	rooms := quickPlayRooms()
+
+	// A player takes one pool room: it's joinable first, and gets replaced
+	filled := rooms[0]
+	filled.Mu.Lock()
+	addTestPlayer(filled, "alice")
+	filled.Mu.Unlock()
+	if id := GetJoinableRoom(); id != filled.Id {
+		t.Errorf("expected quick play to fill %s before an empty room, got %q", filled.Id, id)
+	}
+	if created := topUpQuickPlayPool(2); created != 1 {
+		t.Errorf("expected the filled room to be replaced, created %d", created)
+	}
+
+	// The room empties out and is cleaned up: the pool stays at its size
+	filled.Mu.Lock()
+	delete(filled.Players, "alice")
+	filled.Mu.Unlock()
+	CleanupRoom(filled)
+	if created := topUpQuickPlayPool(2); created != 0 {
+		t.Errorf("expected the pool to still hold 2 empty rooms, created %d", created)
+	}
+	CleanupRoom(quickPlayRooms()[0])
+	if created := topUpQuickPlayPool(2); created != 1 {
+		t.Errorf("expected a cleaned up pool room to be replaced, created %d", created)
+	}
+	if got := countEmptyQuickPlayRooms(); got != 2 {
+		t.Errorf("expected 2 empty pool rooms, got %d", got)
+	}
+}
+
+func TestStartQuickPlayPoolKeepsPoolTopped(t *testing.T) {
+	withEmptyRooms(t)
+	prevSize, prevInterval := QuickPlayPoolSize, QuickPlayPoolInterval
+	QuickPlayPoolSize, QuickPlayPoolInterval = 2, 10*time.Millisecond
+	t.Cleanup(func() { QuickPlayPoolSize, QuickPlayPoolInterval = prevSize, prevInterval })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	StartQuickPlayPool(ctx)
+
+	waitForPool := func(want int) {
+		t.Helper()
+		deadline := time.Now().Add(time.Second)
+		for countEmptyQuickPlayRooms() != want {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %d empty pool rooms, got %d", want, countEmptyQuickPlayRooms())
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	waitForPool(2)
+
+	room := quickPlayRooms()[0]
+	room.Mu.Lock()
+	addTestPlayer(room, "alice")
+	room.Mu.Unlock()
+	waitForPool(2)
+	if got := len(quickPlayRooms()); got != 3 {
+		t.Errorf("expected the maintainer to add a room once one filled, have %d", got)
+	}
+}
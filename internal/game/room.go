@@ -4,8 +4,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
 	"slices"
 	"sync"
+	"time"
 
 	"github.com/scythe504/skribblr-backend/internal"
 	"github.com/scythe504/skribblr-backend/internal/utils"
@@ -15,7 +17,9 @@ import (
 // ROOM MANAGEMENT
 // =============================================================================
 
-// GetJoinableRoom returns ID of a room that can accept new players
+// GetJoinableRoom returns ID of a room that can accept new players. Rooms
+// that already have players come first, so quick play fills them before
+// handing out an empty room from the quick play pool.
 func GetJoinableRoom() string {
 	// TODO:
 	// 1. Lock rooms for reading
@@ -23,18 +27,21 @@ func GetJoinableRoom() string {
 	defer RoomsMu.RUnlock()
 
 	// 2. Iterate through existing rooms
+	emptyRoom := ""
 	for _, room := range Rooms {
 		room.Mu.RLock()
 
-		// 3. Check player count < MaxPlayersPerRoom
-		if len(room.Players) >= MaxPlayersPerRoom {
+		// 3. Check player count < the room's max players
+		if len(room.Players) >= roomMaxPlayers(room) {
 			// MUST unlock before continue, or deadlock happens
 			room.Mu.RUnlock()
 			continue
 		}
 
 		// 4. Check room is in lobby phase
-		if room.Phase == internal.PhaseLobby {
+		if room.Phase == internal.PhaseLobby && len(room.Players) == 0 {
+			emptyRoom = room.Id
+		} else if room.Phase == internal.PhaseLobby {
 			roomID := room.Id
 			room.Mu.RUnlock()
 			log.Printf("[GetJoinableRoom] Found joinable room %s with %d players", roomID, len(room.Players))
@@ -46,13 +53,19 @@ func GetJoinableRoom() string {
 		room.Mu.RUnlock()
 	}
 
+	if emptyRoom != "" {
+		log.Printf("[GetJoinableRoom] Found empty joinable room %s", emptyRoom)
+		return emptyRoom
+	}
+
 	// No joinable room found
 	log.Println("[GetJoinableRoom] No joinable room found")
 	return ""
 }
 
-// getOrCreateRoom retrieves existing room or creates new one
-func getOrCreateRoom(roomId string) *internal.Room {
+// getOrCreateRoom retrieves existing room or creates new one with cfg (which
+// is ignored for an existing room)
+func getOrCreateRoom(roomId string, cfg internal.RoomConfig) *internal.Room {
 	// TODO:
 	// 1. Lock rooms map for writing
 	RoomsMu.Lock()
@@ -66,6 +79,7 @@ func getOrCreateRoom(roomId string) *internal.Room {
 	}
 
 	// 3. If not exists, create new room
+	maxRounds := cfg.RoundsFor(internal.MaxRounds)
 	ctx, cancel := context.WithCancel(context.Background())
 	newRoom := &internal.Room{
 		Id:              roomId,
@@ -73,13 +87,15 @@ func getOrCreateRoom(roomId string) *internal.Room {
 		PlayersReady:    make(map[string]bool),
 		CorrectGuessers: make([]internal.PlayerGuess, 0),
 		PlayerOrder:     make([]string, 0),
-		WordChoices:     make([]string, 0),
+		WordChoices:     make([]internal.Word, 0),
 		Timer:           &internal.GameTimer{IsActive: false},
 		Current:         nil,
 
-		RoundStats:  make([]internal.RoundStats, 0),
-		CanvasState: make([]internal.PixelMessage, 0),
-		Phase:       internal.PhaseLobby,
+		RoundStats:       make([]internal.RoundStats, 0),
+		CanvasState:      make([]internal.PixelMessage, 0),
+		CanvasBackground: internal.DefaultCanvasBackground,
+		Phase:            internal.PhaseLobby,
+		PhaseChangedAt:   time.Now(),
 
 		Context: ctx,
 		Cancel:  cancel,
@@ -87,39 +103,61 @@ func getOrCreateRoom(roomId string) *internal.Room {
 		CurrentIndex:   0,
 		Word:           "",
 		RoundNumber:    1,
-		MaxRounds:      3,
+		MaxRounds:      maxRounds,
+		Config:         cfg,
 		HasGameStarted: false,
 
+		WebhookURL:     os.Getenv("ROOM_WEBHOOK_URL"),
+		IgnoreArticles: IgnoreGuessArticles,
+
 		Mu: sync.RWMutex{},
 	}
 
 	Rooms[roomId] = newRoom
 
-	log.Printf("[getOrCreateRoom] Created new room %s with default settings (maxRounds=%d, phase=%s)",
-		roomId, newRoom.MaxRounds, newRoom.Phase)
+	log.Printf("[getOrCreateRoom] Created new room %s (maxRounds=%d, config=%+v, phase=%s)",
+		roomId, newRoom.MaxRounds, cfg, newRoom.Phase)
 
 	// 4. Return room pointer
 	return newRoom
 }
 
-// AddPlayer joins a player to a room and sends initial messages
-func AddPlayer(roomId string, player *internal.Player) error {
+// AddPlayer joins a player to a room and sends initial messages. cfg only
+// applies if this creates the room.
+func AddPlayer(roomId string, player *internal.Player, cfg internal.RoomConfig) error {
 	// TODO:
 	// 1. Get or create room
-	room := getOrCreateRoom(roomId)
+	room := getOrCreateRoom(roomId, cfg)
 
 	// 2. Lock room for modifications
 	room.Mu.Lock()
 
+	// Enforce max players rule before the player is seated, telling them why
+	if len(room.Players) >= roomMaxPlayers(room) {
+		room.Mu.Unlock()
+		log.Printf("[AddPlayer] Room %s is full, rejecting player %s (%s)",
+			room.Id, player.Id, player.Username)
+		SendErrorToPlayer(player, "room_full", "This room is full, please join another room")
+		return fmt.Errorf("max players reached for this room, please join another room")
+	}
+
 	// 3. Set player.Room reference
 	player.Room = room
 
 	// 4. Add player to room.Players map
 	room.Players[player.Id] = player
+	if _, hostPresent := room.Players[room.HostId]; !hostPresent {
+		room.HostId = player.Id
+	}
 
 	// 5. Set player initial state
 	player.IsConnected = true
 	player.IsReady = false
+	player.IsIdle = false
+	player.JoinedAt = time.Now()
+	player.UnreadySince = player.JoinedAt
+	inLobby := room.Phase == internal.PhaseLobby
+	inResults := room.Phase == internal.PhaseEnded
 
 	// 6. Prepare welcome message
 	welcomeMsg := internal.Message[any]{
@@ -141,27 +179,7 @@ func AddPlayer(roomId string, player *internal.Player) error {
 
 	// 8. Send current game state to new player
 	room.Mu.RLock()
-	players := make([]*internal.Player, 0, len(room.Players))
-	for _, p := range room.Players {
-		players = append(players, p.ToPublicPlayer())
-	}
-
-	missingStateData := internal.Message[any]{
-		Type: "welcome_msg",
-		Data: map[string]any{
-			"game_state": internal.GameStateData{
-				Phase:           room.Phase,
-				RoundNumber:     room.RoundNumber,
-				MaxRounds:       room.MaxRounds,
-				CurrentDrawer:   room.Current,
-				TimeRemaining:   int64(room.Timer.TimeRemaining),
-				Word:            utils.GetMaskedWord(room.Word),
-				CorrectGuessers: room.CorrectGuessers,
-				Players:         players,
-			},
-			"canvas_state": room.CanvasState,
-		},
-	}
+	missingStateData := welcomeMessage(room, player)
 	room.Mu.RUnlock()
 
 	// Write directly to the joining player (not broadcasted)
@@ -171,21 +189,89 @@ func AddPlayer(roomId string, player *internal.Player) error {
 		return err
 	}
 
-	// 9. Enforce max players rule (do AFTER sending state, so player sees reason)
-	room.Mu.RLock()
-	if len(room.Players) > MaxPlayersPerRoom {
-		room.Mu.RUnlock()
-		log.Printf("[AddPlayer] Room %s is full, rejecting player %s (%s)",
-			room.Id, player.Id, player.Username)
-		return fmt.Errorf("max players reached for this room, please join another room")
+	// 9. Watch for players who never ready up
+	if inLobby {
+		scheduleLobbyIdleCheck(player)
+	}
+
+	// 10. Joined during the results window: show the results and tell them the
+	// lobby opens soon. They are reset into the lobby along with everyone else.
+	if inResults {
+		sendResultsPending(room, player)
 	}
-	room.Mu.RUnlock()
 
 	log.Printf("[AddPlayer] Successfully initialized player %s (%s) in room %s",
 		player.Id, player.Username, room.Id)
 	return nil
 }
 
+// welcomeMessage builds the private welcome_msg with the room's current state
+// and player's session token. Mid-drawing the joiner hasn't guessed, so they
+// get the redacted player list. Caller must hold room.Mu.
+func welcomeMessage(room *internal.Room, player *internal.Player) internal.Message[any] {
+	roundActive := room.Phase == internal.PhaseDrawing
+	players := make([]internal.PlayerSnapshot, 0, len(room.Players))
+	for _, p := range room.Players {
+		snapshot := internal.CreatePlayerSnapshot(p)
+		if roundActive {
+			snapshot = snapshot.Redacted()
+		}
+		players = append(players, snapshot)
+	}
+	correctGuessers := room.CorrectGuessers
+	if roundActive {
+		correctGuessers = []internal.PlayerGuess{}
+	}
+	var currentDrawer *internal.Player
+	if room.Current != nil {
+		currentDrawer = room.Current.ToPublicPlayer()
+	}
+
+	return internal.Message[any]{
+		Type: "welcome_msg",
+		Data: map[string]any{
+			"game_state": internal.GameStateData{
+				Phase:            room.Phase,
+				RoundNumber:      room.RoundNumber,
+				MaxRounds:        room.MaxRounds,
+				CurrentDrawer:    currentDrawer,
+				TimeRemaining:    room.RemainingTime(),
+				Word:             utils.GetMaskedWord(room.Word),
+				CorrectGuessers:  correctGuessers,
+				Players:          players,
+				CanvasBackground: room.CanvasBackground,
+				GuessProgress:    guessProgress(room),
+			},
+			"canvas_state":  room.CanvasState,
+			"host_id":       room.HostId,
+			"player_id":     player.Id,
+			"session_token": player.SessionToken,
+		},
+	}
+}
+
+// sendResultsPending shows a player who joined after the game ended the final
+// results and how long until the lobby reopens
+func sendResultsPending(room *internal.Room, player *internal.Player) {
+	room.Mu.RLock()
+	remaining := room.RemainingTime()
+	resultsMessage := internal.Message[any]{
+		Type: "results_pending",
+		Data: map[string]any{
+			"message":           "Game ended, lobby starting soon",
+			"room_id":           room.Id,
+			"results":           room.LastResults,
+			"time_remaining_ms": remaining,
+		},
+	}
+	room.Mu.RUnlock()
+
+	if err := player.SafeWriteJSON(resultsMessage); err != nil {
+		log.Printf("[AddPlayer] Failed to send results to late player %s (%s): %v",
+			player.Id, player.Username, err)
+	}
+}
+
 // removePlayer handles player disconnection and cleanup
 func removePlayer(player *internal.Player) {
 	// TODO:
@@ -200,10 +286,25 @@ func removePlayer(player *internal.Player) {
 	// 2. Lock room and modify shared state
 	room.Mu.Lock()
 
+	// Already removed (e.g. kicked, then their connection closed)
+	if room.Players[player.Id] != player {
+		room.Mu.Unlock()
+		log.Printf("[removePlayer] Player %s (%s) already left room %s", player.Id, player.Username, room.Id)
+		return
+	}
+
 	// Snapshot needed values before unlock
 	wasCurrentDrawer := (room.Current == player)
 	playerCountBefore := len(room.Players)
 
+	// Keep mid-game leavers around so their points still count in final results
+	if room.HasGameStarted {
+		if room.DepartedPlayers == nil {
+			room.DepartedPlayers = make(map[string]*internal.Player)
+		}
+		room.DepartedPlayers[player.Id] = player
+	}
+
 	// Remove from room data structures
 	delete(room.Players, player.Id)
 	delete(room.PlayersReady, player.Id)
@@ -214,26 +315,47 @@ func removePlayer(player *internal.Player) {
 	// Calculate new player count after removal
 	playerCountAfter := len(room.Players)
 
+	// Hand the host role on if the host left
+	var hostChanged *internal.Message[any]
+	if room.HostId == player.Id {
+		room.HostId = ""
+		if host := nextHost(room); host != nil {
+			room.HostId = host.Id
+			msg := hostChangedMessage(room, host)
+			hostChanged = &msg
+		}
+	}
+
+	// A guesser leaving may mean everyone still here has already guessed
+	remainingAllGuessed := !wasCurrentDrawer && room.Phase == internal.PhaseDrawing &&
+		len(room.CorrectGuessers) > 0 && room.HasEveryoneGuessed()
+
 	log.Printf("[removePlayer] Removing player %s (%s) from room %s. Players before=%d after=%d",
 		player.Id, player.Username, room.Id, playerCountBefore, playerCountAfter)
 
 	room.Mu.Unlock()
 
+	minPlayers := GetGameConfig().MinPlayersToStart
 	// 3. Handle drawer leaving mid-round
 	if wasCurrentDrawer && room.Phase == internal.PhaseDrawing {
 		log.Printf("[removePlayer] Player %s was the current drawer in room %s",
 			player.Username, room.Id)
 		CancelPhaseTimer(room)
 
-		if playerCountAfter >= MinPlayersToStart {
+		if playerCountAfter >= minPlayers {
 			NextRound(room) // already acquires locks internally
 		} else {
-			ResetRoomToLobby(room)
+			AbortGame(room, "not_enough_players")
 		}
-	} else if playerCountAfter < MinPlayersToStart && room.HasGameStarted {
-		log.Printf("[removePlayer] Too few players to continue in room %s, resetting to lobby",
+	} else if playerCountAfter < minPlayers && room.HasGameStarted {
+		log.Printf("[removePlayer] Too few players to continue in room %s, aborting game",
+			room.Id)
+		AbortGame(room, "not_enough_players")
+	} else if remainingAllGuessed {
+		log.Printf("[removePlayer] All remaining players in room %s have guessed, ending round early",
 			room.Id)
-		ResetRoomToLobby(room)
+		CancelPhaseTimer(room)
+		NextRound(room)
 	}
 
 	// 4. Cleanup room if empty
@@ -260,6 +382,9 @@ func removePlayer(player *internal.Player) {
 
 	// Safe: we are broadcasting with a snapshot, no lock required here
 	SafeBroadcastToRoom(room, leaveMessage)
+	if hostChanged != nil {
+		SafeBroadcastToRoom(room, *hostChanged)
+	}
 
 	// 6. Update game state for remaining players
 	BroadcastGameState(room)
@@ -277,7 +402,11 @@ func CleanupRoom(room *internal.Room) {
 		room.Cancel = nil
 	}
 
-	// 2. Close all player connections
+	// 2. Drop held seats and close all player connections
+	for _, entry := range room.Disconnected {
+		entry.Purge.Stop()
+	}
+	room.Disconnected = nil
 	for _, player := range room.Players {
 		if player.Conn != nil {
 			if err := player.Conn.Close(); err != nil {
@@ -314,3 +443,12 @@ func CleanupRoom(room *internal.Room) {
 
 	log.Printf("[CleanupRoom] Room %s cleanup completed", room.Id)
 }
+
+// roomMaxPlayers is room's player cap: its configured MaxPlayers, or the
+// server-wide MaxPlayersPerRoom. Caller must hold room.Mu.
+func roomMaxPlayers(room *internal.Room) int {
+	if room.Config.MaxPlayers > 0 {
+		return room.Config.MaxPlayers
+	}
+	return GetGameConfig().MaxPlayersPerRoom
+}
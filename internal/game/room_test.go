@@ -0,0 +1,346 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// joinTestRoom adds a fake-connected player to the global room roomId via AddPlayer
+func joinTestRoom(t *testing.T, roomId, playerId string) (*internal.Player, *fakeConn) {
+	t.Helper()
+
+	conn := newFakeConn()
+	player := &internal.Player{
+		Id:           playerId,
+		Username:     playerId,
+		Conn:         conn,
+		CanvasWidth:  internal.CanvasWidth,
+		CanvasHeight: internal.CanvasHeight,
+	}
+	if err := AddPlayer(roomId, player, internal.RoomConfig{}); err != nil {
+		t.Fatalf("AddPlayer(%s, %s) failed: %v", roomId, playerId, err)
+	}
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		delete(Rooms, roomId)
+		RoomsMu.Unlock()
+	})
+	return player, conn
+}
+
+func TestAddPlayerSendsWelcomeAndNotifiesOthers(t *testing.T) {
+	first, firstConn := joinTestRoom(t, "add-player-room", "alice")
+	_, secondConn := joinTestRoom(t, "add-player-room", "bob")
+
+	if first.Room == nil || first.Room.Id != "add-player-room" {
+		t.Fatalf("expected player to be attached to room, got %+v", first.Room)
+	}
+	if _, ok := secondConn.waitFor("welcome_msg", time.Second); !ok {
+		t.Error("expected joining player to receive welcome_msg")
+	}
+	if _, ok := firstConn.waitFor("player_joined", time.Second); !ok {
+		t.Error("expected existing player to be notified with player_joined")
+	}
+	if got := len(secondConn.messagesOfType("player_joined")); got != 0 {
+		t.Errorf("expected joining player not to receive their own player_joined, got %d", got)
+	}
+}
+
+func TestAddPlayerRejectsWhenRoomFull(t *testing.T) {
+	for i := 0; i < GetGameConfig().MaxPlayersPerRoom; i++ {
+		joinTestRoom(t, "full-room", string(rune('a'+i)))
+	}
+
+	room := getOrCreateRoom("full-room", internal.RoomConfig{})
+	room.Mu.RLock()
+	before := len(room.Players)
+	room.Mu.RUnlock()
+
+	conn := newFakeConn()
+	player := &internal.Player{Id: "overflow", Username: "overflow", Conn: conn}
+	if err := AddPlayer("full-room", player, internal.RoomConfig{}); err == nil {
+		t.Fatal("expected AddPlayer to reject a player beyond MaxPlayersPerRoom")
+	}
+
+	room.Mu.RLock()
+	_, seated := room.Players[player.Id]
+	after := len(room.Players)
+	room.Mu.RUnlock()
+	if seated || after != before {
+		t.Errorf("expected the rejected player not to be seated, seated=%t players %d -> %d", seated, before, after)
+	}
+	if len(conn.messagesOfType("error")) != 1 || len(conn.messagesOfType("welcome_msg")) != 0 {
+		t.Errorf("expected only a room_full error for the rejected player, got %+v", conn.messages())
+	}
+}
+
+// startTestRound puts room mid-game with drawer drawing and guesser already correct
+func startTestRound(room *internal.Room, drawer, guesser *internal.Player) {
+	room.HasGameStarted = true
+	makeDrawer(room, drawer)
+	room.Word = "apple"
+	guesser.HasGuessed = true
+	guesser.Score = 180
+	room.CorrectGuessers = append(room.CorrectGuessers, internal.PlayerGuess{
+		PlayerID: guesser.Id,
+		Username: guesser.Username,
+	})
+}
+
+func TestCorrectGuesserLeavingKeepsScoreInFinalResults(t *testing.T) {
+	room := newTestRoom(t, "departed-results")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, _ := addConnectedPlayer(room, "guesser")
+	addConnectedPlayer(room, "other")
+	addConnectedPlayer(room, "late")
+	startTestRound(room, drawer, guesser)
+	drawer.Score = 50
+
+	removePlayer(guesser)
+
+	results := CalculateFinalResults(room)
+	if len(results.Leaderboard) != 4 {
+		t.Fatalf("expected leaver to stay on the leaderboard, got %d entries", len(results.Leaderboard))
+	}
+	top := results.Leaderboard[0]
+	if top.PlayerID != guesser.Id || top.Score != 180 || !top.HasLeft || top.Position != 1 {
+		t.Errorf("expected departed guesser first with 180 points and has_left, got %+v", top)
+	}
+	for _, entry := range results.Leaderboard[1:] {
+		if entry.HasLeft {
+			t.Errorf("expected %s not to be flagged as departed", entry.PlayerID)
+		}
+	}
+	if results.TotalPlayers != 3 {
+		t.Errorf("expected TotalPlayers to count only remaining players, got %d", results.TotalPlayers)
+	}
+}
+
+func TestLastUnguessedPlayerLeavingEndsRound(t *testing.T) {
+	room := newTestRoom(t, "departed-round-end")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, _ := addConnectedPlayer(room, "guesser")
+	addConnectedPlayer(room, "third")
+	straggler, _ := addConnectedPlayer(room, "straggler")
+	startTestRound(room, drawer, guesser)
+	room.Players["third"].HasGuessed = true
+	// A fixed rotation, so the turn after the drawer's is the guesser's
+	room.PlayerOrder = []string{"drawer", "guesser", "third", "straggler"}
+	room.CurrentIndex = 0
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	removePlayer(straggler)
+
+	room.Mu.RLock()
+	current := room.Current
+	room.Mu.RUnlock()
+	if current != guesser {
+		t.Errorf("expected the next drawer to take over once every remaining guesser had guessed, got %v", current)
+	}
+}
+
+func TestDepartedPlayersClearedOnNewGame(t *testing.T) {
+	room := newTestRoom(t, "departed-reset")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, _ := addConnectedPlayer(room, "guesser")
+	addConnectedPlayer(room, "other")
+	addConnectedPlayer(room, "late")
+	startTestRound(room, drawer, guesser)
+
+	removePlayer(guesser)
+	ResetRoomToLobby(room)
+
+	results := CalculateFinalResults(room)
+	for _, entry := range results.Leaderboard {
+		if entry.HasLeft {
+			t.Errorf("expected departed players to be cleared on reset, found %s", entry.PlayerID)
+		}
+	}
+}
+
+func TestJoinDuringResultsWindowShowsResults(t *testing.T) {
+	alice, aliceConn := joinTestRoom(t, "ended-room", "alice")
+	joinTestRoom(t, "ended-room", "bob")
+	room := alice.Room
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	room.Mu.Lock()
+	room.HasGameStarted = true
+	alice.Score = 120
+	room.Mu.Unlock()
+	EndGame(room)
+	if _, ok := aliceConn.waitFor("game_ended", time.Second); !ok {
+		t.Fatal("expected game_ended before the late join")
+	}
+
+	carol, carolConn := joinTestRoom(t, "ended-room", "carol")
+
+	msg, ok := carolConn.waitFor("results_pending", time.Second)
+	if !ok {
+		t.Fatal("expected late joiner to get results_pending")
+	}
+	var data struct {
+		Results       internal.FinalResults `json:"results"`
+		TimeRemaining int64                 `json:"time_remaining_ms"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad results_pending payload: %v", err)
+	}
+	if len(data.Results.Leaderboard) != 2 || data.Results.Leaderboard[0].PlayerID != alice.Id {
+		t.Errorf("expected the finished game's leaderboard, got %+v", data.Results.Leaderboard)
+	}
+	if data.TimeRemaining <= 0 {
+		t.Errorf("expected time until lobby reset, got %dms", data.TimeRemaining)
+	}
+
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if _, ok := room.Players[carol.Id]; !ok {
+		t.Error("expected late joiner to stay in the room for the lobby reset")
+	}
+}
+
+func TestConfiguredRoomPlaysItsRoundCount(t *testing.T) {
+	conn := newFakeConn()
+	player := &internal.Player{Id: "solo", Username: "solo", Conn: conn}
+	if err := AddPlayer("five-rounds", player, internal.RoomConfig{MaxRounds: 5}); err != nil {
+		t.Fatalf("AddPlayer failed: %v", err)
+	}
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		delete(Rooms, "five-rounds")
+		RoomsMu.Unlock()
+	})
+	room := player.Room
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	room.Mu.Lock()
+	room.HasGameStarted = true
+	room.PlayerOrder = []string{player.Id}
+	room.Current = player
+	room.Phase = internal.PhaseDrawing
+	room.Mu.Unlock()
+
+	turns := 1
+	for ; turns <= 10; turns++ {
+		NextRound(room)
+		deadline := time.Now().Add(time.Second)
+		for len(conn.messagesOfType("waiting_phase")) < turns &&
+			len(conn.messagesOfType("game_ended")) == 0 && time.Now().Before(deadline) {
+			time.Sleep(2 * time.Millisecond)
+		}
+		if len(conn.messagesOfType("game_ended")) > 0 {
+			break
+		}
+	}
+	if turns != 5 {
+		t.Errorf("expected the game to end after 5 rounds, ended after %d", turns)
+	}
+}
+
+func TestTurnsPerPlayerGivesEachPlayerThatManyTurns(t *testing.T) {
+	cfg := internal.RoomConfig{TurnsPerPlayer: 2}
+	var players []*internal.Player
+	var conn *fakeConn
+	for _, id := range []string{"alice", "bob", "carol"} {
+		c := newFakeConn()
+		p := &internal.Player{Id: id, Username: id, Conn: c, IsConnected: true}
+		if err := AddPlayer("two-turns-each", p, cfg); err != nil {
+			t.Fatalf("AddPlayer failed: %v", err)
+		}
+		players = append(players, p)
+		conn = c
+	}
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		delete(Rooms, "two-turns-each")
+		RoomsMu.Unlock()
+	})
+	room := players[0].Room
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	room.Mu.Lock()
+	room.HasGameStarted = true
+	room.PlayerOrder = []string{"alice", "bob", "carol"}
+	room.Current = players[0]
+	room.Phase = internal.PhaseDrawing
+	room.Mu.Unlock()
+
+	turns := 1
+	for ; turns <= 12; turns++ {
+		NextRound(room)
+		deadline := time.Now().Add(time.Second)
+		for len(conn.messagesOfType("waiting_phase")) < turns &&
+			len(conn.messagesOfType("game_ended")) == 0 && time.Now().Before(deadline) {
+			time.Sleep(2 * time.Millisecond)
+		}
+		if len(conn.messagesOfType("game_ended")) > 0 {
+			break
+		}
+	}
+	if turns != 6 {
+		t.Errorf("expected 2 turns each for 3 players (6 turns), game ended after %d", turns)
+	}
+}
+
+func TestAddPlayerHonorsRoomMaxPlayers(t *testing.T) {
+	cfg := internal.RoomConfig{MaxPlayers: 2}
+	for _, id := range []string{"a", "b"} {
+		p := &internal.Player{Id: id, Username: id, Conn: newFakeConn()}
+		if err := AddPlayer("two-seat-room", p, cfg); err != nil {
+			t.Fatalf("AddPlayer(%s) failed: %v", id, err)
+		}
+	}
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		delete(Rooms, "two-seat-room")
+		RoomsMu.Unlock()
+	})
+
+	player := &internal.Player{Id: "c", Username: "c", Conn: newFakeConn()}
+	if err := AddPlayer("two-seat-room", player, internal.RoomConfig{}); err == nil {
+		t.Fatal("expected the room's own player cap to apply")
+	}
+}
+
+func TestPlayerSnapshotsDontRaceWithScoring(t *testing.T) {
+	withLobbyIdle(t, 0, LobbyIdleExclude)
+	room := newTestRoom(t, "player-locking")
+	drawer, _ := addConnectedPlayer(room, "drawer")
+	guesser, _ := addConnectedPlayer(room, "guesser")
+	room.Current = drawer
+
+	// Game logic writes player fields under room.Mu ...
+	stop, done := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+			}
+			room.Mu.Lock()
+			guesser.Score += 10
+			guesser.HasGuessed = !guesser.HasGuessed
+			drawer.CanDraw = !drawer.CanDraw
+			room.Current = drawer
+			room.Mu.Unlock()
+		}
+	}()
+
+	// ... while broadcasts serialize player lists after releasing it
+	for range 20 {
+		ResetRoomToLobby(room)
+		broadcastGameStateNow(room)
+		room.Mu.RLock()
+		welcome := welcomeMessage(room, guesser)
+		room.Mu.RUnlock()
+		_ = guesser.SafeWriteJSON(welcome)
+	}
+	close(stop)
+	<-done
+}
@@ -1,12 +1,36 @@
 package game
 
 import (
+	"log"
 	"math"
 	"slices"
+	"time"
 
 	"github.com/scythe504/skribblr-backend/internal"
+	"github.com/scythe504/skribblr-backend/internal/utils"
 )
 
+// HandleRequestStats privately sends a player their running stats for the
+// current game
+func HandleRequestStats(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleRequestStats] Player %s has no room reference", player.Username)
+		return
+	}
+
+	room.Mu.RLock()
+	statsMessage := internal.Message[any]{
+		Type: "player_stats",
+		Data: utils.GetPlayerStats(player),
+	}
+	room.Mu.RUnlock()
+
+	if err := player.SafeWriteJSON(statsMessage); err != nil {
+		log.Printf("[HandleRequestStats] room=%s: failed to send stats to %s: %v", room.Id, player.Id, err)
+	}
+}
+
 // CalculateFinalResults compiles leaderboard and awards from a finished game
 func CalculateFinalResults(room *internal.Room) internal.FinalResults {
 	room.Mu.Lock()
@@ -25,6 +49,15 @@ func CalculateFinalResults(room *internal.Room) internal.FinalResults {
 			Score:    player.Score,
 		})
 	}
+	// - Players who left mid-game keep the points they earned, flagged as gone
+	for _, player := range room.DepartedPlayers {
+		playerData = append(playerData, internal.GameResultData{
+			PlayerID: player.Id,
+			Username: player.Username,
+			Score:    player.Score,
+			HasLeft:  true,
+		})
+	}
 
 	// TODO: 2. Sort slice by Score descending
 	slices.SortFunc(playerData, func(a internal.GameResultData, b internal.GameResultData) int {
@@ -61,11 +94,69 @@ func CalculateFinalResults(room *internal.Room) internal.FinalResults {
 		results.FastestGuess = nil // no correct guesses recorded
 	}
 
+	// TODO: 5. Compute most accurate player
+	// - Best CorrectGuesses/TotalGuesses among players with at least
+	//   MinGuessesForAccuracyAward guesses; ties go to more correct guesses,
+	//   then to the higher leaderboard position. nil if nobody qualifies.
+	guessStats := make(map[string]*internal.Player, len(room.Players)+len(room.DepartedPlayers))
+	for _, player := range room.Players {
+		guessStats[player.Id] = player
+	}
+	for _, player := range room.DepartedPlayers {
+		guessStats[player.Id] = player
+	}
+	var mostAccurate *internal.Player
+	for idx := range playerData {
+		player := guessStats[playerData[idx].PlayerID]
+		if player == nil || player.TotalGuesses < MinGuessesForAccuracyAward || player.CorrectGuesses == 0 {
+			continue
+		}
+		if mostAccurate != nil {
+			// Compare ratios by cross-multiplying to stay in integers
+			better := player.CorrectGuesses * mostAccurate.TotalGuesses
+			best := mostAccurate.CorrectGuesses * player.TotalGuesses
+			if better < best || (better == best && player.CorrectGuesses <= mostAccurate.CorrectGuesses) {
+				continue
+			}
+		}
+		mostAccurate = player
+		results.MostAccurate = &playerData[idx]
+	}
+
 	// TODO: 6. Fill metadata
 	// - results.RoundsPlayed = room.RoundNumber
 	results.RoundsPlayed = room.RoundNumber
 	// - results.TotalPlayers = len(room.Players)
 	results.TotalPlayers = len(room.Players)
+	// - results.GameDurationMs from StartedAt to now
+	if !room.StartedAt.IsZero() {
+		results.GameDurationMs = now().Sub(room.StartedAt).Milliseconds()
+	}
+	// - results.AvgRoundDurationMs over rounds with both timestamps recorded
+	var roundTotal time.Duration
+	timedRounds := 0
+	for _, stat := range room.RoundStats {
+		if stat.StartTime.IsZero() || stat.EndTime.Before(stat.StartTime) {
+			continue
+		}
+		roundTotal += stat.EndTime.Sub(stat.StartTime)
+		timedRounds++
+	}
+	if timedRounds > 0 {
+		results.AvgRoundDurationMs = (roundTotal / time.Duration(timedRounds)).Milliseconds()
+	}
+
+	// - results.WordCredits: who drew each completed word and who got it
+	results.WordCredits = make([]internal.WordCredit, 0, len(room.RoundStats))
+	for _, stat := range room.RoundStats {
+		results.WordCredits = append(results.WordCredits, internal.WordCredit{
+			RoundNumber:     stat.RoundNumber,
+			Word:            stat.Word,
+			DrawerID:        stat.DrawerId,
+			DrawerUsername:  stat.DrawerUsername,
+			CorrectGuessers: stat.CorrectGuessers,
+		})
+	}
 
 	// TODO: 7. Return results
 	return results
@@ -0,0 +1,207 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// SessionTokenLength is the length of the token a client presents (as the
+// session query param) to reclaim its seat after a dropped connection
+const SessionTokenLength = 24
+
+// disconnectPlayer handles a closed connection. A player dropped mid-game, or
+// a ready player dropped in the lobby with AutoReadyOnReconnect on, keeps
+// their seat for ReconnectGracePeriod, marked disconnected so rounds carry on
+// without them; otherwise, or once the window passes, they are removed via
+// removePlayer.
+func disconnectPlayer(player *internal.Player) {
+	room := player.Room
+	grace := ReconnectGracePeriod
+	if room == nil || grace <= 0 || player.SessionToken == "" {
+		removePlayer(player)
+		return
+	}
+
+	room.Mu.Lock()
+	holdLobbySeat := AutoReadyOnReconnect && room.Phase == internal.PhaseLobby && player.IsReady
+	if (!room.HasGameStarted && !holdLobbySeat) || room.Players[player.Id] != player {
+		room.Mu.Unlock()
+		removePlayer(player)
+		return
+	}
+
+	player.IsConnected = false
+	player.IsReconnecting = true
+	player.CanDraw = false
+	if room.Disconnected == nil {
+		room.Disconnected = make(map[string]*internal.DisconnectedPlayer)
+	}
+	entry := &internal.DisconnectedPlayer{Player: player, Since: time.Now()}
+	token := player.SessionToken
+	entry.Purge = time.AfterFunc(grace, func() { purgeDisconnected(room, token, entry) })
+	room.Disconnected[token] = entry
+
+	// A guesser dropping may mean everyone still here has already guessed
+	remainingAllGuessed := room.Phase == internal.PhaseDrawing && room.Current != player &&
+		len(room.CorrectGuessers) > 0 && room.HasEveryoneGuessed()
+	roomID := room.Id
+	// Others see the player as reconnecting, not gone, until the window closes
+	reconnectingMessage := internal.Message[any]{
+		Type: "player_reconnecting",
+		Data: map[string]any{
+			"player_id":           player.Id,
+			"username":            player.Username,
+			"reconnect_window_ms": grace.Milliseconds(),
+		},
+	}
+	room.Mu.Unlock()
+
+	log.Printf("[disconnectPlayer] room=%s: holding seat of player %s (%s) for %v",
+		roomID, player.Id, player.Username, grace)
+	SafeBroadcastToRoom(room, reconnectingMessage)
+
+	if remainingAllGuessed {
+		log.Printf("[disconnectPlayer] room=%s: all remaining players have guessed, ending round early", roomID)
+		CancelPhaseTimer(room)
+		NextRound(room)
+	}
+	BroadcastGameState(room)
+}
+
+// purgeDisconnected removes a dropped player whose grace period ran out,
+// unless they reconnected in the meantime
+func purgeDisconnected(room *internal.Room, token string, entry *internal.DisconnectedPlayer) {
+	room.Mu.Lock()
+	if room.Disconnected[token] != entry {
+		room.Mu.Unlock()
+		return
+	}
+	delete(room.Disconnected, token)
+	entry.Player.IsReconnecting = false
+	room.Mu.Unlock()
+
+	log.Printf("[purgeDisconnected] room=%s: player %s (%s) did not reconnect within %v",
+		room.Id, entry.Player.Id, entry.Player.Username, time.Since(entry.Since).Round(time.Second))
+	removePlayer(entry.Player)
+}
+
+// ReconnectPlayer gives the seat held for session back to the player arriving
+// on incoming's connection. The held player keeps their id, score and round
+// state and takes over incoming's connection and canvas size, plus its
+// username if one was given. A drawer returning mid-turn can draw again and
+// is resent the word (or the pending word choices). A player returning to the
+// lobby is ready again if AutoReadyOnReconnect is on, else unready. Reports
+// false if room roomId holds no seat for session.
+func ReconnectPlayer(roomId string, incoming *internal.Player, session string) (*internal.Player, bool) {
+	RoomsMu.RLock()
+	room := Rooms[roomId]
+	RoomsMu.RUnlock()
+	if room == nil {
+		return nil, false
+	}
+
+	room.Mu.Lock()
+	entry := room.Disconnected[session]
+	if entry == nil || room.Players[entry.Player.Id] != entry.Player {
+		room.Mu.Unlock()
+		log.Printf("[ReconnectPlayer] room=%s: no held seat for session, joining as new player", roomId)
+		return nil, false
+	}
+	entry.Purge.Stop()
+	delete(room.Disconnected, session)
+
+	player := entry.Player
+	player.Mu.Lock()
+	player.Conn = incoming.Conn
+	player.Mu.Unlock()
+	player.IsConnected = true
+	player.IsReconnecting = false
+	player.CanvasWidth = incoming.CanvasWidth
+	player.CanvasHeight = incoming.CanvasHeight
+	if incoming.Username != "" {
+		player.Username = incoming.Username
+	}
+
+	// A returning drawer picks their turn back up
+	var drawerData *internal.Message[any]
+	if room.Phase == internal.PhaseDrawing && room.Current == player {
+		player.CanDraw = true
+		drawerData = &internal.Message[any]{
+			Type: "drawing_phase",
+			Data: map[string]any{
+				"room_id":           room.Id,
+				"current_word":      room.Word,
+				"current_drawer":    map[string]string{"id": player.Id, "username": player.Username},
+				"phase":             internal.PhaseDrawing,
+				"time_remaining_ms": room.RemainingTime(),
+				"difficulty":        room.WordDifficulty,
+				"points":            room.WordDifficulty.BasePoints(),
+			},
+		}
+	}
+
+	// A returning lobby player is ready again only if AutoReadyOnReconnect allows
+	var lobbyUpdate *internal.Message[any]
+	ready, canStart := false, false
+	if room.Phase == internal.PhaseLobby {
+		player.IsReady = AutoReadyOnReconnect
+		player.IsIdle = false
+		if !player.IsReady {
+			player.UnreadySince = time.Now()
+		}
+		room.PlayersReady[player.Id] = player.IsReady
+		update := lobbyUpdateMessage(room, player)
+		lobbyUpdate = &update
+		ready = player.IsReady
+		canStart = ready && readyToStart(room)
+	}
+
+	welcome := welcomeMessage(room, player)
+	reconnectedMessage := internal.Message[any]{
+		Type: "player_reconnected",
+		Data: map[string]any{
+			"player_id": player.Id,
+			"username":  player.Username,
+			"score":     player.Score,
+		},
+	}
+	away := time.Since(entry.Since)
+	room.Mu.Unlock()
+
+	log.Printf("[ReconnectPlayer] room=%s: player %s (%s) reconnected after %v",
+		roomId, player.Id, player.Username, away.Round(time.Millisecond))
+
+	if err := player.SafeWriteJSON(welcome); err != nil {
+		log.Printf("[ReconnectPlayer] room=%s: failed to send state to player %s (%s): %v",
+			roomId, player.Id, player.Username, err)
+	}
+	if drawerData != nil {
+		if err := player.SafeWriteJSON(*drawerData); err != nil {
+			log.Printf("[ReconnectPlayer] room=%s: failed to resend word to drawer %s (%s): %v",
+				roomId, player.Id, player.Username, err)
+		}
+	} else {
+		ResendWordSelection(player)
+	}
+
+	SafeBroadcastToRoomExcept(room, reconnectedMessage, player)
+	if lobbyUpdate != nil {
+		SafeBroadcastToRoom(room, *lobbyUpdate)
+		if !ready {
+			scheduleLobbyIdleCheck(player)
+		}
+	}
+	BroadcastGameState(room)
+
+	if canStart {
+		log.Printf("[ReconnectPlayer] room=%s: all players ready after reconnect, starting game", roomId)
+		go func() {
+			if err := StartGame(room); err != nil {
+				log.Printf("[ReconnectPlayer] Failed to start game in room %s: %v", roomId, err)
+			}
+		}()
+	}
+	return player, true
+}
@@ -0,0 +1,378 @@
+package game
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// joinMidGame joins players to the global room roomId with session tokens and
+// marks the game as started
+func joinMidGame(t *testing.T, roomId string, ids ...string) ([]*internal.Player, []*fakeConn) {
+	t.Helper()
+	players := make([]*internal.Player, 0, len(ids))
+	conns := make([]*fakeConn, 0, len(ids))
+	for _, id := range ids {
+		p, c := joinTestRoom(t, roomId, id)
+		p.SessionToken = "token-" + id
+		players = append(players, p)
+		conns = append(conns, c)
+	}
+	room := players[0].Room
+	t.Cleanup(func() { CleanupRoom(room) })
+	room.Mu.Lock()
+	room.HasGameStarted = true
+	room.Mu.Unlock()
+	return players, conns
+}
+
+// rejoin is a fresh connection presenting player's session token
+func rejoin(t *testing.T, roomId string, player *internal.Player, username string) (*internal.Player, *fakeConn, bool) {
+	t.Helper()
+	conn := newFakeConn()
+	incoming := &internal.Player{
+		Id:           "fresh-id",
+		Conn:         conn,
+		Username:     username,
+		CanvasWidth:  internal.CanvasWidth,
+		CanvasHeight: internal.CanvasHeight,
+	}
+	restored, ok := ReconnectPlayer(roomId, incoming, player.SessionToken)
+	return restored, conn, ok
+}
+
+func TestReconnectRestoresScore(t *testing.T) {
+	players, conns := joinMidGame(t, "reconnect-score", "alice", "bob", "carol")
+	alice := players[0]
+	room := alice.Room
+	room.Mu.Lock()
+	alice.Score = 240
+	alice.HasGuessed = true
+	room.Mu.Unlock()
+
+	disconnectPlayer(alice)
+
+	room.Mu.RLock()
+	held, connected := room.Players[alice.Id] == alice, alice.IsConnected
+	room.Mu.RUnlock()
+	if !held || connected {
+		t.Fatalf("expected alice's seat to be held while disconnected (held=%v connected=%v)", held, connected)
+	}
+
+	restored, newConn, ok := rejoin(t, "reconnect-score", alice, "")
+	if !ok || restored != alice {
+		t.Fatalf("expected alice's seat to be reclaimed, got %+v ok=%v", restored, ok)
+	}
+	if alice.Score != 240 || !alice.HasGuessed || !alice.IsConnected || alice.Id == "fresh-id" {
+		t.Errorf("expected score, guess state and id restored, got %+v", alice)
+	}
+	if alice.Username != "alice" {
+		t.Errorf("expected the stored username without ?username=, got %q", alice.Username)
+	}
+	msg, ok := newConn.waitFor("welcome_msg", time.Second)
+	if !ok {
+		t.Fatal("expected welcome_msg on the new connection")
+	}
+	var welcome struct {
+		PlayerID     string `json:"player_id"`
+		SessionToken string `json:"session_token"`
+	}
+	if err := json.Unmarshal(msg.Data, &welcome); err != nil {
+		t.Fatalf("bad welcome_msg payload: %v", err)
+	}
+	if welcome.PlayerID != alice.Id || welcome.SessionToken != alice.SessionToken {
+		t.Errorf("expected the original id and session, got %+v", welcome)
+	}
+	if _, ok := conns[1].waitFor("player_reconnected", time.Second); !ok {
+		t.Error("expected others to be told alice is back")
+	}
+
+	// The session is spent once reclaimed
+	if _, _, ok := rejoin(t, "reconnect-score", alice, ""); ok {
+		t.Error("expected a second reconnect with the same session to fail while connected")
+	}
+}
+
+func TestReconnectWithUsernameRenames(t *testing.T) {
+	players, _ := joinMidGame(t, "reconnect-rename", "alice", "bob", "carol")
+	alice := players[0]
+
+	disconnectPlayer(alice)
+	if _, _, ok := rejoin(t, "reconnect-rename", alice, "alicia"); !ok {
+		t.Fatal("expected reconnect to succeed")
+	}
+	if alice.Username != "alicia" {
+		t.Errorf("expected an explicit username to replace the stored one, got %q", alice.Username)
+	}
+}
+
+func TestDrawerReconnectResumesTurn(t *testing.T) {
+	players, _ := joinMidGame(t, "reconnect-drawer", "alice", "bob", "carol")
+	alice := players[0]
+	room := alice.Room
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+	room.Mu.Lock()
+	makeDrawer(room, alice)
+	room.Word = "apple"
+	room.Mu.Unlock()
+
+	disconnectPlayer(alice)
+	room.Mu.RLock()
+	canDraw := alice.CanDraw
+	room.Mu.RUnlock()
+	if canDraw {
+		t.Error("expected a disconnected drawer to lose drawing rights")
+	}
+
+	_, newConn, ok := rejoin(t, "reconnect-drawer", alice, "")
+	if !ok {
+		t.Fatal("expected the drawer to reconnect")
+	}
+	msg, ok := newConn.waitFor("drawing_phase", time.Second)
+	if !ok {
+		t.Fatal("expected the word to be resent to the returning drawer")
+	}
+	var data map[string]any
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad drawing_phase payload: %v", err)
+	}
+	if data["current_word"] != "apple" {
+		t.Errorf("expected the current word, got %v", data["current_word"])
+	}
+	room.Mu.RLock()
+	canDraw, current := alice.CanDraw, room.Current
+	room.Mu.RUnlock()
+	if !canDraw || current != alice {
+		t.Error("expected the drawer to resume their turn")
+	}
+}
+
+func TestDisconnectedPlayerPurgedAfterGrace(t *testing.T) {
+	prev := ReconnectGracePeriod
+	ReconnectGracePeriod = 20 * time.Millisecond
+	t.Cleanup(func() { ReconnectGracePeriod = prev })
+
+	players, _ := joinMidGame(t, "reconnect-expired", "alice", "bob", "carol")
+	alice := players[0]
+	room := alice.Room
+
+	disconnectPlayer(alice)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		room.Mu.RLock()
+		_, present := room.Players[alice.Id]
+		room.Mu.RUnlock()
+		if !present {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected the player to be removed once the grace period passed")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if _, _, ok := rejoin(t, "reconnect-expired", alice, ""); ok {
+		t.Error("expected an expired session not to reconnect")
+	}
+}
+
+func TestLobbyDisconnectRemovesImmediately(t *testing.T) {
+	alice, _ := joinTestRoom(t, "reconnect-lobby", "alice")
+	joinTestRoom(t, "reconnect-lobby", "bob")
+	alice.SessionToken = "token-alice"
+	room := alice.Room
+
+	disconnectPlayer(alice)
+
+	room.Mu.RLock()
+	_, present := room.Players[alice.Id]
+	room.Mu.RUnlock()
+	if present {
+		t.Error("expected a lobby player to be removed without a grace period")
+	}
+}
+
+func withAutoReady(t *testing.T, enabled bool) {
+	t.Helper()
+	prev := AutoReadyOnReconnect
+	AutoReadyOnReconnect = enabled
+	t.Cleanup(func() { AutoReadyOnReconnect = prev })
+}
+
+func TestReconnectIntoLobbyReadiness(t *testing.T) {
+	withLobbyIdle(t, 0, LobbyIdleExclude)
+	for _, enabled := range []bool{true, false} {
+		withAutoReady(t, enabled)
+		roomId := fmt.Sprintf("reconnect-lobby-ready-%v", enabled)
+		players, conns := joinMidGame(t, roomId, "alice", "bob", "carol")
+		alice, room := players[0], players[0].Room
+
+		// Alice drops mid-game and the game ends before she is back
+		disconnectPlayer(alice)
+		ResetRoomToLobby(room)
+
+		restored, _, ok := rejoin(t, roomId, alice, "")
+		if !ok {
+			t.Fatalf("enabled=%v: expected the held seat to be reclaimed", enabled)
+		}
+		room.Mu.RLock()
+		ready, recorded := restored.IsReady, room.PlayersReady[alice.Id]
+		room.Mu.RUnlock()
+		if ready != enabled || recorded != enabled {
+			t.Errorf("enabled=%v: expected ready=%v, got IsReady=%v PlayersReady=%v",
+				enabled, enabled, ready, recorded)
+		}
+
+		msg, ok := conns[1].waitFor("lobby_update", time.Second)
+		if !ok {
+			t.Fatalf("enabled=%v: expected the returning player's ready state to be broadcast", enabled)
+		}
+		var data struct {
+			PlayerID string `json:"player_id"`
+			IsReady  bool   `json:"is_ready"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatalf("bad lobby_update payload: %v", err)
+		}
+		if data.PlayerID != alice.Id || data.IsReady != enabled {
+			t.Errorf("enabled=%v: unexpected lobby_update %+v", enabled, data)
+		}
+	}
+}
+
+func TestReadyLobbyPlayerKeepsSeatWithAutoReady(t *testing.T) {
+	withLobbyIdle(t, 0, LobbyIdleExclude)
+	withAutoReady(t, true)
+	alice, _ := joinTestRoom(t, "reconnect-lobby-held", "alice")
+	joinTestRoom(t, "reconnect-lobby-held", "bob")
+	joinTestRoom(t, "reconnect-lobby-held", "carol")
+	alice.SessionToken = "token-alice"
+	room := alice.Room
+	t.Cleanup(func() { CleanupRoom(room) })
+	HandlePlayerReady(alice, true)
+
+	disconnectPlayer(alice)
+
+	room.Mu.RLock()
+	held := room.Players[alice.Id] == alice && !alice.IsConnected
+	room.Mu.RUnlock()
+	if !held {
+		t.Fatal("expected a ready lobby player's seat to be held")
+	}
+
+	restored, _, ok := rejoin(t, "reconnect-lobby-held", alice, "")
+	if !ok {
+		t.Fatal("expected the lobby seat to be reclaimed")
+	}
+	room.Mu.RLock()
+	ready := restored.IsReady
+	room.Mu.RUnlock()
+	if !ready {
+		t.Error("expected the returning player to still be ready")
+	}
+}
+
+func TestReconnectingThenReconnected(t *testing.T) {
+	players, conns := joinMidGame(t, "reconnecting-back", "alice", "bob", "carol")
+	alice, room := players[0], players[0].Room
+
+	disconnectPlayer(alice)
+
+	msg, ok := conns[1].waitFor("player_reconnecting", time.Second)
+	if !ok {
+		t.Fatal("expected others to see the dropped player as reconnecting")
+	}
+	var data struct {
+		PlayerID string `json:"player_id"`
+		WindowMs int64  `json:"reconnect_window_ms"`
+	}
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("bad player_reconnecting payload: %v", err)
+	}
+	if data.PlayerID != alice.Id || data.WindowMs != ReconnectGracePeriod.Milliseconds() {
+		t.Errorf("unexpected player_reconnecting %+v", data)
+	}
+	room.Mu.RLock()
+	snapshot := internal.CreatePlayerSnapshot(alice)
+	room.Mu.RUnlock()
+	if !snapshot.IsReconnecting || snapshot.IsConnected {
+		t.Errorf("expected the public data to show reconnecting, got %+v", snapshot)
+	}
+
+	restored, _, ok := rejoin(t, "reconnecting-back", alice, "")
+	if !ok {
+		t.Fatal("expected the seat to be reclaimed")
+	}
+	if _, ok := conns[1].waitFor("player_reconnected", time.Second); !ok {
+		t.Fatal("expected player_reconnected after the reconnect")
+	}
+	room.Mu.RLock()
+	reconnecting := restored.IsReconnecting
+	room.Mu.RUnlock()
+	if reconnecting {
+		t.Error("expected the reconnecting flag cleared")
+	}
+	if got := len(conns[1].messagesOfType("player_left")); got != 0 {
+		t.Errorf("expected no player_left for a player who came back, got %d", got)
+	}
+}
+
+func TestReconnectingThenLeft(t *testing.T) {
+	prev := ReconnectGracePeriod
+	ReconnectGracePeriod = 100 * time.Millisecond
+	t.Cleanup(func() { ReconnectGracePeriod = prev })
+
+	players, conns := joinMidGame(t, "reconnecting-gone", "alice", "bob", "carol")
+	alice := players[0]
+
+	disconnectPlayer(alice)
+
+	if _, ok := conns[1].waitFor("player_reconnecting", time.Second); !ok {
+		t.Fatal("expected player_reconnecting when the player drops")
+	}
+	if got := len(conns[1].messagesOfType("player_left")); got != 0 {
+		t.Fatalf("expected no player_left inside the window, got %d", got)
+	}
+	if _, ok := conns[1].waitFor("player_left", time.Second); !ok {
+		t.Fatal("expected player_left once the window closed")
+	}
+}
+
+func TestHeldSeatSkippedAsDrawerButKept(t *testing.T) {
+	players, _ := joinMidGame(t, "held-seat-drawer", "alice", "bob", "carol", "dave")
+	alice, bob, carol := players[0], players[1], players[2]
+	room := alice.Room
+	room.Mu.Lock()
+	room.PlayerOrder = []string{"alice", "bob", "carol", "dave"}
+	room.CurrentIndex = 0
+	room.Current = alice
+	room.MaxRounds = 3
+	room.Mu.Unlock()
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	disconnectPlayer(bob)
+	NextRound(room)
+
+	room.Mu.RLock()
+	current, order := room.Current, append([]string(nil), room.PlayerOrder...)
+	room.Mu.RUnlock()
+	if current != carol {
+		t.Errorf("expected the held seat to be skipped and carol to draw, got %v", current.Id)
+	}
+	if len(order) != 4 || order[1] != "bob" {
+		t.Errorf("expected bob to keep the seat in the rotation, got %v", order)
+	}
+
+	// Back in time, bob draws from the seat he kept
+	if _, _, ok := rejoin(t, "held-seat-drawer", bob, ""); !ok {
+		t.Fatal("expected bob to reclaim his seat")
+	}
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if !room.CanTakeTurn("bob") || room.PlayerOrder[1] != "bob" {
+		t.Errorf("expected bob drawable in his old seat, got order %v", room.PlayerOrder)
+	}
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"context"
+	"testing"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// newTestRoom builds a standalone room that is not registered in Rooms
+func newTestRoom(t testing.TB, id string) *internal.Room {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	return &internal.Room{
+		Id:              id,
+		Players:         make(map[string]*internal.Player),
+		PlayersReady:    make(map[string]bool),
+		CorrectGuessers: make([]internal.PlayerGuess, 0),
+		PlayerOrder:     make([]string, 0),
+		Timer:           &internal.GameTimer{IsActive: false},
+		RoundStats:      make([]internal.RoundStats, 0),
+		CanvasState:     make([]internal.PixelMessage, 0),
+		Phase:           internal.PhaseLobby,
+		RoundNumber:     1,
+		MaxRounds:       3,
+		Context:         ctx,
+		Cancel:          cancel,
+	}
+}
+
+// addTestPlayer adds an offline player to room so broadcasts skip it
+func addTestPlayer(room *internal.Room, id string) *internal.Player {
+	player := &internal.Player{
+		Id:           id,
+		Username:     id,
+		Room:         room,
+		CanvasWidth:  internal.CanvasWidth,
+		CanvasHeight: internal.CanvasHeight,
+	}
+	room.Players[id] = player
+	room.PlayerOrder = append(room.PlayerOrder, id)
+	return player
+}
+
+// makeDrawer puts room into drawing phase with player as the current drawer
+func makeDrawer(room *internal.Room, player *internal.Player) {
+	room.Phase = internal.PhaseDrawing
+	room.Current = player
+	player.CanDraw = true
+}
+
+// addConnectedPlayer adds a connected player backed by a fakeConn to room
+func addConnectedPlayer(room *internal.Room, id string) (*internal.Player, *fakeConn) {
+	conn := newFakeConn()
+	player := addTestPlayer(room, id)
+	player.Conn = conn
+	player.IsConnected = true
+	return player, conn
+}
@@ -12,6 +12,9 @@ import (
 // TIMER MANAGEMENT
 // =============================================================================
 
+// now is the clock used for game start/end bookkeeping; tests swap it out
+var now = time.Now
+
 // StartPhaseTimer creates and manages a phase timer with regular updates
 func StartPhaseTimer(room *internal.Room, duration time.Duration, onExpire func()) {
 	log.Printf("[StartPhaseTimer] Room %s: Function called with duration=%v", room.Id, duration)
@@ -28,9 +31,14 @@ func StartPhaseTimer(room *internal.Room, duration time.Duration, onExpire func(
 	room.Mu.Lock()
 	log.Printf("[StartPhaseTimer] Room %s: CancelPhaseTimer completed", room.Id)
 
-	// 2. Create new context with cancellation
+	// 2. Create new context with cancellation, derived from the room context so
+	// cleaning up the room stops the phase timer immediately
 	log.Printf("[StartPhaseTimer] Room %s: Creating context with timeout %v", room.Id, duration)
-	ctx, cancel := context.WithTimeout(context.Background(), duration)
+	roomCtx := room.Context
+	if roomCtx == nil {
+		roomCtx = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(roomCtx, duration)
 	log.Printf("[StartPhaseTimer] Room %s: Context created successfully", room.Id)
 
 	// 3. Create GameTimer struct
@@ -41,8 +49,10 @@ func StartPhaseTimer(room *internal.Room, duration time.Duration, onExpire func(
 		StartTime: startTime,
 		Duration:  duration,
 		IsActive:  true,
+		Phase:     room.Phase,
 		Context:   ctx,
 		Cancel:    cancel,
+		OnExpire:  onExpire,
 	}
 	log.Printf("[StartPhaseTimer] Room %s: Timer started for %v", room.Id, duration)
 	log.Printf("[StartPhaseTimer] Room %s: GameTimer created and assigned to room", room.Id)
@@ -52,13 +62,18 @@ func StartPhaseTimer(room *internal.Room, duration time.Duration, onExpire func(
 	log.Printf("[StartPhaseTimer] Room %s: Lock released", room.Id)
 	// --- End critical section ---
 
+	// Announce the new timer right away so clients see the phase it is timing
+	// without waiting for the first tick
+	BroadcastTimerUpdate(room)
+
 	// 4. Start goroutine (no locks held)
 	log.Printf("[StartPhaseTimer] Room %s: Starting timer goroutine", room.Id)
+	tickInterval := TimerTickInterval
 	go func() {
 		log.Printf("[StartPhaseTimer] Room %s: Timer goroutine started", room.Id)
 
-		log.Printf("[StartPhaseTimer] Room %s: Creating ticker with 1 second interval", room.Id)
-		ticker := time.NewTicker(1 * time.Second)
+		log.Printf("[StartPhaseTimer] Room %s: Creating ticker with %v interval", room.Id, tickInterval)
+		ticker := time.NewTicker(tickInterval)
 		defer func() {
 			log.Printf("[StartPhaseTimer] Room %s: Stopping ticker in defer", room.Id)
 			ticker.Stop()
@@ -100,7 +115,17 @@ func StartPhaseTimer(room *internal.Room, duration time.Duration, onExpire func(
 					log.Printf("[StartPhaseTimer] Room %s: Timer expired after %v", room.Id, duration)
 					log.Printf("[StartPhaseTimer] Room %s: Starting goroutine to call onExpire callback", room.Id)
 					// Run callback in a separate goroutine so timer goroutine can exit immediately
-					go onExpire()
+					go func() {
+						// The room may have been cleaned up between expiry and now
+						if roomCtx.Err() != nil {
+							log.Printf("[StartPhaseTimer] Room %s: Room context done, skipping onExpire", room.Id)
+							return
+						}
+						onExpire()
+					}()
+				} else if roomCtx.Err() != nil {
+					// Room cleaned up
+					log.Printf("[StartPhaseTimer] Room %s: Room context cancelled, timer stopped", room.Id)
 				} else {
 					// Cancelled explicitly
 					log.Printf("[StartPhaseTimer] Room %s: Timer cancelled before expiry", room.Id)
@@ -132,17 +157,22 @@ func BroadcastTimerUpdate(room *internal.Room) {
 		return
 	}
 
-	remaining := max(room.Timer.Duration-time.Since(room.Timer.StartTime), 0)
+	remaining := time.Duration(room.RemainingTime()) * time.Millisecond
 	room.Timer.TimeRemaining = remaining
 
 	// Snapshot timer update
 	timerUpdateData := internal.TimerUpdateData{
 		TimeRemaining: remaining.Milliseconds(),
-		Phase:         room.Phase,
+		Phase:         room.Timer.Phase,
 		IsActive:      room.Timer.IsActive,
 	}
 	roomID := room.Id
 
+	var warnings []time.Duration
+	if room.Timer.Phase == internal.PhaseDrawing {
+		warnings = dueTimeWarnings(room, remaining)
+	}
+
 	room.Mu.Unlock()
 
 	log.Printf(
@@ -157,8 +187,48 @@ func BroadcastTimerUpdate(room *internal.Room) {
 		Type: "timer_update",
 		Data: timerUpdateData,
 	})
+
+	for _, threshold := range warnings {
+		log.Printf("[BroadcastTimerUpdate] room=%s: %v of drawing time left, sending time_warning", roomID, threshold)
+		SafeBroadcastToRoom(room, internal.Message[any]{
+			Type: "time_warning",
+			Data: map[string]any{
+				"room_id":           roomID,
+				"threshold_ms":      threshold.Milliseconds(),
+				"seconds_left":      int(threshold.Seconds()),
+				"time_remaining_ms": timerUpdateData.TimeRemaining,
+			},
+		})
+	}
 }
 
+// resetTimeWarnings re-arms the drawing time warnings for a new turn of the
+// given length. Thresholds the turn never reaches are marked sent up front.
+// Caller must hold room.Mu.
+func resetTimeWarnings(room *internal.Room, duration time.Duration) {
+	room.TimeWarningsSent = make(map[time.Duration]bool, len(DrawTimeWarnings))
+	for _, threshold := range DrawTimeWarnings {
+		if threshold >= duration {
+			room.TimeWarningsSent[threshold] = true
+		}
+	}
+}
+
+// dueTimeWarnings returns the thresholds reached at remaining that have not
+// been announced yet this turn, marking them sent. Caller must hold room.Mu.
+func dueTimeWarnings(room *internal.Room, remaining time.Duration) []time.Duration {
+	if room.TimeWarningsSent == nil {
+		room.TimeWarningsSent = make(map[time.Duration]bool, len(DrawTimeWarnings))
+	}
+	var due []time.Duration
+	for _, threshold := range DrawTimeWarnings {
+		if remaining <= threshold && !room.TimeWarningsSent[threshold] {
+			room.TimeWarningsSent[threshold] = true
+			due = append(due, threshold)
+		}
+	}
+	return due
+}
 
 // CancelPhaseTimer stops current phase timer
 func CancelPhaseTimer(room *internal.Room) {
@@ -201,10 +271,12 @@ func CancelPhaseTimer(room *internal.Room) {
 	room.Timer.TimeRemaining = 0
 
 	// Snapshot update before unlock
-	log.Printf("[CancelPhaseTimer] Room %s: Creating timer update data snapshot - Phase: %s", room.Id, room.Phase)
+	// Report the phase the timer was timing, not room.Phase: callers often set
+	// the next phase before replacing the previous phase's timer
+	log.Printf("[CancelPhaseTimer] Room %s: Creating timer update data snapshot - Phase: %s", room.Id, room.Timer.Phase)
 	timerUpdateData := internal.TimerUpdateData{
 		TimeRemaining: 0,
-		Phase:         room.Phase,
+		Phase:         room.Timer.Phase,
 		IsActive:      false,
 	}
 	roomID := room.Id
@@ -224,3 +296,76 @@ func CancelPhaseTimer(room *internal.Room) {
 	})
 	log.Printf("[CancelPhaseTimer] Room %s: Timer cancellation completed successfully", roomID)
 }
+
+// PausePhaseTimer stops the current phase timer without expiring it, keeping
+// the remaining time so ResumePhaseTimer can pick up where it left off
+func PausePhaseTimer(room *internal.Room) bool {
+	if room == nil {
+		return false
+	}
+
+	room.Mu.Lock()
+	if room.Timer == nil || !room.Timer.IsActive || room.Timer.IsPaused {
+		log.Printf("[PausePhaseTimer] Room %s: No running timer to pause", room.Id)
+		room.Mu.Unlock()
+		return false
+	}
+
+	remaining := time.Duration(room.RemainingTime()) * time.Millisecond
+	room.Timer.IsPaused = true
+	room.Timer.PausedAt = time.Now()
+	room.Timer.IsActive = false
+	room.Timer.TimeRemaining = remaining
+	if room.Timer.Cancel != nil {
+		// Cancelled (not expired), so onExpire won't run
+		room.Timer.Cancel()
+	}
+
+	timerUpdateData := internal.TimerUpdateData{
+		TimeRemaining: remaining.Milliseconds(),
+		Phase:         room.Timer.Phase,
+		IsActive:      false,
+		IsPaused:      true,
+	}
+	roomID := room.Id
+	room.Mu.Unlock()
+
+	log.Printf("[PausePhaseTimer] Room %s: Timer paused with %v remaining", roomID, remaining)
+	SafeBroadcastToRoom(room, internal.Message[any]{
+		Type: "timer_update",
+		Data: timerUpdateData,
+	})
+	return true
+}
+
+// ResumePhaseTimer restarts a paused phase timer with its remaining duration
+func ResumePhaseTimer(room *internal.Room) bool {
+	if room == nil {
+		return false
+	}
+
+	room.Mu.Lock()
+	if room.Timer == nil || !room.Timer.IsPaused {
+		log.Printf("[ResumePhaseTimer] Room %s: No paused timer to resume", room.Id)
+		room.Mu.Unlock()
+		return false
+	}
+
+	remaining := room.Timer.TimeRemaining
+	onExpire := room.Timer.OnExpire
+	room.Timer.IsPaused = false
+	// The pause doesn't count towards the phase's length for the stuck room
+	// monitor
+	if !room.PhaseChangedAt.IsZero() {
+		room.PhaseChangedAt = room.PhaseChangedAt.Add(time.Since(room.Timer.PausedAt))
+	}
+	room.Mu.Unlock()
+
+	if onExpire == nil {
+		onExpire = func() {}
+	}
+
+	log.Printf("[ResumePhaseTimer] Room %s: Resuming timer with %v remaining", room.Id, remaining)
+	StartPhaseTimer(room, remaining, onExpire)
+	return true
+}
@@ -0,0 +1,325 @@
+package game
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func TestStartPhaseTimerFiresOnExpire(t *testing.T) {
+	room := newTestRoom(t, "timer-expire")
+
+	var fired atomic.Bool
+	StartPhaseTimer(room, 20*time.Millisecond, func() { fired.Store(true) })
+
+	deadline := time.Now().Add(time.Second)
+	for !fired.Load() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if !fired.Load() {
+		t.Fatal("expected onExpire to run after the timer elapsed")
+	}
+}
+
+func TestRoomCancelPreventsOnExpire(t *testing.T) {
+	room := newTestRoom(t, "timer-room-cancel")
+	addTestPlayer(room, "a")
+	addTestPlayer(room, "b")
+
+	var nextRoundCalled atomic.Bool
+	StartPhaseTimer(room, 50*time.Millisecond, func() {
+		nextRoundCalled.Store(true)
+		NextRound(room)
+	})
+
+	room.Cancel()
+	time.Sleep(150 * time.Millisecond)
+
+	if nextRoundCalled.Load() {
+		t.Fatal("expected room cancellation to stop the phase timer before NextRound")
+	}
+	if room.Timer.Context.Err() == nil {
+		t.Error("expected phase timer context to be cancelled with the room")
+	}
+}
+
+func TestPauseResumePreservesRemainingTime(t *testing.T) {
+	room := newTestRoom(t, "timer-pause")
+
+	var fired atomic.Bool
+	StartPhaseTimer(room, 400*time.Millisecond, func() { fired.Store(true) })
+
+	time.Sleep(100 * time.Millisecond)
+	if !PausePhaseTimer(room) {
+		t.Fatal("expected running timer to pause")
+	}
+
+	room.Mu.RLock()
+	remaining := room.Timer.TimeRemaining
+	room.Mu.RUnlock()
+	if diff := remaining - 300*time.Millisecond; diff < -50*time.Millisecond || diff > 50*time.Millisecond {
+		t.Fatalf("expected ~300ms remaining after pause, got %v", remaining)
+	}
+
+	// Paused longer than the remaining time: must not expire
+	time.Sleep(400 * time.Millisecond)
+	if fired.Load() {
+		t.Fatal("expected paused timer not to expire")
+	}
+
+	if !ResumePhaseTimer(room) {
+		t.Fatal("expected paused timer to resume")
+	}
+	room.Mu.RLock()
+	resumedDuration := room.Timer.Duration
+	room.Mu.RUnlock()
+	if resumedDuration != remaining {
+		t.Errorf("expected resumed timer to run for %v, got %v", remaining, resumedDuration)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !fired.Load() && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if !fired.Load() {
+		t.Fatal("expected resumed timer to expire and run onExpire")
+	}
+}
+
+func TestPauseWithoutRunningTimer(t *testing.T) {
+	room := newTestRoom(t, "timer-pause-idle")
+	if PausePhaseTimer(room) {
+		t.Error("expected pause to fail when no timer is running")
+	}
+	if ResumePhaseTimer(room) {
+		t.Error("expected resume to fail when no timer is paused")
+	}
+}
+
+func timerUpdates(t *testing.T, conn *fakeConn) []internal.TimerUpdateData {
+	t.Helper()
+	var updates []internal.TimerUpdateData
+	for _, msg := range conn.messagesOfType("timer_update") {
+		var data internal.TimerUpdateData
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatalf("bad timer_update payload: %v", err)
+		}
+		updates = append(updates, data)
+	}
+	return updates
+}
+
+func TestBroadcastTimerUpdateOnlyWhileActive(t *testing.T) {
+	room := newTestRoom(t, "timer-update-active")
+	_, conn := addConnectedPlayer(room, "alice")
+
+	room.Timer = &internal.GameTimer{
+		StartTime: time.Now(),
+		Duration:  30 * time.Second,
+		IsActive:  true,
+		Phase:     internal.PhaseWaiting,
+	}
+	BroadcastTimerUpdate(room)
+	flushBroadcasts(t, room, conn)
+	if got := len(timerUpdates(t, conn)); got != 1 {
+		t.Fatalf("expected an active timer to broadcast one update, got %d", got)
+	}
+
+	room.Timer.IsActive = false
+	BroadcastTimerUpdate(room)
+	flushBroadcasts(t, room, conn)
+	if got := len(timerUpdates(t, conn)); got != 1 {
+		t.Errorf("expected an inactive timer to broadcast nothing, got %d updates", got)
+	}
+}
+
+func TestTimerUpdatesCarryTimedPhase(t *testing.T) {
+	room := newTestRoom(t, "timer-phase")
+	_, conn := addConnectedPlayer(room, "alice")
+
+	phases := []internal.GamePhase{
+		internal.PhaseWaiting,
+		internal.PhaseDrawing,
+		internal.PhaseRevealing,
+		internal.PhaseEnded,
+	}
+	for i, phase := range phases {
+		// Callers set the next phase before replacing the previous timer
+		room.Mu.Lock()
+		room.Phase = phase
+		room.Mu.Unlock()
+		StartPhaseTimer(room, time.Minute, func() {})
+		flushBroadcasts(t, room, conn)
+
+		updates := timerUpdates(t, conn)
+		first := updates[len(updates)-1]
+		if !first.IsActive || first.Phase != phase {
+			t.Errorf("expected first update for %s to be active and carry its phase, got %+v", phase, first)
+		}
+		if i > 0 {
+			cancelled := updates[len(updates)-2]
+			if cancelled.IsActive || cancelled.Phase != phases[i-1] {
+				t.Errorf("expected cancel update to carry previous phase %s, got %+v", phases[i-1], cancelled)
+			}
+		}
+	}
+	CancelPhaseTimer(room)
+}
+
+func TestDrawTimeWarningsFireOncePerThreshold(t *testing.T) {
+	prevWarnings, prevTick := DrawTimeWarnings, TimerTickInterval
+	DrawTimeWarnings = []time.Duration{time.Second, 200 * time.Millisecond, 100 * time.Millisecond}
+	TimerTickInterval = 20 * time.Millisecond
+	t.Cleanup(func() { DrawTimeWarnings, TimerTickInterval = prevWarnings, prevTick })
+
+	room := newTestRoom(t, "time-warnings")
+	_, conn := addConnectedPlayer(room, "alice")
+	room.Mu.Lock()
+	room.Phase = internal.PhaseDrawing
+	resetTimeWarnings(room, 300*time.Millisecond)
+	room.Mu.Unlock()
+
+	expired := make(chan struct{})
+	StartPhaseTimer(room, 300*time.Millisecond, func() { close(expired) })
+	select {
+	case <-expired:
+	case <-time.After(2 * time.Second):
+		t.Fatal("timer never expired")
+	}
+
+	var got []int64
+	for _, msg := range conn.messagesOfType("time_warning") {
+		var data struct {
+			ThresholdMs     int64 `json:"threshold_ms"`
+			TimeRemainingMs int64 `json:"time_remaining_ms"`
+		}
+		if err := json.Unmarshal(msg.Data, &data); err != nil {
+			t.Fatalf("bad time_warning payload: %v", err)
+		}
+		if data.TimeRemainingMs > data.ThresholdMs {
+			t.Errorf("warning for %dms sent early with %dms left", data.ThresholdMs, data.TimeRemainingMs)
+		}
+		got = append(got, data.ThresholdMs)
+	}
+	if len(got) != 2 || got[0] != 200 || got[1] != 100 {
+		t.Errorf("expected one warning each at 200ms and 100ms (none for the 1s threshold), got %v", got)
+	}
+}
+
+func TestTimeWarningsOnlyForDrawingPhase(t *testing.T) {
+	prevWarnings := DrawTimeWarnings
+	DrawTimeWarnings = []time.Duration{time.Hour}
+	t.Cleanup(func() { DrawTimeWarnings = prevWarnings })
+
+	room := newTestRoom(t, "time-warnings-waiting")
+	_, conn := addConnectedPlayer(room, "alice")
+	room.Mu.Lock()
+	room.Phase = internal.PhaseWaiting
+	room.Mu.Unlock()
+
+	StartPhaseTimer(room, time.Minute, func() {})
+	CancelPhaseTimer(room)
+	if got := len(conn.messagesOfType("time_warning")); got != 0 {
+		t.Errorf("expected no warnings outside the drawing phase, got %d", got)
+	}
+}
+
+func TestTimeRemainingIsMillisecondsEverywhere(t *testing.T) {
+	alice, aliceConn := joinTestRoom(t, "timer-units", "alice")
+	room := alice.Room
+	StartPhaseTimer(room, 30*time.Second, func() {})
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	inMillis := func(where string, got int64) {
+		t.Helper()
+		if got <= 29000 || got > 30000 {
+			t.Errorf("expected %s to report just under 30000ms, got %d", where, got)
+		}
+	}
+
+	flushBroadcasts(t, room, aliceConn)
+	updates := timerUpdates(t, aliceConn)
+	if len(updates) == 0 {
+		t.Fatal("expected a timer_update when the timer starts")
+	}
+	inMillis("timer_update", updates[len(updates)-1].TimeRemaining)
+
+	_, bobConn := joinTestRoom(t, "timer-units", "bob")
+	msg, ok := bobConn.waitFor("welcome_msg", time.Second)
+	if !ok {
+		t.Fatal("expected welcome_msg")
+	}
+	var welcome struct {
+		GameState internal.GameStateData `json:"game_state"`
+	}
+	if err := json.Unmarshal(msg.Data, &welcome); err != nil {
+		t.Fatalf("bad welcome_msg payload: %v", err)
+	}
+	inMillis("welcome_msg", welcome.GameState.TimeRemaining)
+
+	room.Mu.Lock()
+	room.PlayerOrder = []string{"alice", "bob"}
+	room.Mu.Unlock()
+	broadcastGameStateNow(room)
+	msg, ok = aliceConn.waitFor("game_state_update", time.Second)
+	if !ok {
+		t.Fatal("expected a game_state_update")
+	}
+	var state internal.GameStateData
+	if err := json.Unmarshal(msg.Data, &state); err != nil {
+		t.Fatalf("bad game_state_update payload: %v", err)
+	}
+	inMillis("game_state_update", state.TimeRemaining)
+}
+
+func TestWordSelectionReportsSelectionTimerMs(t *testing.T) {
+	room := newTestRoom(t, "timer-selection-units")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	_, guesserConn := addConnectedPlayer(room, "guesser")
+	room.Mu.Lock()
+	room.Phase = internal.PhaseWaiting
+	room.Current = drawer
+	room.Mu.Unlock()
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+
+	StartWordSelection(room)
+	full := WordSelectionTimeout.Milliseconds()
+	selectionLimit := func(n int) int64 {
+		t.Helper()
+		msgs := waitForCount(t, drawerConn, "word_selection", n)
+		var data internal.WordSelectionData
+		if err := json.Unmarshal(msgs[len(msgs)-1].Data, &data); err != nil {
+			t.Fatalf("bad word_selection payload: %v", err)
+		}
+		return data.TimeLimit
+	}
+	if got := selectionLimit(1); got <= full-1000 || got > full {
+		t.Errorf("expected word_selection to report just under %dms, got %d", full, got)
+	}
+
+	msg, ok := guesserConn.waitFor("waiting_for_word", time.Second)
+	if !ok {
+		t.Fatal("expected waiting_for_word")
+	}
+	var waiting struct {
+		TimeRemainingMs int64 `json:"time_remaining_ms"`
+	}
+	if err := json.Unmarshal(msg.Data, &waiting); err != nil {
+		t.Fatalf("bad waiting_for_word payload: %v", err)
+	}
+	if waiting.TimeRemainingMs <= full-1000 || waiting.TimeRemainingMs > full {
+		t.Errorf("expected waiting_for_word to report just under %dms, got %d", full, waiting.TimeRemainingMs)
+	}
+
+	// A resend reports what is left, not the full timeout
+	time.Sleep(200 * time.Millisecond)
+	if !ResendWordSelection(drawer) {
+		t.Fatal("expected the choices to be resent")
+	}
+	if got := selectionLimit(2); got > full-200 {
+		t.Errorf("expected the resent choices to report under %dms, got %d", full-200, got)
+	}
+}
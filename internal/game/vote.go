@@ -0,0 +1,97 @@
+package game
+
+import (
+	"log"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// =============================================================================
+// VOTE TO SKIP
+// =============================================================================
+
+// HandleVoteSkip records a guesser's vote to skip the current drawing. Once a
+// majority of the connected guessers have voted, the drawing ends and the
+// word is revealed; guesses made before that keep their points. Every vote
+// broadcasts skip_vote_update with the current and needed counts.
+func HandleVoteSkip(player *internal.Player) {
+	room := player.Room
+	if room == nil {
+		log.Printf("[HandleVoteSkip] Player %s has no room reference", player.Username)
+		return
+	}
+
+	room.Mu.Lock()
+	// 1. Only guessers may vote, once each, while a drawing is on
+	if room.Phase != internal.PhaseDrawing || room.SkipVotes == nil {
+		room.Mu.Unlock()
+		log.Printf("[HandleVoteSkip] room=%s: no drawing to skip, ignoring vote from %s", room.Id, player.Id)
+		return
+	}
+	if room.Current == player {
+		room.Mu.Unlock()
+		log.Printf("[HandleVoteSkip] room=%s: drawer %s can't vote to skip", room.Id, player.Id)
+		SendErrorToPlayer(player, "drawer_cannot_vote", "The drawer can't vote to skip their own drawing")
+		return
+	}
+	if room.SkipVotes[player.Id] {
+		room.Mu.Unlock()
+		return
+	}
+	room.SkipVotes[player.Id] = true
+
+	// 2. Tally against a majority of the guessers still connected
+	votes, needed := skipVoteCounts(room)
+	skipped := votes >= needed
+	if skipped {
+		// Closes the vote so late votes don't skip twice
+		room.SkipVotes = nil
+	}
+	queueBroadcastLocked(room, internal.Message[any]{
+		Type: "skip_vote_update",
+		Data: map[string]any{
+			"room_id":   room.Id,
+			"player_id": player.Id,
+			"votes":     votes,
+			"needed":    needed,
+			"skipped":   skipped,
+			"timestamp": time.Now().UnixMilli(),
+		},
+	}, nil)
+	roomID := room.Id
+	turnStartedAt := room.PhaseChangedAt
+	room.Mu.Unlock()
+
+	log.Printf("[HandleVoteSkip] room=%s: %s voted to skip (%d/%d)", roomID, player.Id, votes, needed)
+
+	// 3. Majority reached: end the drawing and reveal the word
+	if skipped {
+		// The turn may have ended (timer, last guess) since the vote passed
+		room.Mu.RLock()
+		sameTurn := room.Phase == internal.PhaseDrawing && room.PhaseChangedAt.Equal(turnStartedAt)
+		room.Mu.RUnlock()
+		if !sameTurn {
+			log.Printf("[HandleVoteSkip] room=%s: turn already over, not revealing again", roomID)
+			return
+		}
+		log.Printf("[HandleVoteSkip] room=%s: skip vote passed, revealing the word", roomID)
+		StartRevealingPhase(room)
+	}
+}
+
+// skipVoteCounts returns the votes cast by connected guessers and how many
+// make a majority of them. Caller must hold room.Mu.
+func skipVoteCounts(room *internal.Room) (votes, needed int) {
+	guessers := 0
+	for _, p := range room.Players {
+		if p == room.Current || !p.IsConnected {
+			continue
+		}
+		guessers++
+		if room.SkipVotes[p.Id] {
+			votes++
+		}
+	}
+	return votes, guessers/2 + 1
+}
@@ -0,0 +1,105 @@
+package game
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+type skipVoteUpdate struct {
+	Votes   int  `json:"votes"`
+	Needed  int  `json:"needed"`
+	Skipped bool `json:"skipped"`
+}
+
+func lastSkipVoteUpdate(t *testing.T, conn *fakeConn, n int) skipVoteUpdate {
+	t.Helper()
+	msgs := waitForCount(t, conn, "skip_vote_update", n)
+	var update skipVoteUpdate
+	if err := json.Unmarshal(msgs[len(msgs)-1].Data, &update); err != nil {
+		t.Fatalf("bad skip_vote_update payload: %v", err)
+	}
+	return update
+}
+
+// startVoteTurn puts drawer mid-drawing with a fresh skip vote
+func startVoteTurn(t *testing.T, room *internal.Room, drawer *internal.Player) {
+	t.Helper()
+	room.Mu.Lock()
+	room.Current = drawer
+	room.Word = "apple"
+	room.DrawingStarted = false
+	room.Mu.Unlock()
+	StartDrawingPhase(room)
+	t.Cleanup(func() { CancelPhaseTimer(room) })
+}
+
+func TestSkipVoteMajorityRevealsWord(t *testing.T) {
+	room := newTestRoom(t, "skip-vote")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	alice, _ := addConnectedPlayer(room, "alice")
+	bob, _ := addConnectedPlayer(room, "bob")
+	carol, _ := addConnectedPlayer(room, "carol")
+	dave, _ := addConnectedPlayer(room, "dave")
+	startVoteTurn(t, room, drawer)
+
+	// The drawer's vote doesn't count, nor does voting twice
+	HandleVoteSkip(drawer)
+	HandleVoteSkip(alice)
+	HandleVoteSkip(alice)
+	HandleVoteSkip(bob)
+
+	if update := lastSkipVoteUpdate(t, drawerConn, 2); update.Votes != 2 || update.Needed != 3 || update.Skipped {
+		t.Fatalf("expected 2 of 3 needed votes, got %+v", update)
+	}
+	room.Mu.RLock()
+	phase := room.Phase
+	room.Mu.RUnlock()
+	if phase != internal.PhaseDrawing {
+		t.Fatalf("expected drawing to go on below the majority, got %s", phase)
+	}
+
+	HandleVoteSkip(carol)
+	if update := lastSkipVoteUpdate(t, drawerConn, 3); !update.Skipped {
+		t.Errorf("expected the third of four guessers to pass the vote, got %+v", update)
+	}
+	if _, ok := drawerConn.waitFor("round_end", time.Second); !ok {
+		t.Fatal("expected the word to be revealed once the vote passed")
+	}
+
+	// A late vote after the skip changes nothing
+	HandleVoteSkip(dave)
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	if room.Phase != internal.PhaseRevealing || len(room.CorrectGuessers) != 0 {
+		t.Errorf("expected a reveal with nobody credited, got phase=%s guessers=%d",
+			room.Phase, len(room.CorrectGuessers))
+	}
+	if got := len(drawerConn.messagesOfType("skip_vote_update")); got != 3 {
+		t.Errorf("expected no update for a vote after the skip, got %d updates", got)
+	}
+}
+
+func TestSkipVotesResetEachDrawing(t *testing.T) {
+	room := newTestRoom(t, "skip-vote-reset")
+	drawer, drawerConn := addConnectedPlayer(room, "drawer")
+	alice, _ := addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	addConnectedPlayer(room, "carol")
+	startVoteTurn(t, room, drawer)
+
+	HandleVoteSkip(alice)
+	if update := lastSkipVoteUpdate(t, drawerConn, 1); update.Votes != 1 {
+		t.Fatalf("expected alice's vote to count, got %+v", update)
+	}
+
+	// Next turn: the old vote is gone, so alice can vote again from one
+	CancelPhaseTimer(room)
+	startVoteTurn(t, room, drawer)
+	HandleVoteSkip(alice)
+	if update := lastSkipVoteUpdate(t, drawerConn, 2); update.Votes != 1 || update.Skipped {
+		t.Errorf("expected votes to start over for a new drawing, got %+v", update)
+	}
+}
@@ -0,0 +1,109 @@
+package game
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// =============================================================================
+// WEBHOOK NOTIFICATIONS
+// =============================================================================
+
+var (
+	// WebhookTimeout bounds a single delivery attempt
+	WebhookTimeout = 5 * time.Second
+	// WebhookMaxAttempts caps how many times a delivery is tried
+	WebhookMaxAttempts = 3
+	// WebhookRetryBackoff is multiplied by the attempt number between retries
+	WebhookRetryBackoff = 500 * time.Millisecond
+
+	// webhookClient doesn't follow redirects, so a webhook can't bounce
+	// deliveries on to another address
+	webhookClient = &http.Client{
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+)
+
+// WebhookPayload is the JSON body POSTed to a room's webhook
+type WebhookPayload struct {
+	Event     string `json:"event"`
+	RoomID    string `json:"room_id"`
+	Timestamp int64  `json:"timestamp"`
+	Data      any    `json:"data"`
+}
+
+// NotifyWebhook fires event to the room's webhook URL, if one is configured.
+// Delivery happens in the background and never blocks gameplay.
+func NotifyWebhook(room *internal.Room, event string, data any) {
+	room.Mu.RLock()
+	url := room.WebhookURL
+	roomID := room.Id
+	room.Mu.RUnlock()
+
+	if url == "" {
+		return
+	}
+
+	payload := WebhookPayload{
+		Event:     event,
+		RoomID:    roomID,
+		Timestamp: time.Now().UnixMilli(),
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		log.Printf("[NotifyWebhook] room=%s: failed to encode %s payload: %v", roomID, event, err)
+		return
+	}
+
+	go deliverWebhook(url, roomID, event, body)
+}
+
+// deliverWebhook POSTs body to url, retrying up to WebhookMaxAttempts times
+func deliverWebhook(url string, roomID string, event string, body []byte) {
+	for attempt := 1; attempt <= WebhookMaxAttempts; attempt++ {
+		err := postWebhook(url, body)
+		if err == nil {
+			log.Printf("[deliverWebhook] room=%s: delivered %s (attempt %d)", roomID, event, attempt)
+			return
+		}
+
+		log.Printf("[deliverWebhook] room=%s: attempt %d/%d for %s failed: %v",
+			roomID, attempt, WebhookMaxAttempts, event, err)
+		if attempt < WebhookMaxAttempts {
+			time.Sleep(time.Duration(attempt) * WebhookRetryBackoff)
+		}
+	}
+	log.Printf("[deliverWebhook] room=%s: giving up on %s", roomID, event)
+}
+
+func postWebhook(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.Background(), WebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// webhookRecorder is a local webhook receiver that fails the first failures requests
+func webhookRecorder(t *testing.T, failures int32) (*httptest.Server, <-chan WebhookPayload, *atomic.Int32) {
+	t.Helper()
+
+	received := make(chan WebhookPayload, 16)
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		var payload WebhookPayload
+		if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+			t.Errorf("webhook received invalid JSON: %v", err)
+		}
+		received <- payload
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, received, &attempts
+}
+
+func waitForWebhook(t *testing.T, received <-chan WebhookPayload, event string) WebhookPayload {
+	t.Helper()
+	timeout := time.After(2 * time.Second)
+	for {
+		select {
+		case payload := <-received:
+			if payload.Event == event {
+				return payload
+			}
+		case <-timeout:
+			t.Fatalf("timed out waiting for %s webhook", event)
+		}
+	}
+}
+
+func TestNotifyWebhookDeliversPayload(t *testing.T) {
+	srv, received, _ := webhookRecorder(t, 0)
+	room := newTestRoom(t, "webhook-room")
+	room.WebhookURL = srv.URL
+
+	NotifyWebhook(room, "round_end", map[string]any{"word": "apple"})
+
+	payload := waitForWebhook(t, received, "round_end")
+	if payload.RoomID != "webhook-room" {
+		t.Errorf("expected room id webhook-room, got %q", payload.RoomID)
+	}
+	data, _ := payload.Data.(map[string]any)
+	if data["word"] != "apple" {
+		t.Errorf("expected data to carry the word, got %+v", payload.Data)
+	}
+}
+
+func TestNotifyWebhookRetriesFailures(t *testing.T) {
+	prev := WebhookRetryBackoff
+	WebhookRetryBackoff = time.Millisecond
+	t.Cleanup(func() { WebhookRetryBackoff = prev })
+
+	srv, received, attempts := webhookRecorder(t, 2)
+	room := newTestRoom(t, "webhook-retry")
+	room.WebhookURL = srv.URL
+
+	NotifyWebhook(room, "game_ended", nil)
+
+	waitForWebhook(t, received, "game_ended")
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("expected delivery on the 3rd attempt, got %d attempts", got)
+	}
+}
+
+func TestStartGameFiresWebhook(t *testing.T) {
+	srv, received, _ := webhookRecorder(t, 0)
+	room := newTestRoom(t, "webhook-start")
+	room.WebhookURL = srv.URL
+	addConnectedPlayer(room, "alice")
+	addConnectedPlayer(room, "bob")
+	readyUp(room)
+
+	if err := StartGame(room); err != nil {
+		t.Fatalf("StartGame failed: %v", err)
+	}
+
+	payload := waitForWebhook(t, received, "game_started")
+	if payload.RoomID != "webhook-start" {
+		t.Errorf("expected room id webhook-start, got %q", payload.RoomID)
+	}
+}
+
+func TestRoomWebhookIgnoresClientQuery(t *testing.T) {
+	t.Setenv("ROOM_WEBHOOK_URL", "")
+
+	// Whoever opens a room must not be able to point the server at an
+	// internal address
+	for _, target := range []string{
+		"http://169.254.169.254/latest/meta-data/",
+		"http://127.0.0.1:8080/admin",
+	} {
+		cfg, err := parseRoomConfig(url.Values{"webhook_url": {target}})
+		if err != nil {
+			t.Fatalf("expected the webhook_url parameter to be ignored, got %v", err)
+		}
+		room := getOrCreateRoom("webhook-query", cfg)
+		webhookURL := room.WebhookURL
+		room.Cancel()
+		RoomsMu.Lock()
+		delete(Rooms, room.Id)
+		RoomsMu.Unlock()
+		if webhookURL != "" {
+			t.Errorf("expected no webhook from the client's query, got %q", webhookURL)
+		}
+	}
+}
+
+func TestWebhookDoesNotFollowRedirects(t *testing.T) {
+	var redirected atomic.Int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected.Add(1)
+	}))
+	t.Cleanup(target.Close)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	t.Cleanup(hook.Close)
+
+	if err := postWebhook(hook.URL, []byte(`{}`)); err == nil {
+		t.Error("expected a redirect response to count as a failed delivery")
+	}
+	if got := redirected.Load(); got != 0 {
+		t.Errorf("expected the redirect not to be followed, target hit %d times", got)
+	}
+}
+
+func TestRoomWebhookFallsBackToServerDefault(t *testing.T) {
+	t.Setenv("ROOM_WEBHOOK_URL", "http://default.invalid/hook")
+	room := getOrCreateRoom("webhook-default", internal.RoomConfig{})
+	t.Cleanup(func() {
+		room.Cancel()
+		RoomsMu.Lock()
+		delete(Rooms, room.Id)
+		RoomsMu.Unlock()
+	})
+	if room.WebhookURL != "http://default.invalid/hook" {
+		t.Errorf("expected the server default webhook, got %q", room.WebhookURL)
+	}
+}
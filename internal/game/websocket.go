@@ -2,14 +2,20 @@ package game
 
 import (
 	"encoding/json"
+	"fmt"
+	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
 	"github.com/scythe504/skribblr-backend/internal"
 	"github.com/scythe504/skribblr-backend/internal/utils"
 	"log"
 	"net/http"
+	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // =============================================================================
@@ -25,9 +31,182 @@ var (
 	Rooms   = make(map[string]*internal.Room)
 	RoomsMu sync.RWMutex
 
-	// Game configuration - TODO: Make these configurable
-	MaxPlayersPerRoom = 8
-	MinPlayersToStart = 2
+	// WordSource offers every room's word choices unless the room sets its
+	// own Words provider
+	WordSource internal.WordProvider = utils.RandomWords{}
+
+	// MaxCanvasOpsPerRound compacts the stored canvas once it holds more
+	// operations than this (0 disables)
+	MaxCanvasOpsPerRound = 500
+
+	// EmptyCanvasSkipAfter skips a drawer's turn once their canvas has had no
+	// visible content (never drawn, or cleared) for this long (0 disables)
+	EmptyCanvasSkipAfter = time.Duration(0)
+
+	// AFKDrawerSkipFraction skips a drawer who hasn't sent a single pixel_draw
+	// once this fraction of the drawing time has passed (0 disables)
+	AFKDrawerSkipFraction = 0.4
+
+	// MaxGameTurns ends a game after this many turns whatever its rounds and
+	// player count, so a long-lived room can't keep one game going forever.
+	// The longest game RoomConfig allows (10 rounds of 12 players) fits. (0 disables)
+	MaxGameTurns = 120
+
+	// ClearCanvasOnNewTurn broadcasts canvas_cleared (reason "new_turn") as
+	// each turn starts, so clients drop the previous drawing right away
+	ClearCanvasOnNewTurn = true
+
+	// UndoHistoryDepth caps how many canvas snapshots are kept for undo per round
+	UndoHistoryDepth = 20
+
+	// ProximityHintsEnabled privately tells wrong guessers if they are hot/warm/cold.
+	// Off by default since it can make guessing too easy.
+	ProximityHintsEnabled = false
+
+	// CloseGuessDistance is the edit distance within which a wrong guess is
+	// kept private and answered with a "close_guess" nudge instead of being
+	// broadcast (0 disables)
+	CloseGuessDistance = 1
+
+	// DrawerOrderStrategy picks who draws next within a round:
+	// DrawerOrderRoundRobin, DrawerOrderComeback or DrawerOrderLeaders
+	DrawerOrderStrategy = DrawerOrderRoundRobin
+
+	// RevealDrawOrder includes the planned drawing rotation in game_started
+	RevealDrawOrder = true
+
+	// EmojiHintDelay reveals the word's emoji to guessers if nobody has guessed
+	// it by then (0 disables)
+	EmojiHintDelay = time.Duration(0)
+
+	// DrawTimeWarnings are the remaining drawing times at which a time_warning
+	// is broadcast, once each per turn
+	DrawTimeWarnings = []time.Duration{30 * time.Second, 10 * time.Second}
+	// TimerTickInterval is how often running phase timers broadcast timer_update
+	TimerTickInterval = time.Second
+
+	// DrawTimeByDifficulty gives the drawing phase a per-difficulty length
+	// (e.g. easy 60s, medium 80s, hard 100s). Difficulties without a positive
+	// entry use the fixed DrawingPhaseDuration, which is all of them by default.
+	DrawTimeByDifficulty = map[internal.WordDifficulty]time.Duration{}
+
+	// WordSelectionTimeout is how long the drawer has to pick a word before the
+	// first choice is picked for them
+	WordSelectionTimeout = 15 * time.Second
+
+	// ReconnectGracePeriod is how long a player dropped mid-game keeps their
+	// seat, score and turn for a reconnect with their session token (0 removes
+	// them immediately)
+	ReconnectGracePeriod = 60 * time.Second
+
+	// AutoReadyOnReconnect lets a ready player who drops in the lobby keep
+	// their seat for ReconnectGracePeriod and come back ready, so a brief drop
+	// doesn't undo the readiness the room agreed on. Off, players reconnecting
+	// into the lobby start unready.
+	AutoReadyOnReconnect = false
+
+	// BroadcastQueueSize is how many queued broadcasts a room may have waiting
+	// for its broadcast worker; past that, new broadcasts are dropped
+	BroadcastQueueSize = 1024
+
+	// DefaultClientCanvasWidth and DefaultClientCanvasHeight stand in for a
+	// client canvas size that is missing or not positive
+	DefaultClientCanvasWidth  = internal.CanvasWidth * 10
+	DefaultClientCanvasHeight = internal.CanvasHeight * 10
+
+	// SkipToLobbyOnAbort returns a game aborted for lack of players straight
+	// to the lobby instead of waiting out the results window
+	SkipToLobbyOnAbort = true
+
+	// ShowRoundScoreboard adds each player's points for the round and new
+	// total to round_end
+	ShowRoundScoreboard = true
+
+	// ShowGuessProgress adds how many guessers have got the word so far (e.g.
+	// "3/6 guessed") to drawing_phase and game_state_update while drawing
+	ShowGuessProgress = true
+
+	// HideWordDifficulty keeps the chosen word's difficulty and base points out
+	// of guessers' payloads; the drawer always sees them
+	HideWordDifficulty = true
+
+	// IgnoreGuessArticles is the default for new rooms' lenient article matching
+	IgnoreGuessArticles = false
+
+	// UnknownMessageReplyInterval limits how often a player is told about
+	// unknown message types, so a misbehaving client can't flood logs
+	UnknownMessageReplyInterval = time.Second
+
+	// GameStateBroadcastWindow coalesces BroadcastGameState calls made within
+	// this window into one broadcast of the latest state (0 sends immediately)
+	GameStateBroadcastWindow = 50 * time.Millisecond
+
+	// PixelBroadcastWindow batches a drawer's single-pixel places made within
+	// this window into batch_place broadcasts (0 sends each pixel as it lands)
+	PixelBroadcastWindow = 50 * time.Millisecond
+
+	// RandomWordEnabled lets the drawer send random_word to take a random one
+	// of their offered words instead of choosing
+	RandomWordEnabled = true
+
+	// MinGuessesForAccuracyAward is how many guesses a player must make to be
+	// considered for the most accurate award in the final results
+	MinGuessesForAccuracyAward = 3
+
+	// MaxGuessLength is the longest guess (in characters) accepted from a player
+	MaxGuessLength = 100
+	// MaxChatLength is the longest chat message (in characters) accepted
+	MaxChatLength = 200
+
+	// LobbyIdleTimeout is how long a player may stay unready in the lobby
+	// (0, the default, disables the check)
+	LobbyIdleTimeout time.Duration = 0
+	// LobbyIdleAction is applied to idle players: LobbyIdleExclude or LobbyIdleKick
+	LobbyIdleAction = LobbyIdleExclude
+
+	// AdminToken authorizes admin calls such as ForceWord; set from the
+	// ADMIN_TOKEN env var (empty disables them)
+	AdminToken = ""
+)
+
+// GameConfig holds the player limits, which may be changed while games run.
+// Read it with GetGameConfig and replace it with SetGameConfig; a loaded
+// value is a copy, so concurrent readers never see a half-applied update.
+type GameConfig struct {
+	MaxPlayersPerRoom int
+	MinPlayersToStart int
+}
+
+var gameConfig atomic.Pointer[GameConfig]
+
+func init() {
+	SetGameConfig(GameConfig{
+		MaxPlayersPerRoom: internal.MaxPlayersPerRoom,
+		MinPlayersToStart: internal.MinPlayersToStart,
+	})
+}
+
+// GetGameConfig returns the current player limits
+func GetGameConfig() GameConfig {
+	return *gameConfig.Load()
+}
+
+// SetGameConfig atomically replaces the player limits
+func SetGameConfig(cfg GameConfig) {
+	gameConfig.Store(&cfg)
+}
+
+// Drawer order strategies
+const (
+	DrawerOrderRoundRobin = "round_robin" // keep the rotation order
+	DrawerOrderComeback   = "comeback"    // lowest score draws next
+	DrawerOrderLeaders    = "leaders"     // highest score draws next
+)
+
+// Lobby idle actions
+const (
+	LobbyIdleExclude = "exclude" // skip them in the readiness check
+	LobbyIdleKick    = "kick"    // disconnect them
 )
 
 // =============================================================================
@@ -37,34 +216,36 @@ var (
 // HandleWebSocket upgrades HTTP connection to WebSocket and initializes player
 func HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// TODO:
-	// 1. Upgrade connection to WebSocket
-	conn, err := Upgrader.Upgrade(w, r, nil)
-	if err != nil {
-		log.Println("Upgrade failed: ", err)
+	// 0. Extract roomId from the route before upgrading, so a bad path gets a plain HTTP error
+	roomId := strings.TrimSpace(mux.Vars(r)["roomId"])
+	if roomId == "" {
+		log.Printf("[HandleWebSocket] Missing room id in path %q", r.URL.Path)
+		http.Error(w, "missing room id: connect to /ws/{roomId}", http.StatusBadRequest)
 		return
 	}
-	// 2. Extract username from query params
-	username := r.URL.Query().Get("username")
-	if username == "" {
-		username = "Anonymous"
-	}
-	width, err := strconv.Atoi(r.URL.Query().Get("w"))
+	roomConfig, err := parseRoomConfig(r.URL.Query())
 	if err != nil {
+		log.Printf("[HandleWebSocket] Bad room config for room %s: %v", roomId, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	height, err := strconv.Atoi(r.URL.Query().Get("h"))
+	width, height, err := clientCanvasSize(r.URL.Query())
 	if err != nil {
+		log.Printf("[HandleWebSocket] Bad canvas size for room %s: %v", roomId, err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	// 3. Extract roomId from URL path
-	roomIdFromUrl := strings.Split(r.URL.Path, "/")
-	if len(roomIdFromUrl) < 2 {
-		log.Println("No room id provided")
-		conn.Close()
+
+	// 1. Upgrade connection to WebSocket
+	conn, err := Upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Println("Upgrade failed: ", err)
 		return
 	}
-	roomId := roomIdFromUrl[2]
-	// 4. Create new Player struct with generated ID
+	// 2. Extract username from query params (left empty, a reconnecting
+	// player keeps the name they had)
+	username := r.URL.Query().Get("username")
+	// 3. Create new Player struct with generated ID
 	player := &internal.Player{
 		Id:           utils.GenerateID(8),
 		Conn:         conn,
@@ -72,28 +253,163 @@ func HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 		CanvasWidth:  width,
 		CanvasHeight: height,
 		Score:        0,
+		SessionToken: utils.GenerateID(SessionTokenLength),
 	}
-	// 5. Call AddPlayer to join room
-	if err := AddPlayer(roomId, player); err != nil {
-		log.Println("Error adding player", err)
+	// 4. Advertise capabilities first, before AddPlayer sends welcome_msg
+	if err := player.SafeWriteJSON(serverInfoMessage(roomId)); err != nil {
+		log.Printf("[HandleWebSocket] Failed to send server_info to %s: %v", username, err)
 		conn.Close()
 		return
 	}
+	// 5. Reclaim a dropped seat if the client has a session, else call
+	// AddPlayer to join room
+	reconnected := false
+	if session := r.URL.Query().Get("session"); session != "" {
+		if restored, ok := ReconnectPlayer(roomId, player, session); ok {
+			player, reconnected = restored, true
+		}
+	}
+	if !reconnected {
+		if player.Username == "" {
+			player.Username = "Anonymous"
+		}
+		if err := AddPlayer(roomId, player, roomConfig); err != nil {
+			log.Println("Error adding player", err)
+			conn.Close()
+			return
+		}
+	}
 	// 6. Start handleMessages goroutine
 	go handleMessages(player)
 	// 7. Handle connection errors gracefully
 }
 
+// clientMessageTypes lists every message type handleMessages accepts
+var clientMessageTypes = []string{
+	"player_ready", "word_selection", "guess_message", "chat_message", "pixel_draw", "clear_canvas",
+	"background_change", "undo", "redo", "mute_player", "unmute_player", "kick_player", "start_game",
+	"request_stats", "random_word", "vote_skip", "refresh_words",
+}
+
+// clientCanvasSize reads the client's canvas size from the w and h query
+// params, using the defaults for any that are missing. A size that is given
+// but isn't a positive integer is an error, since pixels scaled against it
+// would land in the wrong place.
+func clientCanvasSize(query url.Values) (width, height int, err error) {
+	width, height = DefaultClientCanvasWidth, DefaultClientCanvasHeight
+	for _, dim := range []struct {
+		name string
+		dst  *int
+	}{{"w", &width}, {"h", &height}} {
+		raw := query.Get(dim.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			return width, height, fmt.Errorf("invalid canvas size %s=%q: must be a positive integer", dim.name, raw)
+		}
+		*dim.dst = v
+	}
+	return width, height, nil
+}
+
+// parseRoomConfig reads a room creator's optional overrides from the
+// connection's query: rounds or turns_per_player, draw_time and wait_time
+// (seconds), max_players, lang (word list language), word_mix (difficulties
+// of the drawer's choices)
+func parseRoomConfig(query url.Values) (internal.RoomConfig, error) {
+	var cfg internal.RoomConfig
+	cfg.Language = utils.NormalizeLanguage(query.Get("lang"))
+	cfg.WordMix = internal.WordMix(strings.ToLower(strings.TrimSpace(query.Get("word_mix"))))
+	fields := []struct {
+		name string
+		set  func(int)
+	}{
+		{"rounds", func(v int) { cfg.MaxRounds = v }},
+		{"turns_per_player", func(v int) { cfg.TurnsPerPlayer = v }},
+		{"draw_time", func(v int) { cfg.DrawTime = time.Duration(v) * time.Second }},
+		{"wait_time", func(v int) { cfg.WaitTime = time.Duration(v) * time.Second }},
+		{"max_players", func(v int) { cfg.MaxPlayers = v }},
+	}
+	for _, f := range fields {
+		raw := query.Get(f.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil || v <= 0 {
+			return cfg, fmt.Errorf("invalid %s %q", f.name, raw)
+		}
+		f.set(v)
+	}
+	return cfg, cfg.Validate()
+}
+
+// roomConfigData reports the game settings room plays with, or the server
+// defaults for a nil room. Caller must hold room.Mu.
+func roomConfigData(room *internal.Room) internal.RoomConfigData {
+	cfg := GetGameConfig()
+	data := internal.RoomConfigData{
+		MaxRounds:  internal.MaxRounds,
+		DrawTimeMs: internal.DrawingPhaseDuration.Milliseconds(),
+		MaxPlayers: cfg.MaxPlayersPerRoom,
+		MinPlayers: cfg.MinPlayersToStart,
+		Language:   utils.DefaultLanguage,
+		WordMix:    internal.WordMixBalanced,
+	}
+	if room != nil {
+		data.MaxRounds = room.MaxRounds
+		if room.Config.WordMix != "" {
+			data.WordMix = room.Config.WordMix
+		}
+		data.TurnsPerPlayer = room.Config.TurnsPerPlayer
+		data.Language = roomLanguage(room)
+		data.DrawTimeMs = drawingPhaseDuration(room).Milliseconds()
+		if room.Config.MaxPlayers > 0 {
+			data.MaxPlayers = room.Config.MaxPlayers
+		}
+	}
+	return data
+}
+
+// serverInfoMessage builds the server_info handshake for a connection to
+// roomId, using the room's settings if it already exists
+func serverInfoMessage(roomId string) internal.Message[internal.ServerInfoData] {
+	RoomsMu.RLock()
+	room := Rooms[roomId]
+	RoomsMu.RUnlock()
+	config := roomConfigData(nil)
+	if room != nil {
+		room.Mu.RLock()
+		config = roomConfigData(room)
+		room.Mu.RUnlock()
+	}
+
+	return internal.Message[internal.ServerInfoData]{
+		Type: "server_info",
+		Data: internal.ServerInfoData{
+			SchemaVersion: internal.ProtocolVersion,
+			MessageTypes:  slices.Clone(clientMessageTypes),
+			CanvasWidth:   internal.CanvasWidth,
+			CanvasHeight:  internal.CanvasHeight,
+			RoomConfig:    config,
+		},
+	}
+}
+
 // handleMessages processes incoming WebSocket messages for a player
 func handleMessages(player *internal.Player) {
 	// TODO:
 	// 1. Set up defer for cleanup (close connection, remove player)
 	defer func() {
 		player.Conn.Close()
-		removePlayer(player)
+		disconnectPlayer(player)
 	}()
 	log.Printf("Started message handler for player: %s in room: %s", player.Username, player.Room.Id)
 
+	var lastUnknownReply time.Time
+
 	// 2. Start infinite loop to read messages
 	for {
 		_, rawMessage, err := player.Conn.ReadMessage()
@@ -129,6 +445,12 @@ func handleMessages(player *internal.Player) {
 				continue
 			}
 			HandleWordSelection(player, wordSelected)
+			// - "random_word" -> HandleRandomWordSelection
+		case "random_word":
+			HandleRandomWordSelection(player)
+			// - "refresh_words" -> HandleRefreshWords
+		case "refresh_words":
+			HandleRefreshWords(player)
 			// - "guess" -> HandleGuessEnhanced
 		case "guess_message":
 			var wordSelected string
@@ -137,15 +459,62 @@ func handleMessages(player *internal.Player) {
 				continue
 			}
 			HandleGuessEnhanced(player, wordSelected)
+			// - "chat_message" -> HandleChatMessage (never treated as a guess)
+		case "chat_message":
+			var text string
+			if err := json.Unmarshal(baseMsg.Data, &text); err != nil {
+				log.Println("Error parsing data, wrong json", err)
+				continue
+			}
+			HandleChatMessage(player, text)
 			// - "pixel_draw" -> HandlePixelDrawEnhance
 		case "pixel_draw":
 			HandlePixelDrawEnhanced(player, baseMsg.Data)
 			// - "clear_canvas" -> ClearCanvas
 		case "clear_canvas":
 			ClearCanvas(player.Room, player)
-			// - "start_game" -> StartGame (host only)
+			// - "background_change" -> HandleBackgroundChange
+		case "background_change":
+			HandleBackgroundChange(player, baseMsg.Data)
+			// - "undo" / "redo" -> HandleUndo / HandleRedo
+		case "undo":
+			HandleUndo(player)
+		case "redo":
+			HandleRedo(player)
+			// - "mute_player" / "unmute_player" -> HandleMutePlayer (host only)
+		case "mute_player", "unmute_player":
+			var targetId string
+			if err := json.Unmarshal(baseMsg.Data, &targetId); err != nil {
+				log.Println("Error parsing data, wrong json", err)
+				continue
+			}
+			HandleMutePlayer(player, targetId, baseMsg.Type == "mute_player")
+			// - "kick_player" -> HandleKickPlayer (host only)
+		case "kick_player":
+			var targetId string
+			if err := json.Unmarshal(baseMsg.Data, &targetId); err != nil {
+				log.Println("Error parsing data, wrong json", err)
+				continue
+			}
+			HandleKickPlayer(player, targetId)
+			// - "start_game" -> HandleStartGame (host only)
 		case "start_game":
-			go StartGame(player.Room)
+			go HandleStartGame(player)
+			// - "vote_skip" -> HandleVoteSkip
+		case "vote_skip":
+			HandleVoteSkip(player)
+			// - "request_stats" -> HandleRequestStats (private reply)
+		case "request_stats":
+			HandleRequestStats(player)
+			// - anything else -> "unknown_message_type" error (rate limited)
+		default:
+			if time.Since(lastUnknownReply) < UnknownMessageReplyInterval {
+				continue
+			}
+			lastUnknownReply = time.Now()
+			log.Printf("Unknown message type %q from player: %s", baseMsg.Type, player.Username)
+			SendErrorToPlayer(player, "unknown_message_type",
+				fmt.Sprintf("unknown message type %q", baseMsg.Type))
 		}
 	}
 }
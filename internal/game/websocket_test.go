@@ -0,0 +1,308 @@
+package game
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/gorilla/websocket"
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// newTestWSServer serves HandleWebSocket on the same route as the real server
+func newTestWSServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	r := mux.NewRouter()
+	r.HandleFunc("/ws/{roomId}", HandleWebSocket)
+	srv := httptest.NewServer(r)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func wsURL(srv *httptest.Server, path string) string {
+	return "ws" + strings.TrimPrefix(srv.URL, "http") + path
+}
+
+func TestHandleWebSocketJoinsRoomFromRouteVar(t *testing.T) {
+	srv := newTestWSServer(t)
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		delete(Rooms, "route-room")
+		RoomsMu.Unlock()
+	})
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(srv, "/ws/route-room?username=alice&w=350&h=200"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg internal.Message[map[string]any]
+	for _, want := range []string{"server_info", "welcome_msg"} {
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("failed to read %s: %v", want, err)
+		}
+		if msg.Type != want {
+			t.Fatalf("expected %s, got %q", want, msg.Type)
+		}
+	}
+
+	RoomsMu.RLock()
+	_, exists := Rooms["route-room"]
+	RoomsMu.RUnlock()
+	if !exists {
+		t.Error("expected room id to be taken from the route variable")
+	}
+}
+
+func TestClientCanvasSizeRejectsBadDimensions(t *testing.T) {
+	cases := []struct {
+		query        string
+		wantW, wantH int
+		valid        bool
+	}{
+		{"w=350&h=200", 350, 200, true},
+		{"h=120", DefaultClientCanvasWidth, 120, true},
+		{"", DefaultClientCanvasWidth, DefaultClientCanvasHeight, true},
+		{"w=0&h=200", 0, 0, false},
+		{"w=350&h=0", 0, 0, false},
+		{"w=-5&h=120", 0, 0, false},
+		{"w=abc", 0, 0, false},
+	}
+	for _, c := range cases {
+		query, _ := url.ParseQuery(c.query)
+		w, h, err := clientCanvasSize(query)
+		if (err == nil) != c.valid {
+			t.Errorf("clientCanvasSize(%q) error = %v, want valid=%v", c.query, err, c.valid)
+			continue
+		}
+		if c.valid && (w != c.wantW || h != c.wantH) {
+			t.Errorf("clientCanvasSize(%q) = %dx%d, want %dx%d", c.query, w, h, c.wantW, c.wantH)
+		}
+	}
+}
+
+func TestHandleWebSocketRejectsZeroCanvasSize(t *testing.T) {
+	srv := newTestWSServer(t)
+
+	_, resp, err := websocket.DefaultDialer.Dial(wsURL(srv, "/ws/zero-canvas?username=alice&w=0&h=200"), nil)
+	if err == nil {
+		t.Fatal("expected the handshake to fail for w=0")
+	}
+	if resp == nil || resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("expected 400 for w=0, got %v", resp)
+	}
+
+	RoomsMu.RLock()
+	_, exists := Rooms["zero-canvas"]
+	RoomsMu.RUnlock()
+	if exists {
+		t.Error("expected no room to be created for a rejected connection")
+	}
+}
+
+func TestServerInfoIsFirstFrame(t *testing.T) {
+	srv := newTestWSServer(t)
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		delete(Rooms, "info-room")
+		RoomsMu.Unlock()
+	})
+
+	conn, _, err := websocket.DefaultDialer.Dial(wsURL(srv, "/ws/info-room?username=alice&w=350&h=200"), nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	var msg internal.Message[internal.ServerInfoData]
+	if err := conn.ReadJSON(&msg); err != nil {
+		t.Fatalf("failed to read first frame: %v", err)
+	}
+	if msg.Type != "server_info" {
+		t.Fatalf("expected server_info first, got %q", msg.Type)
+	}
+
+	info := msg.Data
+	if info.SchemaVersion != internal.ProtocolVersion {
+		t.Errorf("expected schema version %d, got %d", internal.ProtocolVersion, info.SchemaVersion)
+	}
+	if info.CanvasWidth != internal.CanvasWidth || info.CanvasHeight != internal.CanvasHeight {
+		t.Errorf("expected a %dx%d grid, got %dx%d",
+			internal.CanvasWidth, internal.CanvasHeight, info.CanvasWidth, info.CanvasHeight)
+	}
+	if !slices.Contains(info.MessageTypes, "guess_message") || !slices.Contains(info.MessageTypes, "pixel_draw") {
+		t.Errorf("expected supported message types to be listed, got %v", info.MessageTypes)
+	}
+	if info.RoomConfig.MaxRounds != internal.MaxRounds || info.RoomConfig.MaxPlayers != GetGameConfig().MaxPlayersPerRoom {
+		t.Errorf("unexpected room config %+v", info.RoomConfig)
+	}
+}
+
+func TestHandleWebSocketRejectsMissingRoomId(t *testing.T) {
+	// Called without mux, so no roomId route variable is present
+	req := httptest.NewRequest(http.MethodGet, "/ws/", nil)
+	rec := httptest.NewRecorder()
+
+	HandleWebSocket(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for missing room id, got %d", rec.Code)
+	}
+	if !strings.Contains(rec.Body.String(), "missing room id") {
+		t.Errorf("expected a clear error message, got %q", rec.Body.String())
+	}
+}
+
+func TestHandleWebSocketRejectsOutOfRangeRoomConfig(t *testing.T) {
+	srv := newTestWSServer(t)
+
+	for _, query := range []string{"rounds=0", "rounds=11", "draw_time=29", "draw_time=181", "max_players=13", "max_players=1", "rounds=five"} {
+		resp, err := http.Get(srv.URL + "/ws/config-room?w=350&h=200&" + query)
+		if err != nil {
+			t.Fatalf("GET with %s failed: %v", query, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("expected 400 for %s, got %d", query, resp.StatusCode)
+		}
+	}
+	RoomsMu.RLock()
+	_, created := Rooms["config-room"]
+	RoomsMu.RUnlock()
+	if created {
+		t.Error("expected no room to be created from a rejected config")
+	}
+}
+
+func TestHandleWebSocketCreatesRoomWithConfig(t *testing.T) {
+	srv := newTestWSServer(t)
+	t.Cleanup(func() {
+		RoomsMu.Lock()
+		delete(Rooms, "configured-room")
+		RoomsMu.Unlock()
+	})
+
+	url := wsURL(srv, "/ws/configured-room?username=alice&w=350&h=200&rounds=5&draw_time=60&max_players=4")
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+	var welcome internal.Message[json.RawMessage]
+	for welcome.Type != "welcome_msg" {
+		if err := conn.ReadJSON(&welcome); err != nil {
+			t.Fatalf("failed to read welcome_msg: %v", err)
+		}
+	}
+
+	RoomsMu.RLock()
+	room := Rooms["configured-room"]
+	RoomsMu.RUnlock()
+	room.Mu.RLock()
+	defer room.Mu.RUnlock()
+	want := internal.RoomConfig{MaxRounds: 5, DrawTime: 60 * time.Second, MaxPlayers: 4}
+	if room.Config != want || room.MaxRounds != 5 {
+		t.Errorf("expected config %+v with 5 rounds, got %+v (max rounds %d)", want, room.Config, room.MaxRounds)
+	}
+	if got := roomConfigData(room); got.DrawTimeMs != 60000 || got.MaxPlayers != 4 {
+		t.Errorf("expected advertised config to follow the room, got %+v", got)
+	}
+}
+
+func TestHandleWebSocketMalformedPathNotRouted(t *testing.T) {
+	srv := newTestWSServer(t)
+
+	for _, path := range []string{"/ws", "/ws/", "/ws/a/b"} {
+		resp, err := http.Get(srv.URL + path)
+		if err != nil {
+			t.Fatalf("GET %s failed: %v", path, err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode == http.StatusSwitchingProtocols || resp.StatusCode == http.StatusOK {
+			t.Errorf("expected malformed path %s to be rejected, got %d", path, resp.StatusCode)
+		}
+	}
+}
+
+func TestUnknownMessageTypeRepliesWithError(t *testing.T) {
+	room := newTestRoom(t, "unknown-type")
+	player, conn := addConnectedPlayer(room, "alice")
+	done := make(chan struct{})
+	go func() {
+		handleMessages(player)
+		close(done)
+	}()
+
+	conn.send("no_such_type", nil)
+	msg, ok := conn.waitFor("error", time.Second)
+	if !ok {
+		t.Fatal("expected an error reply for an unknown message type")
+	}
+	var data internal.ErrorData
+	if err := json.Unmarshal(msg.Data, &data); err != nil {
+		t.Fatalf("failed to decode error: %v", err)
+	}
+	if data.Code != "unknown_message_type" || !strings.Contains(data.Message, "no_such_type") {
+		t.Errorf("expected unknown_message_type naming the type, got %+v", data)
+	}
+
+	// A burst of unknown frames is answered at most once per interval
+	for range 5 {
+		conn.send("no_such_type", nil)
+	}
+	conn.send("player_ready", false) // known type, processed after the burst
+	time.Sleep(50 * time.Millisecond)
+	conn.Close()
+	<-done
+
+	if got := len(conn.messagesOfType("error")); got != 1 {
+		t.Errorf("expected rate limiting to suppress repeat replies, got %d errors", got)
+	}
+}
+
+func TestGameConfigSwapIsRaceFree(t *testing.T) {
+	prev := GetGameConfig()
+	t.Cleanup(func() { SetGameConfig(prev) })
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				cfg := roomConfigData(nil)
+				// Both limits come from the same update
+				if cfg.MaxPlayers != cfg.MinPlayers*4 {
+					t.Errorf("read a torn config: %+v", cfg)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 200; i++ {
+		SetGameConfig(GameConfig{MaxPlayersPerRoom: i * 4, MinPlayersToStart: i})
+	}
+	close(stop)
+	wg.Wait()
+
+	if got := GetGameConfig(); got.MaxPlayersPerRoom != 800 || got.MinPlayersToStart != 200 {
+		t.Errorf("expected the last update to win, got %+v", got)
+	}
+}
@@ -5,10 +5,41 @@ type Message[T any] struct {
 	Data T      `json:"data"`
 }
 
+// ErrorData is sent privately to a player whose request was rejected
+type ErrorData struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// ProtocolVersion is bumped whenever message payloads change incompatibly
+const ProtocolVersion = 1
+
+// RoomConfigData describes a room's game settings for clients
+type RoomConfigData struct {
+	MaxRounds      int     `json:"max_rounds"`
+	TurnsPerPlayer int     `json:"turns_per_player,omitempty"` // set when the room counts turns, not rounds
+	DrawTimeMs     int64   `json:"draw_time_ms"`
+	MaxPlayers     int     `json:"max_players"`
+	MinPlayers     int     `json:"min_players"`
+	Language       string  `json:"language"` // word list language the room draws from
+	WordMix        WordMix `json:"word_mix"` // difficulties of the drawer's word choices
+}
+
+// ServerInfoData is the first frame on every connection, so clients can
+// feature-detect instead of hardcoding server behavior
+type ServerInfoData struct {
+	SchemaVersion int            `json:"schema_version"`
+	MessageTypes  []string       `json:"message_types"` // accepted from clients
+	CanvasWidth   int            `json:"canvas_width"`
+	CanvasHeight  int            `json:"canvas_height"`
+	RoomConfig    RoomConfigData `json:"room_config"`
+}
+
 type TimerUpdateData struct {
 	TimeRemaining int64     `json:"time_remaining_ms"`
 	Phase         GamePhase `json:"phase"`
 	IsActive      bool      `json:"is_active"`
+	IsPaused      bool      `json:"is_paused,omitempty"`
 }
 
 type PlayerJoinedData struct {
@@ -25,23 +56,42 @@ type PlayerLeftData struct {
 }
 
 type WordSelectionData struct {
-	Choices   []string `json:"choices"`
-	RoomId    string   `json:"room_id"`
-	Message   string   `json:"message"`
-	TimeLimit int      `json:"time_limit"`
+	Choices   []Word `json:"choices"` // includes difficulty and base points
+	RoomId    string `json:"room_id"`
+	Message   string `json:"message"`
+	TimeLimit int64  `json:"time_limit_ms"`
+	// The drawer may still swap these choices for new ones (refresh_words)
+	CanRefresh bool `json:"can_refresh"`
 }
 
 type MaskedWordData struct {
-	RoomID       string `json:"room_id"`
-	MaskedWord   string `json:"masked_word"`
+	RoomID        string         `json:"room_id"`
+	MaskedWord    string         `json:"masked_word"`
+	Difficulty    WordDifficulty `json:"difficulty,omitempty"`     // omitted when difficulty is hidden
+	Points        int            `json:"points,omitempty"`         // base points for the word
+	GuessProgress *GuessProgress `json:"guess_progress,omitempty"` // unless disabled by config
 }
 
 type FinalResults struct {
-    Leaderboard   []GameResultData `json:"leaderboard"`   // sorted by score
-    MVP           *GameResultData  `json:"mvp,omitempty"` // highest scorer or other criteria
-    FastestGuess  *GameResultData  `json:"fastest_guess,omitempty"`
-    MostAccurate  *GameResultData  `json:"most_accurate,omitempty"`
-    RoundsPlayed  int              `json:"rounds_played"`
-    TotalPlayers  int              `json:"total_players"`
+	Leaderboard        []GameResultData `json:"leaderboard"`   // sorted by score
+	MVP                *GameResultData  `json:"mvp,omitempty"` // highest scorer or other criteria
+	FastestGuess       *GameResultData  `json:"fastest_guess,omitempty"`
+	MostAccurate       *GameResultData  `json:"most_accurate,omitempty"`
+	RoundsPlayed       int              `json:"rounds_played"`
+	TotalPlayers       int              `json:"total_players"`
+	GameDurationMs     int64            `json:"game_duration_ms"`
+	AvgRoundDurationMs int64            `json:"avg_round_duration_ms"`
+	WordCredits        []WordCredit     `json:"word_credits"` // in the order the words were drawn
+	Aborted            bool             `json:"aborted"`      // game cut short; no awards
+	AbortReason        string           `json:"abort_reason,omitempty"`
+}
+
+// WordCredit records who drew a completed word and who guessed it
+type WordCredit struct {
+	RoundNumber     int           `json:"round_number"`
+	Word            string        `json:"word"`
+	DrawerID        string        `json:"drawer_id"`
+	DrawerUsername  string        `json:"drawer_username"`
+	CorrectGuessers []PlayerGuess `json:"correct_guessers"`
 }
 
@@ -15,6 +15,33 @@ const (
 	MaxRounds              = 3
 )
 
+// Bounds a room creator may pick within (see RoomConfig)
+const (
+	MinRoomRounds   = 1
+	MaxRoomRounds   = 10
+	MinRoomTurns    = 1
+	MaxRoomTurns    = 10
+	MinRoomDrawTime = 30 * time.Second
+	MaxRoomDrawTime = 180 * time.Second
+	MinRoomWaitTime = 5 * time.Second
+	MaxRoomWaitTime = 60 * time.Second
+	MinRoomPlayers  = 2
+	MaxRoomPlayers  = 12
+	MaxLanguageLen  = 16
+)
+
+// RoomConfig holds the settings a room's creator chose. Zero fields use the
+// server defaults. Game length is set by either MaxRounds or TurnsPerPlayer.
+type RoomConfig struct {
+	MaxRounds      int           `json:"max_rounds,omitempty"`
+	TurnsPerPlayer int           `json:"turns_per_player,omitempty"` // times each player draws
+	DrawTime       time.Duration `json:"draw_time,omitempty"`
+	WaitTime       time.Duration `json:"wait_time,omitempty"`
+	MaxPlayers     int           `json:"max_players,omitempty"`
+	Language       string        `json:"language,omitempty"` // word list language code, e.g. "es"
+	WordMix        WordMix       `json:"word_mix,omitempty"` // difficulties of the drawer's choices
+}
+
 type GamePhase string
 
 const (
@@ -33,11 +60,60 @@ const (
 	DifficultyHard   WordDifficulty = "hard"
 )
 
+// BasePoints returns the points a correct guess is worth before speed and
+// position multipliers are applied
+func (d WordDifficulty) BasePoints() int {
+	switch d {
+	case DifficultyEasy:
+		return 100
+	case DifficultyMedium:
+		return 150
+	case DifficultyHard:
+		return 200
+	}
+	return 0
+}
+
+// WordMix names the difficulties of the three words a drawer is offered
+type WordMix string
+
+const (
+	WordMixBalanced WordMix = "balanced" // one easy, one medium, one hard (the default)
+	WordMixMedium   WordMix = "medium"   // three medium
+	WordMixEasy     WordMix = "easy"     // two easy, one hard
+	WordMixHard     WordMix = "hard"     // one easy, two hard
+)
+
+// Difficulties lists the difficulty of each offered word, or nil for an
+// unknown mix. The empty mix is WordMixBalanced.
+func (m WordMix) Difficulties() []WordDifficulty {
+	switch m {
+	case "", WordMixBalanced:
+		return []WordDifficulty{DifficultyEasy, DifficultyMedium, DifficultyHard}
+	case WordMixMedium:
+		return []WordDifficulty{DifficultyMedium, DifficultyMedium, DifficultyMedium}
+	case WordMixEasy:
+		return []WordDifficulty{DifficultyEasy, DifficultyEasy, DifficultyHard}
+	case WordMixHard:
+		return []WordDifficulty{DifficultyEasy, DifficultyHard, DifficultyHard}
+	}
+	return nil
+}
+
 type Word struct {
 	Word      string         `json:"word"`
 	Count     int            `json:"count"`
 	Difficult WordDifficulty `json:"difficulty"`
 	Points    int            `json:"points"`
+	Emoji     string         `json:"emoji,omitempty"` // optional hint icon from the word list
+}
+
+// WordProvider offers a drawer's word choices in a room played under cfg,
+// avoiding the (lowercased) words in used where it can. Rooms draw from the
+// random word lists unless one is injected, e.g. by tests that need to know
+// the word.
+type WordProvider interface {
+	GenerateChoices(cfg RoomConfig, used map[string]bool) []Word
 }
 
 type GameTimer struct {
@@ -45,8 +121,20 @@ type GameTimer struct {
 	Duration      time.Duration `json:"duration"`
 	TimeRemaining time.Duration `json:"time_remaining"`
 	IsActive      bool          `json:"is_active"`
+	IsPaused      bool          `json:"is_paused"`
+	PausedAt      time.Time     `json:"-"`     // when IsPaused was set
+	Phase         GamePhase     `json:"phase"` // phase this timer is timing
 	Context       context.Context
 	Cancel        context.CancelFunc
+	OnExpire      func() `json:"-"` // kept so a paused timer can be resumed
+}
+
+// DisconnectedPlayer holds a dropped player's seat until they reconnect or
+// Purge fires and removes them for good
+type DisconnectedPlayer struct {
+	Player *Player
+	Since  time.Time
+	Purge  *time.Timer
 }
 
 type PlayerGuess struct {
@@ -54,41 +142,66 @@ type PlayerGuess struct {
 	Username  string `json:"username"`
 	GuessTime int    `json:"guess_time"`
 	IsCorrect bool   `json:"is_correct"`
+	Points    int    `json:"points"` // awarded for a correct guess
 }
 
 type RoundStats struct {
-	RoundNumber    int           `json:"round_number"`
-	DrawerId       string        `json:"drawer_id"`
-	Word           string        `json:"word"`
+	RoundNumber     int           `json:"round_number"`
+	DrawerId        string        `json:"drawer_id"`
+	DrawerUsername  string        `json:"drawer_username"`
+	Word            string        `json:"word"`
 	CorrectGuessers []PlayerGuess `json:"correct_guesses"`
-	TotalGuesses   int           `json:"total_guesses"`
-	StartTime      time.Time     `json:"start_time"`
-	EndTime        time.Time     `json:"end_time"`
+	TotalGuesses    int           `json:"total_guesses"`
+	DrawerPoints    int           `json:"drawer_points"` // awarded to the drawer when the turn ended
+	StartTime       time.Time     `json:"start_time"`
+	EndTime         time.Time     `json:"end_time"`
 }
 
-type Response struct {
+// Response is the JSON envelope for HTTP API replies
+type Response[T any] struct {
 	StatusCode    int   `json:"status_code"`
 	RespStartTime int64 `json:"resp_time_start_ms"`
 	RespEndTime   int64 `json:"resp_time_end_ms"`
 	NetRespTime   int64 `json:"net_resp_time_ms"`
-	Data          any   `json:"data"`
+	Data          T     `json:"data"`
 }
 
 type Room struct {
 	Id      string
 	Players map[string]*Player
+	HostId  string `json:"host_id"` // first player to join; may moderate the room
 
 	// Game State
-	Phase        GamePhase `json:"phase"`
-	Current      *Player   `json:"current_drawer"`
-	CurrentIndex int       `json:"current_index"`
-	Word         string    `json:"word"`
-	WordChoices  []string  `json:"word_choices,omitempty"` //Only available for current drawer
+	Phase          GamePhase      `json:"phase"`
+	PhaseChangedAt time.Time      `json:"-"` // last phase transition, watched for stuck rooms
+	Current        *Player        `json:"current_drawer"`
+	CurrentIndex   int            `json:"current_index"`
+	Word           string         `json:"word"`
+	WordEmoji      string         `json:"-"`                      // optional hint for the chosen word
+	WordDifficulty WordDifficulty `json:"-"`                      // difficulty of the chosen word
+	WordChoices    []Word         `json:"word_choices,omitempty"` //Only available for current drawer
+	// Set once the drawer has re-rolled this turn's word choices
+	WordsRefreshed bool `json:"-"`
+	// Overrides the package word provider for this room (tests only)
+	Words WordProvider `json:"-"`
+	// Words already drawn this game, lowercased, so they aren't offered again
+	UsedWords map[string]bool `json:"-"`
+	// Latch so each turn enters the drawing phase exactly once
+	DrawingStarted bool `json:"-"`
+	// Set once this turn's RoundStats entry is written, or if the turn was skipped
+	RoundRecorded bool `json:"-"`
+	// Drawing time warnings already sent this turn, by threshold
+	TimeWarningsSent map[time.Duration]bool `json:"-"`
 
 	// Round Management
 	RoundNumber int          `json:"round_number"`
-	MaxRounds   int          `json:"max_rounds"`
+	MaxRounds   int          `json:"max_rounds"` // from Config, or the MaxRounds default
+	TurnsPlayed int          `json:"-"`          // turns finished this game, checked against MaxGameTurns
+	Config      RoomConfig   `json:"config"`     // creator's overrides
 	RoundStats  []RoundStats `json:"round_stats"`
+	StartedAt   time.Time    `json:"started_at"` // when the current game started
+	// Results of the last finished game, shown to players joining before the lobby reset
+	LastResults *FinalResults `json:"-"`
 
 	// Timer
 	Timer *GameTimer `json:"timer"`
@@ -96,16 +209,48 @@ type Room struct {
 	// Player Order and Management
 	PlayerOrder  []string        `json:"player_order"`
 	PlayersReady map[string]bool `json:"players_ready"`
+	// Players who left mid-game, kept so their points still show in final results
+	DepartedPlayers map[string]*Player `json:"-"`
+	// Mid-game players whose connection dropped, by session token, until they
+	// reconnect or their grace period ends
+	Disconnected map[string]*DisconnectedPlayer `json:"-"`
 
 	// Guessing State
 	CorrectGuessers []PlayerGuess `json:"correct_guessers"`
-	HasGameStarted  bool          `json:"has_game_started"`
+	IgnoreArticles  bool          `json:"ignore_articles"` // lenient matching of "a"/"an"/"the"
+	// Guessers already credited for this turn's word, by id. Backs up
+	// HasGuessed so no guesser is ever scored twice for one word.
+	CreditedGuessers map[string]bool `json:"-"`
+
+	HasGameStarted bool `json:"has_game_started"`
 
 	// Drawing Canvas State
-	CanvasState []PixelMessage `json:"canvas_state,omitempty"`
+	CanvasState      []PixelMessage   `json:"canvas_state,omitempty"`
+	CanvasEmptySince time.Time        `json:"-"` // start of the current blank-canvas stretch while drawing
+	HasDrawn         bool             `json:"-"` // a pixel_draw arrived since the drawing phase started
+	CanvasBackground CanvasBackground `json:"canvas_background"`
+
+	// Guessers who voted to skip the current drawing (nil once it's skipped)
+	SkipVotes map[string]bool `json:"-"`
+
+	// Undo/Redo history for the current round (canvas snapshots)
+	UndoStack [][]PixelMessage `json:"-"`
+	RedoStack [][]PixelMessage `json:"-"`
 
 	// Concurrency control
 	Mu sync.RWMutex `json:"-"`
+	// Set while a coalesced game state broadcast is scheduled
+	StateBroadcastPending bool `json:"-"`
+	// Single-pixel places applied but not yet broadcast, and who drew them
+	PendingPixels     []PixelMessage `json:"-"`
+	PendingPixelsFrom *Player        `json:"-"`
+	// Queued broadcasts, sent in order by the room's broadcast worker
+	broadcasts    chan func()
+	broadcastOnce sync.Once
+
+	// Integrations: POSTed key game events when set. Server-configured
+	// (ROOM_WEBHOOK_URL), never taken from clients
+	WebhookURL string `json:"-"`
 
 	// Context for cleanup
 	Context context.Context    `json:"-"`
@@ -113,14 +258,23 @@ type Room struct {
 }
 
 type GameStateData struct {
-	Phase           GamePhase     `json:"phase"`
-	RoundNumber     int           `json:"round_number"`
-	MaxRounds       int           `json:"max_rounds"`
-	CurrentDrawer   *Player       `json:"current_drawer"`
-	TimeRemaining   int64         `json:"time_remaining"`
-	Players         []*Player     `json:"players"`
-	CorrectGuessers []PlayerGuess `json:"correct_guessers"`
-	Word            string        `json:"word,omitempty"`
+	Phase            GamePhase        `json:"phase"`
+	RoundNumber      int              `json:"round_number"`
+	MaxRounds        int              `json:"max_rounds"`
+	CurrentDrawer    *Player          `json:"current_drawer"`
+	TimeRemaining    int64            `json:"time_remaining_ms"`
+	Players          []PlayerSnapshot `json:"players"`
+	CorrectGuessers  []PlayerGuess    `json:"correct_guessers"`
+	Word             string           `json:"word,omitempty"`
+	WordDifficulty   WordDifficulty   `json:"word_difficulty,omitempty"` // drawer only, unless shown by config
+	CanvasBackground CanvasBackground `json:"canvas_background"`
+	GuessProgress    *GuessProgress   `json:"guess_progress,omitempty"` // while drawing, unless disabled by config
+}
+
+// GuessProgress is how many of the turn's guessers have got the word so far
+type GuessProgress struct {
+	Guessed int `json:"guessed"`
+	Total   int `json:"total"`
 }
 
 type GameResultData struct {
@@ -130,6 +284,7 @@ type GameResultData struct {
 	Score       int    `json:"score"`
 	Position    int    `json:"position"`
 	TimeToGuess int64  `json:"time_to_guess_ms"`
+	HasLeft     bool   `json:"has_left,omitempty"`
 }
 
 type RoundEndData struct {
@@ -137,8 +292,18 @@ type RoundEndData struct {
 	DrawerID        string        `json:"drawer_id"`
 	DrawerUsername  string        `json:"drawer_username"`
 	CorrectGuessers []PlayerGuess `json:"correct_guessers"`
+	DrawerPoints    int           `json:"drawer_points"`
 	NextDrawer      *Player       `json:"next_drawer"`
 	FinalScores     []*Player     `json:"final_scores"`
 	RoundNumber     int           `json:"round_number"`
 	IsGameEnded     bool          `json:"is_game_ended"`
+	Scoreboard      []RoundScore  `json:"scoreboard,omitempty"` // unless disabled by config
+}
+
+// RoundScore is one player's line in the end-of-round scoreboard
+type RoundScore struct {
+	PlayerID    string `json:"player_id"`
+	Username    string `json:"username"`
+	RoundPoints int    `json:"round_points"`
+	TotalScore  int    `json:"total_score"`
 }
@@ -1,18 +1,40 @@
 package internal
 
 import (
+	"errors"
+	"net"
 	"sync"
 	"time"
-
-	"github.com/gorilla/websocket"
 )
 
+// WriteTimeout bounds each write to a player's connection, so a stalled
+// client fails its writes instead of holding up the room's broadcasts
+// (0 disables)
+var WriteTimeout = 10 * time.Second
+
+// Conn is the subset of *websocket.Conn the game relies on, so tests and load
+// tools can inject in-memory connections. *websocket.Conn satisfies it.
+type Conn interface {
+	WriteJSON(v any) error
+	ReadMessage() (messageType int, p []byte, err error)
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+	Close() error
+}
+
+// Player is one connection's seat in a room.
+//
+// Locking: every game-state field (score, flags, stats, Username, canvas size)
+// is read and written under Room.Mu. Messages are serialized after Room.Mu is
+// released, so they must carry copies (ToPublicPlayer, CreatePlayerSnapshot)
+// taken under it, never a live *Player. Mu only serializes writes to Conn and
+// guards swapping Conn on reconnect.
 type Player struct {
-	Id       string          `json:"id"`
-	Conn     *websocket.Conn `json:"-"`
-	Room     *Room           `json:"-"` // Avoid circular reference in JSON
-	Username string          `json:"username"`
-	Score    int             `json:"score"`
+	Id       string `json:"id"`
+	Conn     Conn   `json:"-"`
+	Room     *Room  `json:"-"` // Avoid circular reference in JSON
+	Username string `json:"username"`
+	Score    int    `json:"score"`
 
 	// Game state
 	CanvasHeight  int       `json:"canvas_height"`
@@ -21,7 +43,14 @@ type Player struct {
 	HasGuessed    bool      `json:"has_guessed"`
 	LastGuessTime time.Time `json:"last_guess_time"`
 	IsConnected   bool      `json:"is_connected"`
+	IsIdle        bool      `json:"is_idle"` // never readied up in lobby, excluded from readiness
 	JoinedAt      time.Time `json:"joined_at"`
+	UnreadySince  time.Time `json:"-"`        // start of the current unready stretch in lobby
+	IsMuted       bool      `json:"is_muted"` // guesses still score, but aren't shown to others
+	SessionToken  string    `json:"-"`        // lets a dropped player reclaim their seat
+
+	// Dropped, with their seat held for a reconnect (see ReconnectGracePeriod)
+	IsReconnecting bool `json:"is_reconnecting"`
 
 	// DrawingPermissions
 	CanDraw bool `json:"can_draw"`
@@ -31,8 +60,15 @@ type Player struct {
 	CorrectGuesses int `json:"correct_guesses"`
 	TimesDrawn     int `json:"times_drawn"`
 	Mu             sync.RWMutex `json:"-"`
+
+	// Score, CorrectGuesses and TotalGuesses as the current drawing began,
+	// shown to guessers still guessing so changes don't give guessers away
+	TurnStartScore   int `json:"-"`
+	TurnStartCorrect int `json:"-"`
+	TurnStartGuesses int `json:"-"`
 }
 
+// PlayerSnapshot is the lean player view used in broadcast player lists
 type PlayerSnapshot struct {
 	ID             string `json:"id"`
 	Username       string `json:"username"`
@@ -40,12 +76,16 @@ type PlayerSnapshot struct {
 	IsReady        bool   `json:"is_ready"`
 	HasGuessed     bool   `json:"has_guessed"`
 	IsConnected    bool   `json:"is_connected"`
+	IsReconnecting bool   `json:"is_reconnecting"`
+	IsIdle         bool   `json:"is_idle"`
+	IsMuted        bool   `json:"is_muted"`
 	CanDraw        bool   `json:"can_draw"`
 	TotalGuesses   int    `json:"total_guesses"`
 	CorrectGuesses int    `json:"correct_guesses"`
 	TimesDrawn     int    `json:"times_drawn"`
-}
 
+	turnStartScore, turnStartCorrect, turnStartGuesses int // what Redacted reports
+}
 
 func (p *Player) ResetRoundState() {
 	p.HasGuessed = false
@@ -53,6 +93,7 @@ func (p *Player) ResetRoundState() {
 	p.LastGuessTime = time.Time{}
 }
 
+// ToPublicPlayer copies the fields clients may see. Caller must hold Room.Mu.
 func (p *Player) ToPublicPlayer() *Player {
 	return &Player{
 		Id:             p.Id,
@@ -61,6 +102,9 @@ func (p *Player) ToPublicPlayer() *Player {
 		IsReady:        p.IsReady,
 		HasGuessed:     p.HasGuessed,
 		IsConnected:    p.IsConnected,
+		IsReconnecting: p.IsReconnecting,
+		IsIdle:         p.IsIdle,
+		IsMuted:        p.IsMuted,
 		CanDraw:        p.CanDraw,
 		TotalGuesses:   p.TotalGuesses,
 		CorrectGuesses: p.CorrectGuesses,
@@ -69,6 +113,19 @@ func (p *Player) ToPublicPlayer() *Player {
 	}
 }
 
+// ToRedactedPlayer is ToPublicPlayer with in-round state hidden. It is sent to
+// guessers during an active round so they can't infer who has already guessed.
+func (p *Player) ToRedactedPlayer() *Player {
+	public := p.ToPublicPlayer()
+	public.HasGuessed = false
+	public.CanDraw = false
+	public.Score = p.TurnStartScore
+	public.CorrectGuesses = p.TurnStartCorrect
+	public.TotalGuesses = p.TurnStartGuesses
+	return public
+}
+
+// CreatePlayerSnapshot copies p for a player list. Caller must hold Room.Mu.
 func CreatePlayerSnapshot(p *Player) PlayerSnapshot {
 	return PlayerSnapshot{
 		ID:             p.Id,
@@ -77,16 +134,43 @@ func CreatePlayerSnapshot(p *Player) PlayerSnapshot {
 		IsReady:        p.IsReady,
 		HasGuessed:     p.HasGuessed,
 		IsConnected:    p.IsConnected,
+		IsReconnecting: p.IsReconnecting,
+		IsIdle:         p.IsIdle,
+		IsMuted:        p.IsMuted,
 		CanDraw:        p.CanDraw,
 		TotalGuesses:   p.TotalGuesses,
 		CorrectGuesses: p.CorrectGuesses,
 		TimesDrawn:     p.TimesDrawn,
+
+		turnStartScore:   p.TurnStartScore,
+		turnStartCorrect: p.TurnStartCorrect,
+		turnStartGuesses: p.TurnStartGuesses,
 	}
 }
 
+// Redacted hides in-round state, like ToRedactedPlayer does for full players
+func (s PlayerSnapshot) Redacted() PlayerSnapshot {
+	s.HasGuessed = false
+	s.CanDraw = false
+	s.Score = s.turnStartScore
+	s.CorrectGuesses = s.turnStartCorrect
+	s.TotalGuesses = s.turnStartGuesses
+	return s
+}
 
+// SafeWriteJSON writes v to the player's connection within WriteTimeout. A
+// write that times out leaves the connection unusable, so it is closed and
+// the player's reader loop disconnects them.
 func (p *Player) SafeWriteJSON(v any) error {
 	p.Mu.Lock()
 	defer p.Mu.Unlock()
-	return p.Conn.WriteJSON(v)
+	if WriteTimeout > 0 {
+		p.Conn.SetWriteDeadline(time.Now().Add(WriteTimeout))
+	}
+	err := p.Conn.WriteJSON(v)
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		p.Conn.Close()
+	}
+	return err
 }
\ No newline at end of file
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestRedactedVsPublicPlayer(t *testing.T) {
+	p := &Player{
+		Id:             "p1",
+		Username:       "alice",
+		Score:          120,
+		TurnStartScore: 20,
+		HasGuessed:     true,
+		CanDraw:        true,
+		IsConnected:    true,
+		TotalGuesses:   4,
+		CorrectGuesses: 2,
+	}
+	p.TurnStartCorrect = 1
+	p.TurnStartGuesses = 3
+
+	full := p.ToPublicPlayer()
+	redacted := p.ToRedactedPlayer()
+
+	if !full.HasGuessed || !full.CanDraw {
+		t.Errorf("expected full projection to keep in-round state, got %+v", full)
+	}
+	if redacted.HasGuessed || redacted.CanDraw {
+		t.Errorf("expected redacted projection to hide in-round state, got %+v", redacted)
+	}
+	if redacted.Score != 20 || redacted.CorrectGuesses != 1 || redacted.TotalGuesses != 3 {
+		t.Errorf("expected redacted projection to show turn-start score 20, 1 correct of 3 guesses, got %d, %d/%d",
+			redacted.Score, redacted.CorrectGuesses, redacted.TotalGuesses)
+	}
+	if snap := CreatePlayerSnapshot(p).Redacted(); snap.Score != 20 || snap.CorrectGuesses != 1 ||
+		snap.TotalGuesses != 3 || snap.HasGuessed {
+		t.Errorf("expected redacted snapshot to match, got %+v", snap)
+	}
+
+	// Everything else must match the full projection
+	redacted.HasGuessed, redacted.CanDraw = full.HasGuessed, full.CanDraw
+	redacted.Score, redacted.CorrectGuesses = full.Score, full.CorrectGuesses
+	redacted.TotalGuesses = full.TotalGuesses
+	if *redacted != *full {
+		t.Errorf("redacted projection differs beyond in-round state:\nfull=%+v\nredacted=%+v", full, redacted)
+	}
+}
+
+func TestPlayerSnapshotIsSmallerAndKeepsListFields(t *testing.T) {
+	p := &Player{
+		Id:            "p1",
+		Username:      "alice",
+		Score:         120,
+		IsReady:       true,
+		IsConnected:   true,
+		IsMuted:       true,
+		CanvasWidth:   350,
+		CanvasHeight:  200,
+		JoinedAt:      time.Now(),
+		LastGuessTime: time.Now(),
+	}
+
+	full, err := json.Marshal(p.ToPublicPlayer())
+	if err != nil {
+		t.Fatalf("failed to encode public player: %v", err)
+	}
+	lean, err := json.Marshal(CreatePlayerSnapshot(p))
+	if err != nil {
+		t.Fatalf("failed to encode snapshot: %v", err)
+	}
+	if len(lean) >= len(full) {
+		t.Errorf("expected snapshot (%d bytes) to be smaller than the public player (%d bytes)", len(lean), len(full))
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(lean, &fields); err != nil {
+		t.Fatalf("failed to decode snapshot: %v", err)
+	}
+	for _, name := range []string{"id", "username", "score", "is_ready", "has_guessed", "is_connected", "is_idle", "is_muted", "can_draw"} {
+		if _, ok := fields[name]; !ok {
+			t.Errorf("expected snapshot to keep %q, got %v", name, fields)
+		}
+	}
+	for _, name := range []string{"canvas_width", "joined_at", "last_guess_time"} {
+		if _, ok := fields[name]; ok {
+			t.Errorf("expected snapshot to drop %q", name)
+		}
+	}
+}
@@ -1,4 +1,71 @@
 package internal
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// RemainingTime is the live time left on the room's phase timer, in
+// milliseconds: the frozen value while paused, and 0 without a running timer.
+// Caller must hold r.Mu.
+func (r *Room) RemainingTime() int64 {
+	switch {
+	case r.Timer == nil:
+		return 0
+	case r.Timer.IsPaused:
+		return r.Timer.TimeRemaining.Milliseconds()
+	case !r.Timer.IsActive:
+		return 0
+	}
+	return max(r.Timer.Duration-time.Since(r.Timer.StartTime), 0).Milliseconds()
+}
+
+// Validate reports the first setting outside its allowed bounds. Zero
+// fields are left to the server defaults and always pass.
+func (c RoomConfig) Validate() error {
+	if c.MaxRounds != 0 && (c.MaxRounds < MinRoomRounds || c.MaxRounds > MaxRoomRounds) {
+		return fmt.Errorf("rounds must be between %d and %d, got %d", MinRoomRounds, MaxRoomRounds, c.MaxRounds)
+	}
+	if c.TurnsPerPlayer != 0 && (c.TurnsPerPlayer < MinRoomTurns || c.TurnsPerPlayer > MaxRoomTurns) {
+		return fmt.Errorf("turns per player must be between %d and %d, got %d", MinRoomTurns, MaxRoomTurns, c.TurnsPerPlayer)
+	}
+	if c.MaxRounds != 0 && c.TurnsPerPlayer != 0 {
+		return fmt.Errorf("set either rounds or turns per player, not both")
+	}
+	if c.DrawTime != 0 && (c.DrawTime < MinRoomDrawTime || c.DrawTime > MaxRoomDrawTime) {
+		return fmt.Errorf("draw time must be between %v and %v, got %v", MinRoomDrawTime, MaxRoomDrawTime, c.DrawTime)
+	}
+	if c.WaitTime != 0 && (c.WaitTime < MinRoomWaitTime || c.WaitTime > MaxRoomWaitTime) {
+		return fmt.Errorf("wait time must be between %v and %v, got %v", MinRoomWaitTime, MaxRoomWaitTime, c.WaitTime)
+	}
+	if c.MaxPlayers != 0 && (c.MaxPlayers < MinRoomPlayers || c.MaxPlayers > MaxRoomPlayers) {
+		return fmt.Errorf("max players must be between %d and %d, got %d", MinRoomPlayers, MaxRoomPlayers, c.MaxPlayers)
+	}
+	if c.WordMix != "" && c.WordMix.Difficulties() == nil {
+		return fmt.Errorf("unknown word mix %q", c.WordMix)
+	}
+	if len(c.Language) > MaxLanguageLen || strings.Trim(c.Language, "abcdefghijklmnopqrstuvwxyz-_") != "" {
+		return fmt.Errorf("invalid language %q", c.Language)
+	}
+	return nil
+}
+
+// RoundsFor is the number of rounds a game under c lasts. A round gives every
+// player in the rotation one turn, so TurnsPerPlayer turns each is that many
+// rounds whatever the player count; players leaving drop out of the rotation
+// without costing anyone else a turn. Falls back to defaultRounds.
+func (c RoomConfig) RoundsFor(defaultRounds int) int {
+	switch {
+	case c.TurnsPerPlayer > 0:
+		return c.TurnsPerPlayer
+	case c.MaxRounds > 0:
+		return c.MaxRounds
+	}
+	return defaultRounds
+}
+
 // Methods (Room Struct)
 func (r *Room) GetPlayerByIndex(index int) *Player {
 	if index < 0 || index >= len(r.PlayerOrder) {
@@ -33,7 +100,7 @@ func (r *Room) CanStartGame() bool {
 
 func (r *Room) AreAllPlayersReady() bool {
 	for _, player := range r.Players {
-		if player.IsConnected && !player.IsReady {
+		if player.IsConnected && !player.IsIdle && !player.IsReady {
 			return false
 		}
 	}
@@ -41,12 +108,35 @@ func (r *Room) AreAllPlayersReady() bool {
 	return true
 }
 
+// IsGameOver reports whether the turn that just finished was the last one:
+// the final round is reached and nobody later in PlayerOrder can still take
+// a turn. This is the single end-of-game rule. Caller must hold r.Mu.
+func (r *Room) IsGameOver() bool {
+	if r.RoundNumber > r.MaxRounds {
+		return true
+	}
+	if r.RoundNumber != r.MaxRounds {
+		return false
+	}
+	later := r.PlayerOrder[min(max(r.CurrentIndex+1, 0), len(r.PlayerOrder)):]
+	return !slices.ContainsFunc(later, r.CanTakeTurn)
+}
+
+// CanTakeTurn reports whether the player seated at id in PlayerOrder can be
+// picked to draw. A seat held for a reconnecting player stays in the order
+// but is skipped. Caller must hold r.Mu.
+func (r *Room) CanTakeTurn(id string) bool {
+	p := r.Players[id]
+	return p != nil && p.IsConnected
+}
+
 func (r *Room) ResetPlayerGuessState() {
 	for _, player := range r.Players {
 		player.HasGuessed = false
 	}
 
 	r.CorrectGuessers = []PlayerGuess{}
+	r.CreditedGuessers = make(map[string]bool)
 }
 
 func (r *Room) HasEveryoneGuessed() bool {
@@ -57,4 +147,15 @@ func (r *Room) HasEveryoneGuessed() bool {
 	}
 
 	return true
-}
\ No newline at end of file
+}
+
+// BroadcastQueue returns the room's queue of pending broadcasts, made with
+// capacity size on first use. created reports whether this call made it, in
+// which case the caller starts the worker draining it.
+func (r *Room) BroadcastQueue(size int) (queue chan func(), created bool) {
+	r.broadcastOnce.Do(func() {
+		r.broadcasts = make(chan func(), size)
+		created = true
+	})
+	return r.broadcasts, created
+}
@@ -0,0 +1,103 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestIsGameOver(t *testing.T) {
+	cases := []struct {
+		name                string
+		round, maxRounds    int
+		currentIndex, order int
+		heldAfter           bool // later seats are held for reconnecting players
+		want                bool
+	}{
+		{"mid rotation in final round", 3, 3, 1, 4, false, false},
+		{"last drawer in final round", 3, 3, 3, 4, false, true},
+		{"last drawer before final round", 2, 3, 3, 4, false, false},
+		{"past max rounds", 4, 3, 0, 4, false, true},
+		{"single player final round", 3, 3, 0, 1, false, true},
+		{"only held seats left in final round", 3, 3, 1, 4, true, true},
+	}
+	for _, c := range cases {
+		room := &Room{
+			RoundNumber:  c.round,
+			MaxRounds:    c.maxRounds,
+			CurrentIndex: c.currentIndex,
+			Players:      map[string]*Player{},
+		}
+		for i := range c.order {
+			id := fmt.Sprintf("p%d", i)
+			held := c.heldAfter && i > c.currentIndex
+			room.Players[id] = &Player{Id: id, IsConnected: !held, IsReconnecting: held}
+			room.PlayerOrder = append(room.PlayerOrder, id)
+		}
+		if got := room.IsGameOver(); got != c.want {
+			t.Errorf("%s: IsGameOver() = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestRoomConfigValidate(t *testing.T) {
+	cases := []struct {
+		name  string
+		cfg   RoomConfig
+		valid bool
+	}{
+		{"defaults", RoomConfig{}, true},
+		{"all at bounds", RoomConfig{MaxRounds: 10, DrawTime: 30 * time.Second, WaitTime: 60 * time.Second, MaxPlayers: 2}, true},
+		{"too many rounds", RoomConfig{MaxRounds: 11}, false},
+		{"negative rounds", RoomConfig{MaxRounds: -1}, false},
+		{"draw time too short", RoomConfig{DrawTime: 29 * time.Second}, false},
+		{"draw time too long", RoomConfig{DrawTime: 181 * time.Second}, false},
+		{"wait time too short", RoomConfig{WaitTime: time.Second}, false},
+		{"too few players", RoomConfig{MaxPlayers: 1}, false},
+		{"too many players", RoomConfig{MaxPlayers: 13}, false},
+		{"turns per player", RoomConfig{TurnsPerPlayer: 2}, true},
+		{"too many turns", RoomConfig{TurnsPerPlayer: 11}, false},
+		{"language code", RoomConfig{Language: "pt-br"}, true},
+		{"bad language", RoomConfig{Language: "../es"}, false},
+		{"rounds and turns", RoomConfig{MaxRounds: 3, TurnsPerPlayer: 2}, false},
+		{"word mix", RoomConfig{WordMix: WordMixEasy}, true},
+		{"unknown word mix", RoomConfig{WordMix: "impossible"}, false},
+	}
+	for _, c := range cases {
+		if err := c.cfg.Validate(); (err == nil) != c.valid {
+			t.Errorf("%s: Validate() = %v, want valid=%v", c.name, err, c.valid)
+		}
+	}
+}
+
+func TestRemainingTime(t *testing.T) {
+	room := &Room{}
+	if got := room.RemainingTime(); got != 0 {
+		t.Errorf("expected 0 without a timer, got %d", got)
+	}
+
+	room.Timer = &GameTimer{StartTime: time.Now().Add(-2 * time.Second), Duration: 10 * time.Second, IsActive: true}
+	first := room.RemainingTime()
+	if first > 8000 || first < 7900 {
+		t.Errorf("expected about 8000ms left, got %d", first)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if second := room.RemainingTime(); second >= first {
+		t.Errorf("expected remaining time to keep falling, got %d then %d", first, second)
+	}
+
+	room.Timer.StartTime = time.Now().Add(-time.Minute)
+	if got := room.RemainingTime(); got != 0 {
+		t.Errorf("expected an overrun timer to report 0, got %d", got)
+	}
+
+	room.Timer = &GameTimer{TimeRemaining: 4500 * time.Millisecond, IsPaused: true}
+	if got := room.RemainingTime(); got != 4500 {
+		t.Errorf("expected a paused timer's frozen 4500ms, got %d", got)
+	}
+
+	room.Timer = &GameTimer{StartTime: time.Now(), Duration: time.Minute}
+	if got := room.RemainingTime(); got != 0 {
+		t.Errorf("expected an inactive timer to report 0, got %d", got)
+	}
+}
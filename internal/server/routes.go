@@ -4,12 +4,14 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/scythe504/skribblr-backend/internal"
 	"github.com/scythe504/skribblr-backend/internal/game"
+	"github.com/scythe504/skribblr-backend/internal/utils"
 )
 
 func (s *Server) RegisterRoutes() http.Handler {
@@ -22,8 +24,12 @@ func (s *Server) RegisterRoutes() http.Handler {
 
 	r.HandleFunc("/rooms-available", s.GetRoomToJoin)
 
+	r.HandleFunc("/avatar/{seed}", s.AvatarHandler)
+
 	r.HandleFunc("/ws/{roomId}", game.HandleWebSocket)
 
+	r.HandleFunc("/admin/rooms/{roomId}/word", s.ForceWordHandler).Methods(http.MethodPost)
+
 	return r
 }
 
@@ -64,40 +70,96 @@ func (s *Server) HelloWorldHandler(w http.ResponseWriter, r *http.Request) {
 	_, _ = w.Write(jsonResp)
 }
 
+// WriteResponse encodes data in the standard Response envelope with status,
+// filling the timing fields from start (when the handler began) to now
+func WriteResponse[T any](w http.ResponseWriter, start time.Time, status int, data T) {
+	end := time.Now()
+	resp := internal.Response[T]{
+		StatusCode:    status,
+		RespStartTime: start.UnixMilli(),
+		RespEndTime:   end.UnixMilli(),
+		NetRespTime:   end.Sub(start).Milliseconds(),
+		Data:          data,
+	}
+
+	// Encode first so a marshal failure can still become a clean 500
+	body, err := json.Marshal(resp)
+	if err != nil {
+		log.Printf("Error encoding response: %v", err)
+		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(body, '\n'))
+}
+
 func (s *Server) GetRoomToJoin(w http.ResponseWriter, r *http.Request) {
-	startTime := time.Now().UnixMilli()
+	start := time.Now()
 	roomId := game.GetJoinableRoom()
 
-	var resp internal.Response
-
-	if roomId != "" {
-		// Found a joinable room - SUCCESS
-		resp = internal.Response{
-			StatusCode:    http.StatusOK,
-			RespStartTime: startTime,
-			Data:          roomId,
-		}
-	} else {
+	if roomId == "" {
 		// No joinable room found - NOT FOUND or could create new room
-		resp = internal.Response{
-			StatusCode:    http.StatusNotFound,
-			RespStartTime: startTime,
-			Data:          "No joinable rooms available",
-		}
+		WriteResponse(w, start, http.StatusNotFound, "No joinable rooms available")
+		return
 	}
+	// Found a joinable room - SUCCESS
+	WriteResponse(w, start, http.StatusOK, roomId)
+}
 
-	// Calculate response times
-	endTime := time.Now().UnixMilli()
-	resp.RespEndTime = endTime
-	resp.NetRespTime = endTime - startTime
+// ForceWordHandler sets a room's word for the current turn, skipping word
+// selection. Needs "Authorization: Bearer <ADMIN_TOKEN>" and a {"word": ...} body.
+func (s *Server) ForceWordHandler(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !game.IsAdminToken(token) {
+		WriteResponse(w, start, http.StatusUnauthorized, "unauthorized")
+		return
+	}
 
-	// Set response headers
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(resp.StatusCode)
+	var body struct {
+		Word string `json:"word"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		WriteResponse(w, start, http.StatusBadRequest, "invalid body")
+		return
+	}
+	if err := game.ForceWord(mux.Vars(r)["roomId"], body.Word, token); err != nil {
+		WriteResponse(w, start, http.StatusConflict, err.Error())
+		return
+	}
+	WriteResponse(w, start, http.StatusOK, "word set")
+}
 
-	// Send JSON response
-	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		log.Printf("Error encoding response: %v", err)
+// AvatarHandler serves a deterministic identicon PNG for the seed in the path
+// (usually a username or player id). Optional ?size= sets the edge in pixels.
+func (s *Server) AvatarHandler(w http.ResponseWriter, r *http.Request) {
+	seed := strings.TrimSpace(mux.Vars(r)["seed"])
+	if seed == "" {
+		http.Error(w, "missing avatar seed", http.StatusBadRequest)
+		return
+	}
+
+	size := utils.IdenticonDefaultSize
+	if raw := r.URL.Query().Get("size"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			http.Error(w, "invalid size", http.StatusBadRequest)
+			return
+		}
+		size = parsed
+	}
+
+	img, err := utils.GenerateIdenticon(seed, size)
+	if err != nil {
+		log.Printf("Error generating avatar for seed %q: %v", seed, err)
 		http.Error(w, "Internal server error", http.StatusInternalServerError)
+		return
 	}
+
+	// Output depends only on seed and size, so clients may cache it forever
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	_, _ = w.Write(img)
 }
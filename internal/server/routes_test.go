@@ -1,10 +1,16 @@
 package server
 
 import (
+	"bytes"
+	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+
+	"github.com/scythe504/skribblr-backend/internal"
+	"github.com/scythe504/skribblr-backend/internal/game"
 )
 
 func TestHandler(t *testing.T) {
@@ -29,3 +35,109 @@ func TestHandler(t *testing.T) {
 		t.Errorf("expected response body to be %v; got %v", expected, string(body))
 	}
 }
+
+func TestAvatarHandler(t *testing.T) {
+	s := &Server{}
+	server := httptest.NewServer(s.RegisterRoutes())
+	defer server.Close()
+
+	fetch := func(path string) (*http.Response, []byte) {
+		t.Helper()
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("error making request to server. Err: %v", err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatalf("error reading response body. Err: %v", err)
+		}
+		return resp, body
+	}
+
+	resp, first := fetch("/avatar/alice")
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected status OK; got %v", resp.Status)
+	}
+	if ct := resp.Header.Get("Content-Type"); ct != "image/png" {
+		t.Errorf("expected image/png; got %q", ct)
+	}
+	_, again := fetch("/avatar/alice")
+	if !bytes.Equal(first, again) {
+		t.Error("expected identical bytes for the same seed")
+	}
+	_, other := fetch("/avatar/bob")
+	if bytes.Equal(first, other) {
+		t.Error("expected different bytes for a different seed")
+	}
+
+	if resp, _ := fetch("/avatar/alice?size=abc"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected 400 for a bad size; got %v", resp.Status)
+	}
+}
+
+func TestWriteResponseFillsEnvelope(t *testing.T) {
+	rec := httptest.NewRecorder()
+	start := time.Now().Add(-25 * time.Millisecond)
+
+	WriteResponse(rec, start, http.StatusCreated, map[string]int{"players": 3})
+
+	if rec.Code != http.StatusCreated {
+		t.Errorf("expected status %d, got %d", http.StatusCreated, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("expected JSON content type, got %q", ct)
+	}
+
+	var resp internal.Response[map[string]int]
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.StatusCode != http.StatusCreated || resp.Data["players"] != 3 {
+		t.Errorf("unexpected envelope %+v", resp)
+	}
+	if resp.RespStartTime != start.UnixMilli() || resp.RespEndTime < resp.RespStartTime {
+		t.Errorf("expected start %d <= end, got start=%d end=%d", start.UnixMilli(), resp.RespStartTime, resp.RespEndTime)
+	}
+	if resp.NetRespTime < 25 {
+		t.Errorf("expected net time to cover the handler's run, got %dms", resp.NetRespTime)
+	}
+}
+
+func TestForceWordHandlerRequiresAdminToken(t *testing.T) {
+	prev := game.AdminToken
+	game.AdminToken = "secret"
+	t.Cleanup(func() { game.AdminToken = prev })
+
+	s := &Server{}
+	server := httptest.NewServer(s.RegisterRoutes())
+	defer server.Close()
+
+	post := func(token string) int {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/admin/rooms/no-such-room/word",
+			bytes.NewBufferString(`{"word":"lighthouse"}`))
+		if err != nil {
+			t.Fatalf("error building request. Err: %v", err)
+		}
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("error making request to server. Err: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	if got := post(""); got != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token; got %d", got)
+	}
+	if got := post("wrong"); got != http.StatusUnauthorized {
+		t.Errorf("expected 401 with a bad token; got %d", got)
+	}
+	if got := post("secret"); got != http.StatusConflict {
+		t.Errorf("expected 409 for a missing room with a good token; got %d", got)
+	}
+}
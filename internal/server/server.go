@@ -1,13 +1,18 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"log"
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/joho/godotenv/autoload"
+	"github.com/scythe504/skribblr-backend/internal/game"
+	"github.com/scythe504/skribblr-backend/internal/utils"
 
 )
 
@@ -18,6 +23,41 @@ type Server struct {
 
 func NewServer() *http.Server {
 	port, _ := strconv.Atoi(os.Getenv("PORT"))
+
+	// Optional word list (and blacklist) replacing the built-in words
+	if wordListPath := os.Getenv("WORD_LIST_PATH"); wordListPath != "" {
+		if err := utils.LoadWords(wordListPath, os.Getenv("WORD_BLACKLIST_PATH")); err != nil {
+			log.Printf("Failed to load word list, keeping built-in words: %v", err)
+		}
+	}
+
+	// Optional word lists for other languages, as "es=/words/es.csv,fr=/words/fr.csv".
+	// Rooms asking for a language that isn't loaded use the default words.
+	for _, entry := range strings.Split(os.Getenv("WORD_LISTS"), ",") {
+		lang, path, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		if err := utils.LoadLanguageWords(lang, strings.TrimSpace(path), os.Getenv("WORD_BLACKLIST_PATH")); err != nil {
+			log.Printf("Failed to load %q word list: %v", lang, err)
+		}
+	}
+
+	// Admin endpoints stay disabled unless a token is configured
+	game.AdminToken = os.Getenv("ADMIN_TOKEN")
+
+	// Background game loops run until the server shuts down
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// Recover rooms whose phase timer stopped advancing the game
+	game.StartStuckRoomMonitor(ctx)
+
+	// Keep empty lobby rooms ready for quick play (QUICK_PLAY_POOL_SIZE=0 disables)
+	if size, err := strconv.Atoi(os.Getenv("QUICK_PLAY_POOL_SIZE")); err == nil && size >= 0 {
+		game.QuickPlayPoolSize = size
+	}
+	game.StartQuickPlayPool(ctx)
+
 	NewServer := &Server{
 		port: port,
 	}
@@ -30,6 +70,7 @@ func NewServer() *http.Server {
 		ReadTimeout:  10 * time.Second,
 		WriteTimeout: 30 * time.Second,
 	}
+	server.RegisterOnShutdown(cancel)
 
 	return server
 }
@@ -0,0 +1,211 @@
+package utils
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// Column positions used when a word list has no header row
+var defaultCsvColumns = map[string]int{"word": 0, "count": 1, "emoji": 2, "difficulty": 3}
+
+// ReadCsvFile reads a word list. If the first row has a "word" cell it is
+// treated as a header and columns are matched by name (case-insensitive, in
+// any order, unknown columns ignored); otherwise rows are
+// "word,count[,emoji[,difficulty]]". Only the word column is required: a
+// header without a count column falls back to the word's length, and a blank
+// or missing difficulty is guessed from the word's length. Rows with no word,
+// a missing/non-integer count or an unknown difficulty are skipped.
+func ReadCsvFile(path string) ([]Word, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open word list %s: %w", path, err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.FieldsPerRecord = -1 // rows may omit optional columns or carry trailing commas
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("read word list %s: %w", path, err)
+	}
+
+	columns := defaultCsvColumns
+	if len(records) > 0 {
+		if header := csvHeaderColumns(records[0]); hasColumn(header, "word") {
+			columns = header
+			records = records[1:]
+		}
+	}
+
+	words := make([]Word, 0, len(records))
+	for _, record := range records {
+		text := csvField(record, columns, "word")
+		if text == "" {
+			continue
+		}
+		word := Word{Text: text, Count: utf8.RuneCountInString(text), Emoji: csvField(record, columns, "emoji")}
+		if hasColumn(columns, "count") {
+			if word.Count, err = strconv.Atoi(csvField(record, columns, "count")); err != nil {
+				continue
+			}
+		}
+		difficulty := csvField(record, columns, "difficulty")
+		if difficulty == "" {
+			word.Difficulty = WordDifficultyByLength(utf8.RuneCountInString(text))
+		} else if word.Difficulty, err = ParseWordDifficulty(difficulty); err != nil {
+			log.Printf("[ReadCsvFile] %s: skipping %q: %v", path, text, err)
+			continue
+		}
+		words = append(words, word)
+	}
+	return words, nil
+}
+
+// csvHeaderColumns maps each lowercased, non-empty header name to its position
+func csvHeaderColumns(header []string) map[string]int {
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, seen := columns[name]; name != "" && !seen {
+			columns[name] = i
+		}
+	}
+	return columns
+}
+
+func hasColumn(columns map[string]int, name string) bool {
+	_, ok := columns[name]
+	return ok
+}
+
+// csvField returns the trimmed value of the named column, or "" when the
+// column is unknown or the row is too short to have it
+func csvField(record []string, columns map[string]int, name string) string {
+	i, ok := columns[name]
+	if !ok || i >= len(record) {
+		return ""
+	}
+	return strings.TrimSpace(record[i])
+}
+
+// ReadBlacklistFile reads one word per line into a lowercased set. Blank lines
+// and lines starting with '#' are ignored.
+func ReadBlacklistFile(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open blacklist %s: %w", path, err)
+	}
+	defer file.Close()
+
+	blacklist := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		blacklist[strings.ToLower(line)] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read blacklist %s: %w", path, err)
+	}
+	return blacklist, nil
+}
+
+// WordDifficultyByLength buckets a word by character count: 3-5 easy,
+// 6-8 medium, longer hard (very short words count as easy)
+func WordDifficultyByLength(count int) internal.WordDifficulty {
+	switch {
+	case count > 8:
+		return internal.DifficultyHard
+	case count > 5:
+		return internal.DifficultyMedium
+	default:
+		return internal.DifficultyEasy
+	}
+}
+
+// ParseWordDifficulty maps "easy", "medium" or "hard" (case-insensitive) to
+// its WordDifficulty
+func ParseWordDifficulty(s string) (internal.WordDifficulty, error) {
+	switch d := internal.WordDifficulty(strings.ToLower(strings.TrimSpace(s))); d {
+	case internal.DifficultyEasy, internal.DifficultyMedium, internal.DifficultyHard:
+		return d, nil
+	}
+	return "", fmt.Errorf("unknown word difficulty %q", s)
+}
+
+// LoadWords replaces the default language's word pools with the list at
+// csvPath, skipping any word in the blacklist at blacklistPath
+// (case-insensitive; "" means no blacklist). The pools are left untouched on
+// error. Call before serving games.
+func LoadWords(csvPath, blacklistPath string) error {
+	return LoadLanguageWords(DefaultLanguage, csvPath, blacklistPath)
+}
+
+// LoadLanguageWords is LoadWords for the word pools of lang, so rooms created
+// with that language draw their words from csvPath. Loading DefaultLanguage
+// replaces the built-in words.
+func LoadLanguageWords(lang, csvPath, blacklistPath string) error {
+	lang = NormalizeLanguage(lang)
+	if lang == "" {
+		return fmt.Errorf("word list %s has no language", csvPath)
+	}
+
+	words, err := ReadCsvFile(csvPath)
+	if err != nil {
+		return err
+	}
+
+	blacklist := map[string]bool{}
+	if blacklistPath != "" {
+		if blacklist, err = ReadBlacklistFile(blacklistPath); err != nil {
+			return err
+		}
+	}
+
+	var easy, medium, hard []Word
+	filtered := 0
+	for _, w := range words {
+		if blacklist[strings.ToLower(w.Text)] {
+			filtered++
+			continue
+		}
+		switch w.Difficulty {
+		case internal.DifficultyEasy:
+			easy = append(easy, w)
+		case internal.DifficultyMedium:
+			medium = append(medium, w)
+		case internal.DifficultyHard:
+			hard = append(hard, w)
+		}
+	}
+
+	if len(easy) == 0 || len(medium) == 0 || len(hard) == 0 {
+		return fmt.Errorf("word list %s leaves an empty difficulty pool (easy=%d medium=%d hard=%d)",
+			csvPath, len(easy), len(medium), len(hard))
+	}
+
+	if lang == DefaultLanguage {
+		easyWords, mediumWords, hardWords = easy, medium, hard
+	} else {
+		languagePools[lang] = wordPools{easy: easy, medium: medium, hard: hard}
+	}
+	log.Printf("[LoadWords] Loaded %d %s words from %s (easy=%d medium=%d hard=%d), filtered %d blacklisted",
+		len(easy)+len(medium)+len(hard), lang, csvPath, len(easy), len(medium), len(hard), filtered)
+	return nil
+}
+
+// UnloadLanguageWords drops the word list loaded for lang, so its rooms fall
+// back to the default words. DefaultLanguage's words can't be unloaded.
+func UnloadLanguageWords(lang string) {
+	delete(languagePools, NormalizeLanguage(lang))
+}
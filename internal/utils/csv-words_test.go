@@ -0,0 +1,227 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// withWordPools restores the package word pools after the test
+func withWordPools(t *testing.T) {
+	t.Helper()
+	easy, medium, hard, languages := easyWords, mediumWords, hardWords, languagePools
+	languagePools = map[string]wordPools{}
+	t.Cleanup(func() { easyWords, mediumWords, hardWords, languagePools = easy, medium, hard, languages })
+}
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestReadCsvFileSkipsBadRows(t *testing.T) {
+	path := writeTempFile(t, "words.csv", "word,count,\nace,3,\nlonely\nbee,x,\nbanana,6,\n")
+
+	words, err := ReadCsvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(words) != 2 || words[0].Text != "ace" || words[1] != (Word{Text: "banana", Count: 6, Difficulty: internal.DifficultyMedium}) {
+		t.Errorf("expected ace and banana only, got %+v", words)
+	}
+}
+
+func TestLoadWordsAppliesBlacklist(t *testing.T) {
+	withWordPools(t)
+	csvPath := writeTempFile(t, "words.csv",
+		"word,count,\ncat,3,\ndog,3,\nbanana,6,\nrabbit,6,\nelephant,8,\nhelicopter,10,\ncrocodile,9,\n")
+	blacklistPath := writeTempFile(t, "blacklist.txt", "# too easy\nDOG\n\nRabbit\ncrocodile\n")
+
+	if err := LoadWords(csvPath, blacklistPath); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for range 200 {
+		for _, choice := range GenerateWordChoices() {
+			switch strings.ToLower(choice.Word) {
+			case "dog", "rabbit", "crocodile":
+				t.Fatalf("blacklisted word %q offered as a choice", choice.Word)
+			}
+		}
+	}
+}
+
+func TestLoadWordsKeepsPoolsOnError(t *testing.T) {
+	withWordPools(t)
+	before := len(easyWords)
+
+	if err := LoadWords(filepath.Join(t.TempDir(), "missing.csv"), ""); err == nil {
+		t.Error("expected an error for a missing word list")
+	}
+	csvPath := writeTempFile(t, "words.csv", "cat,3,\n")
+	if err := LoadWords(csvPath, ""); err == nil {
+		t.Error("expected an error when a difficulty pool would be empty")
+	}
+	if len(easyWords) != before {
+		t.Error("expected the word pools to be left untouched")
+	}
+}
+
+func TestLoadWordsCarriesEmojiIntoChoices(t *testing.T) {
+	withWordPools(t)
+	csvPath := writeTempFile(t, "words.csv",
+		"word,count,emoji\ncat,3,🐱\nbanana,6,🍌\nhelicopter,10,🚁\n")
+
+	if err := LoadWords(csvPath, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]string{"cat": "🐱", "banana": "🍌", "helicopter": "🚁"}
+	for _, choice := range GenerateWordChoices() {
+		if choice.Emoji != want[choice.Word] {
+			t.Errorf("expected %q to carry emoji %q, got %q", choice.Word, want[choice.Word], choice.Emoji)
+		}
+	}
+}
+
+func TestReadCsvFileMatchesHeaderColumnsByName(t *testing.T) {
+	path := writeTempFile(t, "words.csv",
+		"Emoji,category,Word,Count\n🐱,animals,cat,3\n,food,banana,6\n🚁,,helicopter\n")
+
+	words, err := ReadCsvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Word{
+		{Text: "cat", Count: 3, Emoji: "🐱", Difficulty: internal.DifficultyEasy},
+		{Text: "banana", Count: 6, Difficulty: internal.DifficultyMedium},
+	}
+	if len(words) != len(want) || words[0] != want[0] || words[1] != want[1] {
+		t.Errorf("expected %+v (short row skipped), got %+v", want, words)
+	}
+}
+
+func TestReadCsvFileHeaderWithOptionalColumnsAbsent(t *testing.T) {
+	path := writeTempFile(t, "words.csv", "word\ncat\nhelicopter\n")
+
+	words, err := ReadCsvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Word{
+		{Text: "cat", Count: 3, Difficulty: internal.DifficultyEasy},
+		{Text: "helicopter", Count: 10, Difficulty: internal.DifficultyHard},
+	}
+	if len(words) != len(want) || words[0] != want[0] || words[1] != want[1] {
+		t.Errorf("expected counts derived from word length, got %+v", words)
+	}
+}
+
+func TestReadCsvFileParsesDifficultyColumn(t *testing.T) {
+	path := writeTempFile(t, "words.csv",
+		"word,count,emoji,difficulty\nqi,2,,Hard\nbutterfly,9,🦋,easy\nbanana,6,,\nzebra,5,,impossible\n,4,,easy\nkiwi,x,,easy\n")
+
+	words, err := ReadCsvFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Word{
+		{Text: "qi", Count: 2, Difficulty: internal.DifficultyHard},
+		{Text: "butterfly", Count: 9, Emoji: "🦋", Difficulty: internal.DifficultyEasy},
+		{Text: "banana", Count: 6, Difficulty: internal.DifficultyMedium}, // blank: guessed from length
+	}
+	if len(words) != len(want) {
+		t.Fatalf("expected %+v (unknown difficulty and malformed rows skipped), got %+v", want, words)
+	}
+	for i := range want {
+		if words[i] != want[i] {
+			t.Errorf("row %d: expected %+v, got %+v", i, want[i], words[i])
+		}
+	}
+}
+
+func TestParseWordDifficulty(t *testing.T) {
+	for in, want := range map[string]internal.WordDifficulty{
+		"easy": internal.DifficultyEasy, " Medium ": internal.DifficultyMedium, "HARD": internal.DifficultyHard,
+	} {
+		if got, err := ParseWordDifficulty(in); err != nil || got != want {
+			t.Errorf("ParseWordDifficulty(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "extreme", "3"} {
+		if _, err := ParseWordDifficulty(in); err == nil {
+			t.Errorf("ParseWordDifficulty(%q) should fail", in)
+		}
+	}
+}
+
+func TestLoadWordsBucketsByParsedDifficulty(t *testing.T) {
+	withWordPools(t)
+	csvPath := writeTempFile(t, "words.csv",
+		"word,count,emoji,difficulty\nqi,2,,hard\nbutterfly,9,,easy\nbanana,6,,medium\n")
+
+	if err := LoadWords(csvPath, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]internal.WordDifficulty{
+		"qi": internal.DifficultyHard, "butterfly": internal.DifficultyEasy, "banana": internal.DifficultyMedium,
+	}
+	for range 20 {
+		choices := GenerateWordChoices()
+		if len(choices) != 3 {
+			t.Fatalf("expected 3 choices, got %+v", choices)
+		}
+		seen := map[internal.WordDifficulty]bool{}
+		for _, c := range choices {
+			if c.Difficult != want[c.Word] || c.Points != want[c.Word].BasePoints() {
+				t.Errorf("expected %q tagged %q, got %q (%d points)", c.Word, want[c.Word], c.Difficult, c.Points)
+			}
+			seen[c.Difficult] = true
+		}
+		if len(seen) != 3 {
+			t.Errorf("expected one word per difficulty, got %+v", choices)
+		}
+	}
+}
+
+func TestLanguageWordListsStaySeparate(t *testing.T) {
+	withWordPools(t)
+	spanish := map[string]bool{"sol": true, "gato": true, "perro": true, "manzana": true, "ventana": true, "mariposa": true, "helicoptero": true}
+	csvPath := writeTempFile(t, "es.csv",
+		"word\nsol\ngato\nperro\nmanzana\nventana\nmariposa\nhelicoptero\n")
+
+	if err := LoadLanguageWords(" ES ", csvPath, ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !HasLanguage("es") || HasLanguage("fr") {
+		t.Errorf("expected only es to be loaded alongside the default")
+	}
+
+	for range 100 {
+		for _, choice := range GenerateWordChoicesFor("es") {
+			if !spanish[choice.Word] {
+				t.Fatalf("expected only Spanish words for es, got %q", choice.Word)
+			}
+		}
+		// Other languages, loaded or not, keep the default words
+		for _, lang := range []string{DefaultLanguage, "fr", ""} {
+			for _, choice := range GenerateWordChoicesFor(lang) {
+				if spanish[choice.Word] {
+					t.Fatalf("expected default words for %q, got Spanish %q", lang, choice.Word)
+				}
+			}
+		}
+	}
+
+	UnloadLanguageWords("es")
+	if HasLanguage("es") {
+		t.Error("expected es to fall back to the default words once unloaded")
+	}
+}
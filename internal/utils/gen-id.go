@@ -1,16 +1,37 @@
 package utils
 
 import (
-	"math/rand"
+	"crypto/rand"
 )
 
-func GenerateID(n int) string {
+// IDAlphabet is the URL-safe character set used by GenerateID
+const IDAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
-	var letterRunes = []rune("abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ")
+// GenerateID returns an n-character id drawn uniformly from IDAlphabet using
+// crypto/rand. Random bytes past the largest multiple of the alphabet size are
+// rejected so no character is favoured. Returns "" for n <= 0.
+func GenerateID(n int) string {
+	if n <= 0 {
+		return ""
+	}
 
-	b := make([]rune, n)
-	for i := range b {
-		b[i] = letterRunes[rand.Intn(len(letterRunes))]
+	const limit = 256 - 256%len(IDAlphabet)
+	b := make([]byte, 0, n)
+	buf := make([]byte, n+n/4+1)
+	for len(b) < n {
+		if _, err := rand.Read(buf); err != nil {
+			// crypto/rand only fails if the OS entropy source is broken
+			panic("GenerateID: crypto/rand failed: " + err.Error())
+		}
+		for _, v := range buf {
+			if int(v) >= limit {
+				continue
+			}
+			b = append(b, IDAlphabet[int(v)%len(IDAlphabet)])
+			if len(b) == n {
+				break
+			}
+		}
 	}
 	return string(b)
 }
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// Identicon size limits (pixels, square)
+const (
+	IdenticonDefaultSize = 80
+	IdenticonMinSize     = 16
+	IdenticonMaxSize     = 512
+
+	identiconGrid = 5 // cells per side, mirrored around the middle column
+)
+
+// GenerateIdenticon renders a deterministic, horizontally symmetric identicon
+// PNG for seed. The same seed and size always produce the same bytes.
+func GenerateIdenticon(seed string, size int) ([]byte, error) {
+	size = min(max(size, IdenticonMinSize), IdenticonMaxSize)
+	hash := sha256.Sum256([]byte(seed))
+
+	// First three bytes pick the foreground, kept away from the light background
+	fg := color.RGBA{R: hash[0]/2 + 32, G: hash[1]/2 + 32, B: hash[2]/2 + 32, A: 255}
+	bg := color.RGBA{R: 240, G: 240, B: 240, A: 255}
+
+	// Only the left half (plus middle column) is random; the right mirrors it
+	var filled [identiconGrid][identiconGrid]bool
+	half := (identiconGrid + 1) / 2
+	bit := 0
+	for row := 0; row < identiconGrid; row++ {
+		for col := 0; col < half; col++ {
+			on := hash[3+bit/8]&(1<<(bit%8)) != 0
+			filled[row][col] = on
+			filled[row][identiconGrid-1-col] = on
+			bit++
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	cell := float64(size) / identiconGrid
+	for y := 0; y < size; y++ {
+		row := min(int(float64(y)/cell), identiconGrid-1)
+		for x := 0; x < size; x++ {
+			col := min(int(float64(x)/cell), identiconGrid-1)
+			if filled[row][col] {
+				img.SetRGBA(x, y, fg)
+			} else {
+				img.SetRGBA(x, y, bg)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -1,10 +1,14 @@
 package utils
 
 import (
+	"encoding/json"
+	"io"
 	"log"
 	"math/rand"
+	"os"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/scythe504/skribblr-backend/internal"
@@ -33,50 +37,143 @@ func GetMaskedWord(word string) string {
 	return strings.Join(masked, " ")
 }
 
+// leadingArticles are dropped from the front of guesses in lenient matching
+var leadingArticles = []string{"a", "an", "the"}
 
+// NormalizeGuess prepares a guess or target word for comparison: trimmed and
+// lowercased, and with a single leading article removed when stripArticles is
+// set (so "the eiffel tower" and "eiffel tower" compare equal)
+func NormalizeGuess(s string, stripArticles bool) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if !stripArticles {
+		return s
+	}
+
+	words := strings.Fields(s)
+	if len(words) > 1 && slices.Contains(leadingArticles, words[0]) {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// Proximity bands reported for wrong guesses
+const (
+	ProximityHot  = "hot"
+	ProximityWarm = "warm"
+	ProximityCold = "cold"
+)
+
+// LevenshteinDistance returns the rune-level edit distance between a and b
+func LevenshteinDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 {
+		return len(rb)
+	}
+	if len(rb) == 0 {
+		return len(ra)
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(rb)]
+}
+
+// IsCloseGuess reports whether a wrong (normalized) guess is within
+// maxDistance edits of the target. An exact match is not close: it is correct.
+func IsCloseGuess(guess, target string, maxDistance int) bool {
+	if maxDistance <= 0 || target == "" || guess == target {
+		return false
+	}
+	return LevenshteinDistance(guess, target) <= maxDistance
+}
+
+// ProximityBand classifies how close a (normalized) guess is to the target.
+// The band is derived from the edit distance relative to the target length,
+// so it never leaks letters of the word itself.
+func ProximityBand(guess, target string) string {
+	targetLen := len([]rune(target))
+	if targetLen == 0 {
+		return ProximityCold
+	}
+
+	distance := LevenshteinDistance(guess, target)
+	ratio := float64(distance) / float64(targetLen)
+
+	switch {
+	case distance <= 1 || ratio <= 0.25:
+		return ProximityHot
+	case ratio <= 0.5:
+		return ProximityWarm
+	default:
+		return ProximityCold
+	}
+}
+
+// GenerateWordChoices offers three words from the DefaultLanguage pools
+func GenerateWordChoices() []internal.Word {
+	return GenerateWordChoicesFor(DefaultLanguage)
+}
+
+// GenerateWordChoicesFor offers three words from lang's pools, or from the
+// DefaultLanguage pools when lang has no loaded word list
+func GenerateWordChoicesFor(lang string) []internal.Word {
+	return GenerateWordChoicesMix(lang, internal.WordMixBalanced.Difficulties(), nil)
+}
+
+// GenerateWordChoicesMix offers one word from lang's pools per entry of mix,
+// each of that difficulty. Words in used (lowercased) are skipped unless a
+// pool has nothing else left.
+func GenerateWordChoicesMix(lang string, mix []internal.WordDifficulty, used map[string]bool) []internal.Word {
+	pools := poolsFor(lang)
 
-func GenerateWordChoices() []string {
 	// TODO:
 	// Initialize random seed
 	rand.NewSource(time.Now().UnixNano())
 	
-	var choices []string
-	
-	// 1. Select one word from each difficulty (easy, medium, hard)
+	// 1. Select one word per difficulty in the mix
 	// 2. Randomize selection within each category
-	easyChoice := easyWords[rand.Intn(len(easyWords))]
-	mediumChoice := mediumWords[rand.Intn(len(mediumWords))]
-	hardChoice := hardWords[rand.Intn(len(hardWords))]
-	
-	// Add to choices slice
-	choices = append(choices, easyChoice.Text, mediumChoice.Text, hardChoice.Text)
-	
-	// 5. Ensure no duplicates (basic check - unlikely with different difficulty levels)
-	// This is a simple check since words from different difficulty levels are unlikely to duplicate
+	// Tag each with its difficulty and base points
+	// 5. Ensure no duplicates (likelier when the mix repeats a difficulty),
+	// compared case-insensitively so a selection can never match two choices.
+	// A duplicate is redrawn from the same difficulty; attempts are bounded
+	// so a tiny word list can't spin forever.
 	seen := make(map[string]bool)
-	var uniqueChoices []string
+	var uniqueChoices []internal.Word
 	
-	for _, word := range choices {
-		if !seen[word] {
-			seen[word] = true
-			uniqueChoices = append(uniqueChoices, word)
+	for _, difficulty := range mix {
+		for attempts := 0; attempts < 20; attempts++ {
+			choice := toWordChoice(pickUnused(pools.of(difficulty), used), difficulty)
+			if !seen[strings.ToLower(choice.Word)] {
+				seen[strings.ToLower(choice.Word)] = true
+				uniqueChoices = append(uniqueChoices, choice)
+				break
+			}
 		}
 	}
 	
-	// If we somehow have duplicates, fill with random words from any category
-	for len(uniqueChoices) < 3 {
-		var randomWord string
-		switch rand.Intn(3) {
-		case 0:
-			randomWord = easyWords[rand.Intn(len(easyWords))].Text
-		case 1:
-			randomWord = mediumWords[rand.Intn(len(mediumWords))].Text
-		case 2:
-			randomWord = hardWords[rand.Intn(len(hardWords))].Text
-		}
+	// If a difficulty ran out of distinct words, fill from the mix's others
+	for attempts := 0; len(uniqueChoices) < len(mix) && attempts < 50; attempts++ {
+		difficulty := mix[rand.Intn(len(mix))]
+		randomWord := toWordChoice(pickUnused(pools.of(difficulty), used), difficulty)
 		
-		if !seen[randomWord] {
-			seen[randomWord] = true
+		if !seen[strings.ToLower(randomWord.Word)] {
+			seen[strings.ToLower(randomWord.Word)] = true
 			uniqueChoices = append(uniqueChoices, randomWord)
 		}
 	}
@@ -87,43 +184,175 @@ func GenerateWordChoices() []string {
 		uniqueChoices[i], uniqueChoices[j] = uniqueChoices[j], uniqueChoices[i]
 	}
 	
-	// 4. Return slice of 3 words
+	// 4. Return one word per entry of the mix
 	return uniqueChoices
 }
 
-// UpdatePlayerOrder rebuilds the drawing rotation order
+// RandomWords is the default WordProvider: choices drawn at random from the
+// room's language and difficulty mix
+type RandomWords struct{}
+
+// GenerateChoices implements internal.WordProvider
+func (RandomWords) GenerateChoices(cfg internal.RoomConfig, used map[string]bool) []internal.Word {
+	return GenerateWordChoicesMix(cfg.Language, cfg.WordMix.Difficulties(), used)
+}
+
+// WordWeighting controls how a word's Count biases its chance of being offered
+type WordWeighting int
+
+const (
+	WeightUniform        WordWeighting = iota // every word equally likely
+	WeightByCount                             // higher Count offered more often
+	WeightByInverseCount                      // lower Count offered more often
+)
+
+// Word choice weighting. Count is the character count in the built-in lists,
+// so the default keeps selection uniform; switch modes for lists whose count
+// column holds a frequency.
+var (
+	WordChoiceWeighting = WeightUniform
+	MaxWordWeight       = 100 // counts are clamped to [1, MaxWordWeight] so no word can dominate its pool
+)
+
+// pickWord draws one word from a non-empty pool according to WordChoiceWeighting
+func pickWord(pool []Word) Word {
+	if WordChoiceWeighting == WeightUniform {
+		return pool[rand.Intn(len(pool))]
+	}
+
+	weights := make([]float64, len(pool))
+	total := 0.0
+	for i, w := range pool {
+		weight := float64(min(max(w.Count, 1), MaxWordWeight))
+		if WordChoiceWeighting == WeightByInverseCount {
+			weight = 1 / weight
+		}
+		weights[i] = weight
+		total += weight
+	}
+
+	target := rand.Float64() * total
+	for i, weight := range weights {
+		if target < weight {
+			return pool[i]
+		}
+		target -= weight
+	}
+	return pool[len(pool)-1] // float rounding left target just past the end
+}
+
+// toWordChoice converts a pool word into a choice carrying difficulty metadata
+func toWordChoice(w Word, difficulty internal.WordDifficulty) internal.Word {
+	if w.Difficulty != "" {
+		difficulty = w.Difficulty
+	}
+	return internal.Word{
+		Word:      w.Text,
+		Count:     w.Count,
+		Difficult: difficulty,
+		Points:    difficulty.BasePoints(),
+		Emoji:     w.Emoji,
+	}
+}
+
+// pickUnused draws a word from a non-empty pool that isn't in used, or any
+// word of the pool once every one of them has been used
+func pickUnused(pool []Word, used map[string]bool) Word {
+	if len(used) > 0 {
+		unused := slices.DeleteFunc(slices.Clone(pool), func(w Word) bool {
+			return used[strings.ToLower(w.Text)]
+		})
+		if len(unused) > 0 {
+			return pickWord(unused)
+		}
+	}
+	return pickWord(pool)
+}
+
+// UpdatePlayerOrder rebuilds the drawing rotation order. Connected players,
+// and players whose seat is held for a reconnect, keep their place in the
+// rotation and newcomers join at the end, so every player draws once per
+// round. Held seats are skipped when picking a drawer (see Room.CanTakeTurn).
 func UpdatePlayerOrder(room *internal.Room) {
 	// TODO:
 	room.Mu.Lock()
 	defer room.Mu.Unlock()
 
-	// 1. Clear existing PlayerOrder slice
-	room.PlayerOrder = make([]string, 0)
-
-	// 2. Add all connected players to slice
-	for _, player := range room.Players {
-		if player.IsConnected {
-			room.PlayerOrder = append(room.PlayerOrder, player.Id)
+	// 1. Collect connected and held players, once per player id. The map is keyed by
+	// id, but a reconnection bug could leave the same player under two keys,
+	// which would make them draw twice per rotation.
+	connected := make(map[string]*internal.Player, len(room.Players))
+	for key, player := range room.Players {
+		if !player.IsConnected && !player.IsReconnecting {
+			continue
 		}
+		if key != player.Id {
+			log.Printf("[UpdatePlayerOrder] Invariant violated in room %s: player %s stored under key %s",
+				room.Id, player.Id, key)
+		}
+		if connected[player.Id] != nil {
+			log.Printf("[UpdatePlayerOrder] Invariant violated in room %s: duplicate player %s dropped from order",
+				room.Id, player.Id)
+			continue
+		}
+		connected[player.Id] = player
 	}
 
-	// 3. Optional: shuffle for fairness
+	// 2. Keep the existing rotation for players still seated
+	oldOrder := room.PlayerOrder
+	order := make([]string, 0, len(connected))
+	for _, id := range oldOrder {
+		if connected[id] != nil && !slices.Contains(order, id) {
+			order = append(order, id)
+		}
+	}
 
-	// 4. Adjust CurrentIndex if it's now invalid
-	if room.CurrentIndex >= len(room.PlayerOrder) {
-		room.CurrentIndex = 0
+	// 3. Append newcomers in join order
+	var newcomers []*internal.Player
+	for id, player := range connected {
+		if !slices.Contains(order, id) {
+			newcomers = append(newcomers, player)
+		}
+	}
+	slices.SortFunc(newcomers, func(a, b *internal.Player) int {
+		if c := a.JoinedAt.Compare(b.JoinedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+	for _, player := range newcomers {
+		order = append(order, player.Id)
 	}
 
-	// 5. Handle case where current drawer left
+	// 4. Point CurrentIndex at the current drawer in the new order
 	if room.Current != nil {
-		found := slices.Contains(room.PlayerOrder, room.Current.Id)
-		if !found {
-			room.Current = nil     // no valid current drawer anymore
-			room.CurrentIndex = 0  // reset index
+		if idx := slices.Index(order, room.Current.Id); idx >= 0 {
+			room.CurrentIndex = idx
+		} else {
+			// Current drawer left: step back to just before whoever followed
+			// them, so the next advance hands the turn to that player
+			pos := slices.Index(oldOrder, room.Current.Id)
+			if pos < 0 {
+				// already removed from the order; the index now points at the follower
+				pos = min(max(room.CurrentIndex, 0), len(oldOrder))
+			}
+			keptBefore := 0
+			for _, id := range oldOrder[:pos] {
+				if slices.Contains(order, id) {
+					keptBefore++
+				}
+			}
+			room.Current = nil // no valid current drawer anymore
+			room.CurrentIndex = keptBefore - 1
 		}
 	}
-}
 
+	// 5. Adjust CurrentIndex if it's now invalid
+	if room.CurrentIndex >= len(order) {
+		room.CurrentIndex = 0
+	}
+	room.PlayerOrder = order
+}
 
 // ValidateGameState checks room state consistency
 func ValidateGameState(room *internal.Room) bool {
@@ -173,14 +402,48 @@ func ValidateGameState(room *internal.Room) bool {
 }
 
 
-// GetPlayerStats returns formatted statistics for a player
+// GetPlayerStats returns formatted statistics for a player in the current
+// game. Guess times and turns drawn come from the room's finished rounds.
+// Caller must hold player.Room.Mu if the player is in a room.
 func GetPlayerStats(player *internal.Player) map[string]any {
-	// TODO:
 	// 1. Calculate accuracy rate (correct/total guesses)
-	// 2. Calculate average guess time
-	// 3. Count total games played
-	// 4. Return formatted stats map
-	return map[string]any{}
+	accuracy := 0.0
+	if player.TotalGuesses > 0 {
+		accuracy = float64(player.CorrectGuesses) / float64(player.TotalGuesses)
+	}
+
+	// 2. Calculate average guess time and count turns drawn from round stats
+	var guessTimeTotal int64
+	guessCount, timesDrawn := 0, 0
+	if player.Room != nil {
+		for _, round := range player.Room.RoundStats {
+			if round.DrawerId == player.Id {
+				timesDrawn++
+			}
+			for _, guess := range round.CorrectGuessers {
+				if guess.PlayerID == player.Id {
+					guessTimeTotal += int64(guess.GuessTime)
+					guessCount++
+				}
+			}
+		}
+	}
+	var avgGuessTimeMs int64
+	if guessCount > 0 {
+		avgGuessTimeMs = guessTimeTotal / int64(guessCount)
+	}
+
+	// 3. Return formatted stats map
+	return map[string]any{
+		"player_id":         player.Id,
+		"username":          player.Username,
+		"score":             player.Score,
+		"total_guesses":     player.TotalGuesses,
+		"correct_guesses":   player.CorrectGuesses,
+		"accuracy":          accuracy,
+		"avg_guess_time_ms": avgGuessTimeMs,
+		"times_drawn":       timesDrawn,
+	}
 }
 
 // =============================================================================
@@ -198,12 +461,79 @@ func GetRoomStats(room *internal.Room) map[string]interface{} {
 	return map[string]interface{}{}
 }
 
-// LogGameEvent records important game events for analytics
+// EventLevel ranks game events so noisy ones can be filtered out
+type EventLevel int
+
+const (
+	EventLevelDebug EventLevel = iota // high-frequency events (drawing, timer ticks)
+	EventLevelInfo                    // everything else
+)
+
+// Game event logging. Events below GameEventMinLevel are dropped; types not
+// listed in GameEventLevels are EventLevelInfo.
+var (
+	GameEventMinLevel = EventLevelInfo
+	GameEventLevels   = map[string]EventLevel{
+		string(internal.PixelPlace): EventLevelDebug,
+		string(internal.BatchPlace): EventLevelDebug,
+		string(internal.ErasePixel): EventLevelDebug,
+		string(internal.BatchErase): EventLevelDebug,
+		"timer_update":              EventLevelDebug,
+		"game_state_update":         EventLevelDebug,
+		"canvas_state":              EventLevelDebug,
+		"canvas_cleared":            EventLevelDebug,
+	}
+
+	gameEventMu   sync.Mutex
+	gameEventSink io.Writer = os.Stdout
+)
+
+// GameEvent is one structured line written by LogGameEvent
+type GameEvent struct {
+	Timestamp int64  `json:"timestamp"` // unix milliseconds
+	RoomID    string `json:"room_id"`
+	Event     string `json:"event"`
+	Data      any    `json:"data,omitempty"`
+}
+
+// SetGameEventSink redirects LogGameEvent output (os.Stdout by default)
+func SetGameEventSink(w io.Writer) {
+	gameEventMu.Lock()
+	defer gameEventMu.Unlock()
+	gameEventSink = w
+}
+
+// LogGameEvent records important game events for analytics as one JSON line
+// on the event sink
 func LogGameEvent[T any](room *internal.Room, eventType string, data T) {
-	// TODO:
-	// 1. Create structured log entry
-	// 2. Include room ID, timestamp, event type
-	// 3. Include relevant game state
-	// 4. Write to log file or analytics service
-	// 5. Handle different event types appropriately
+	// 1. Drop events below the configured level
+	level, ok := GameEventLevels[eventType]
+	if !ok {
+		level = EventLevelInfo
+	}
+	if level < GameEventMinLevel {
+		return
+	}
+
+	// 2. Create structured log entry with room ID, timestamp, event type
+	event := GameEvent{
+		Timestamp: time.Now().UnixMilli(),
+		Event:     eventType,
+		Data:      data,
+	}
+	if room != nil {
+		event.RoomID = room.Id
+	}
+	line, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("[LogGameEvent] room=%s: failed to encode %s event: %v", event.RoomID, eventType, err)
+		return
+	}
+
+	// 3. Write to the sink, one event per line
+	gameEventMu.Lock()
+	defer gameEventMu.Unlock()
+	if _, err := gameEventSink.Write(append(line, '\n')); err != nil {
+		log.Printf("[LogGameEvent] room=%s: failed to write %s event: %v", event.RoomID, eventType, err)
+	}
 }
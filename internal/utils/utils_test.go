@@ -0,0 +1,452 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"os"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"cat", "", 3},
+		{"cat", "cat", 0},
+		{"cat", "cut", 1},
+		{"kitten", "sitting", 3},
+		{"café", "cafe", 1},
+	}
+
+	for _, c := range cases {
+		if got := LevenshteinDistance(c.a, c.b); got != c.want {
+			t.Errorf("LevenshteinDistance(%q, %q) = %d; want %d", c.a, c.b, got, c.want)
+		}
+	}
+}
+
+func TestProximityBand(t *testing.T) {
+	cases := []struct {
+		guess, target string
+		want          string
+	}{
+		{"elephent", "elephant", ProximityHot},
+		{"elephan", "elephant", ProximityHot},
+		{"element", "elephant", ProximityWarm},
+		{"elk", "elephant", ProximityCold},
+		{"zebra", "elephant", ProximityCold},
+		{"anything", "", ProximityCold},
+	}
+
+	for _, c := range cases {
+		if got := ProximityBand(c.guess, c.target); got != c.want {
+			t.Errorf("ProximityBand(%q, %q) = %q; want %q", c.guess, c.target, got, c.want)
+		}
+	}
+}
+
+func TestIsCloseGuess(t *testing.T) {
+	cases := []struct {
+		guess, target string
+		want          bool
+	}{
+		{"aple", "apple", true},   // one deletion
+		{"applle", "apple", true}, // one insertion
+		{"appme", "apple", true},  // one substitution
+		{"aplpe", "apple", false}, // a swap is two edits
+		{"ample", "apple", true},
+		{"amble", "apple", false}, // distance 2
+		{"apple", "apple", false}, // identical is a correct guess
+		{"a", "", false},
+	}
+
+	for _, c := range cases {
+		if got := IsCloseGuess(c.guess, c.target, 1); got != c.want {
+			t.Errorf("IsCloseGuess(%q, %q, 1) = %v; want %v", c.guess, c.target, got, c.want)
+		}
+	}
+	if IsCloseGuess("aple", "apple", 0) {
+		t.Error("expected a zero threshold to disable close guesses")
+	}
+}
+
+func TestGenerateWordChoicesSkipCaseVariants(t *testing.T) {
+	withWordPools(t)
+	easyWords = []Word{{Text: "Cat", Count: 3}}
+	mediumWords = []Word{{Text: "cat", Count: 3}}
+	hardWords = []Word{{Text: "CAT", Count: 3}, {Text: "bird", Count: 4}, {Text: "fish", Count: 4}}
+
+	for range 20 {
+		choices := GenerateWordChoices()
+		seen := make(map[string]string)
+		for _, choice := range choices {
+			key := strings.ToLower(choice.Word)
+			if prev, ok := seen[key]; ok {
+				t.Fatalf("expected no case-variant choices, got %q and %q in %+v", prev, choice.Word, choices)
+			}
+			seen[key] = choice.Word
+		}
+	}
+}
+
+func TestGenerateWordChoicesFollowMix(t *testing.T) {
+	mixes := []internal.WordMix{internal.WordMixBalanced, internal.WordMixMedium, internal.WordMixEasy, internal.WordMixHard}
+	for _, mix := range mixes {
+		want := mix.Difficulties()
+		slices.Sort(want)
+		for range 20 {
+			var got []internal.WordDifficulty
+			for _, choice := range GenerateWordChoicesMix(DefaultLanguage, mix.Difficulties(), nil) {
+				got = append(got, choice.Difficult)
+			}
+			slices.Sort(got)
+			if !slices.Equal(got, want) {
+				t.Fatalf("%s: expected difficulties %v, got %v", mix, want, got)
+			}
+		}
+	}
+}
+
+func TestGenerateWordChoicesAvoidUsedWords(t *testing.T) {
+	withWordPools(t)
+	easyWords = []Word{{Text: "Cat", Count: 3}, {Text: "dog", Count: 3}}
+	mediumWords = []Word{{Text: "bird", Count: 4}, {Text: "fish", Count: 4}}
+	hardWords = []Word{{Text: "zebra", Count: 5}, {Text: "camel", Count: 5}}
+
+	used := map[string]bool{"cat": true, "bird": true, "zebra": true}
+	for range 20 {
+		var got []string
+		for _, choice := range GenerateWordChoicesMix(DefaultLanguage, internal.WordMixBalanced.Difficulties(), used) {
+			got = append(got, choice.Word)
+		}
+		slices.Sort(got)
+		if !slices.Equal(got, []string{"camel", "dog", "fish"}) {
+			t.Fatalf("expected only unused words, got %v", got)
+		}
+	}
+
+	// Every word used: choices still come, repeating old words
+	for _, w := range []string{"dog", "fish", "camel"} {
+		used[w] = true
+	}
+	if got := GenerateWordChoicesMix(DefaultLanguage, internal.WordMixBalanced.Difficulties(), used); len(got) != 3 {
+		t.Errorf("expected 3 choices from an exhausted pool, got %+v", got)
+	}
+}
+
+func TestGenerateWordChoicesCarryMetadata(t *testing.T) {
+	choices := GenerateWordChoices()
+	if len(choices) != 3 {
+		t.Fatalf("expected 3 choices, got %d", len(choices))
+	}
+
+	seen := make(map[string]bool)
+	for _, choice := range choices {
+		if choice.Word == "" {
+			t.Errorf("choice has empty word: %+v", choice)
+		}
+		if seen[choice.Word] {
+			t.Errorf("duplicate choice %q", choice.Word)
+		}
+		seen[choice.Word] = true
+
+		if choice.Difficult == "" {
+			t.Errorf("choice %q has no difficulty", choice.Word)
+		}
+		if choice.Points != choice.Difficult.BasePoints() || choice.Points == 0 {
+			t.Errorf("choice %q has points %d; want %d for %s",
+				choice.Word, choice.Points, choice.Difficult.BasePoints(), choice.Difficult)
+		}
+	}
+}
+
+func TestGenerateIdenticonDeterministic(t *testing.T) {
+	first, err := GenerateIdenticon("alice", 64)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	second, err := GenerateIdenticon("alice", 64)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(first, second) {
+		t.Error("expected the same seed to yield identical bytes")
+	}
+
+	other, err := GenerateIdenticon("bob", 64)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bytes.Equal(first, other) {
+		t.Error("expected different seeds to yield different images")
+	}
+}
+
+func TestGenerateIdenticonClampsSize(t *testing.T) {
+	data, err := GenerateIdenticon("alice", 10000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("expected a valid PNG: %v", err)
+	}
+	if got := img.Bounds().Dx(); got != IdenticonMaxSize {
+		t.Errorf("expected size clamped to %d, got %d", IdenticonMaxSize, got)
+	}
+}
+
+func TestUpdatePlayerOrderDedupesPlayers(t *testing.T) {
+	alice := &internal.Player{Id: "alice", IsConnected: true}
+	bob := &internal.Player{Id: "bob", IsConnected: true}
+	room := &internal.Room{
+		Id: "dedupe",
+		Players: map[string]*internal.Player{
+			"alice":       alice,
+			"bob":         bob,
+			"alice-stale": alice, // same player left under a second key
+		},
+	}
+
+	UpdatePlayerOrder(room)
+
+	if len(room.PlayerOrder) != 2 {
+		t.Fatalf("expected 2 unique players in order, got %v", room.PlayerOrder)
+	}
+	seen := map[string]bool{}
+	for _, id := range room.PlayerOrder {
+		if seen[id] {
+			t.Errorf("player %s appears twice in %v", id, room.PlayerOrder)
+		}
+		seen[id] = true
+	}
+}
+
+func TestGetMaskedWord(t *testing.T) {
+	cases := []struct {
+		word, want string
+	}{
+		{"", ""},
+		{"cat", "_ _ _"},
+		{"ice cream", "_ _ _   _ _ _ _ _"},
+		{"café", "_ _ _ _"},
+		{"jalapeño", "_ _ _ _ _ _ _ _"},
+	}
+	for _, c := range cases {
+		if got := GetMaskedWord(c.word); got != c.want {
+			t.Errorf("GetMaskedWord(%q) = %q, want %q", c.word, got, c.want)
+		}
+	}
+}
+
+func TestNormalizeGuess(t *testing.T) {
+	cases := []struct {
+		in    string
+		strip bool
+		want  string
+	}{
+		{"  Eiffel Tower ", false, "eiffel tower"},
+		{"The Eiffel Tower", false, "the eiffel tower"},
+		{"The Eiffel Tower", true, "eiffel tower"},
+		{"an  apple", true, "apple"},
+		{"a", true, "a"},                 // a lone article is the whole word
+		{"theatre", true, "theatre"},     // only whole-word articles
+		{"the the end", true, "the end"}, // only one article is dropped
+	}
+	for _, c := range cases {
+		if got := NormalizeGuess(c.in, c.strip); got != c.want {
+			t.Errorf("NormalizeGuess(%q, %v) = %q, want %q", c.in, c.strip, got, c.want)
+		}
+	}
+}
+
+func TestUpdatePlayerOrderKeepsRotationWhenDrawerLeaves(t *testing.T) {
+	players := map[string]*internal.Player{}
+	for _, id := range []string{"a", "b", "c", "d"} {
+		players[id] = &internal.Player{Id: id, IsConnected: true}
+	}
+	room := &internal.Room{
+		Id:           "rotation",
+		Players:      players,
+		PlayerOrder:  []string{"c", "a", "d", "b"},
+		Current:      players["a"],
+		CurrentIndex: 1,
+	}
+
+	// Drawer "a" leaves; the turn should pass to "d", who followed them
+	delete(room.Players, "a")
+	UpdatePlayerOrder(room)
+
+	if want := []string{"c", "d", "b"}; !slices.Equal(room.PlayerOrder, want) {
+		t.Fatalf("expected rotation %v preserved, got %v", want, room.PlayerOrder)
+	}
+	next := room.PlayerOrder[(room.CurrentIndex+1)%len(room.PlayerOrder)]
+	if next != "d" {
+		t.Errorf("expected next drawer d, got %s", next)
+	}
+
+	// Newcomers join at the end of the rotation
+	room.Players["e"] = &internal.Player{Id: "e", IsConnected: true}
+	UpdatePlayerOrder(room)
+	if want := []string{"c", "d", "b", "e"}; !slices.Equal(room.PlayerOrder, want) {
+		t.Errorf("expected newcomer appended, got %v", room.PlayerOrder)
+	}
+}
+
+// pickShares draws from pool many times and returns each word's share
+func pickShares(t *testing.T, weighting WordWeighting, pool []Word) map[string]float64 {
+	t.Helper()
+	prev := WordChoiceWeighting
+	WordChoiceWeighting = weighting
+	t.Cleanup(func() { WordChoiceWeighting = prev })
+
+	const draws = 20000
+	counts := map[string]int{}
+	for range draws {
+		counts[pickWord(pool).Text]++
+	}
+	shares := map[string]float64{}
+	for text, n := range counts {
+		shares[text] = float64(n) / draws
+	}
+	return shares
+}
+
+func TestPickWordWeighting(t *testing.T) {
+	pool := []Word{{Text: "rare", Count: 1}, {Text: "common", Count: 3}}
+
+	cases := []struct {
+		weighting  WordWeighting
+		wantCommon float64
+	}{
+		{WeightUniform, 0.5},
+		{WeightByCount, 0.75},
+		{WeightByInverseCount, 0.25},
+	}
+	for _, tc := range cases {
+		got := pickShares(t, tc.weighting, pool)["common"]
+		if got < tc.wantCommon-0.03 || got > tc.wantCommon+0.03 {
+			t.Errorf("weighting %d: expected common share near %.2f, got %.3f", tc.weighting, tc.wantCommon, got)
+		}
+	}
+}
+
+func TestPickWordClampsWeights(t *testing.T) {
+	prev := MaxWordWeight
+	MaxWordWeight = 4
+	t.Cleanup(func() { MaxWordWeight = prev })
+	pool := []Word{{Text: "zero", Count: 0}, {Text: "huge", Count: 1000}}
+
+	// zero counts as 1 and huge as 4, so huge gets 4/5 of the draws
+	got := pickShares(t, WeightByCount, pool)["huge"]
+	if got < 0.77 || got > 0.83 {
+		t.Errorf("expected clamped share near 0.80, got %.3f", got)
+	}
+}
+
+func TestGenerateIDLengthAndCharset(t *testing.T) {
+	for _, n := range []int{0, 1, 8, 32} {
+		id := GenerateID(n)
+		if len(id) != n {
+			t.Errorf("GenerateID(%d) returned %d characters", n, len(id))
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(IDAlphabet, c) {
+				t.Errorf("GenerateID(%d) returned %q with non URL-safe character %q", n, id, c)
+			}
+		}
+	}
+	if id := GenerateID(-1); id != "" {
+		t.Errorf("expected empty id for negative length, got %q", id)
+	}
+}
+
+func TestGenerateIDNoCollisions(t *testing.T) {
+	seen := make(map[string]bool, 100000)
+	for range 100000 {
+		id := GenerateID(8)
+		if seen[id] {
+			t.Fatalf("collision on %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestGetPlayerStatsWithoutGuesses(t *testing.T) {
+	player := &internal.Player{Id: "alice", Username: "alice"}
+
+	stats := GetPlayerStats(player)
+	if stats["accuracy"] != 0.0 || stats["avg_guess_time_ms"] != int64(0) || stats["times_drawn"] != 0 {
+		t.Errorf("expected zeroed stats for a player with no guesses, got %v", stats)
+	}
+}
+
+func TestGetPlayerStatsMixedGuesses(t *testing.T) {
+	room := &internal.Room{Id: "stats"}
+	player := &internal.Player{Id: "alice", Username: "alice", Room: room, TotalGuesses: 4, CorrectGuesses: 2}
+	room.RoundStats = []internal.RoundStats{
+		{DrawerId: "bob", CorrectGuessers: []internal.PlayerGuess{{PlayerID: "alice", GuessTime: 10000}}},
+		{DrawerId: "alice"},
+		{DrawerId: "bob", CorrectGuessers: []internal.PlayerGuess{
+			{PlayerID: "carol", GuessTime: 1000},
+			{PlayerID: "alice", GuessTime: 20000},
+		}},
+	}
+
+	stats := GetPlayerStats(player)
+	if stats["accuracy"] != 0.5 {
+		t.Errorf("expected accuracy 0.5, got %v", stats["accuracy"])
+	}
+	if stats["avg_guess_time_ms"] != int64(15000) {
+		t.Errorf("expected an average of 15000ms over alice's own guesses, got %v", stats["avg_guess_time_ms"])
+	}
+	if stats["times_drawn"] != 1 {
+		t.Errorf("expected 1 turn drawn, got %v", stats["times_drawn"])
+	}
+}
+
+func TestLogGameEventWritesJSONLines(t *testing.T) {
+	var buf bytes.Buffer
+	SetGameEventSink(&buf)
+	t.Cleanup(func() { SetGameEventSink(os.Stdout) })
+
+	room := &internal.Room{Id: "events"}
+	LogGameEvent(room, "round_end", map[string]any{"word": "castle"})
+	LogGameEvent(room, string(internal.PixelPlace), map[string]any{"x": 1})
+	for _, noisy := range []string{"game_state_update", "canvas_state", "canvas_cleared"} {
+		LogGameEvent(room, noisy, map[string]any{})
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("expected only the info-level event to be written, got %q", buf.String())
+	}
+	var event struct {
+		Timestamp int64          `json:"timestamp"`
+		RoomID    string         `json:"room_id"`
+		Event     string         `json:"event"`
+		Data      map[string]any `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(lines[0]), &event); err != nil {
+		t.Fatalf("expected a JSON line, got %q: %v", lines[0], err)
+	}
+	if event.RoomID != "events" || event.Event != "round_end" || event.Timestamp == 0 || event.Data["word"] != "castle" {
+		t.Errorf("unexpected event %+v", event)
+	}
+
+	prev := GameEventMinLevel
+	GameEventMinLevel = EventLevelDebug
+	t.Cleanup(func() { GameEventMinLevel = prev })
+	buf.Reset()
+	LogGameEvent(room, string(internal.PixelPlace), map[string]any{"x": 1})
+	if !strings.Contains(buf.String(), `"event":"place"`) {
+		t.Errorf("expected pixel events once debug level is enabled, got %q", buf.String())
+	}
+}
@@ -1,13 +1,82 @@
 package utils
 
-// Word represents a word with its character count
+import (
+	"strings"
+
+	"github.com/scythe504/skribblr-backend/internal"
+)
+
+// Word represents a word with its character count, optional emoji hint and
+// difficulty (empty means the difficulty of the pool it sits in)
 type Word struct {
-	Text  string
-	Count int
+	Text       string
+	Count      int
+	Emoji      string
+	Difficulty internal.WordDifficulty
+}
+
+// DefaultLanguage is the language of the built-in word lists below, used by
+// rooms that don't pick one or pick one with no loaded word list
+const DefaultLanguage = "en"
+
+// wordPools holds one language's words by difficulty
+type wordPools struct {
+	easy, medium, hard []Word
+}
+
+// of returns the pool of words of difficulty d
+func (p wordPools) of(d internal.WordDifficulty) []Word {
+	switch d {
+	case internal.DifficultyEasy:
+		return p.easy
+	case internal.DifficultyHard:
+		return p.hard
+	}
+	return p.medium
+}
+
+// Word lists loaded for languages other than DefaultLanguage, by language code.
+// Filled by LoadLanguageWords before serving games and only read afterwards.
+var languagePools = map[string]wordPools{}
+
+// NormalizeLanguage lowercases and trims a language code ("ES " -> "es")
+func NormalizeLanguage(lang string) string {
+	return strings.ToLower(strings.TrimSpace(lang))
+}
+
+// HasLanguage reports whether rooms in lang get that language's own words
+func HasLanguage(lang string) bool {
+	lang = NormalizeLanguage(lang)
+	_, ok := languagePools[lang]
+	return ok || lang == DefaultLanguage
+}
+
+// poolsFor returns the word pools for lang, falling back to DefaultLanguage
+// when lang is empty or has no loaded word list
+func poolsFor(lang string) wordPools {
+	if pools, ok := languagePools[NormalizeLanguage(lang)]; ok {
+		return pools
+	}
+	return wordPools{easy: easyWords, medium: mediumWords, hard: hardWords}
+}
+
+// builtinWord is a built-in word list entry; difficulty comes from its list
+type builtinWord struct {
+	text  string
+	count int
+}
+
+// builtinWords converts a built-in list to pool words
+func builtinWords(list []builtinWord) []Word {
+	words := make([]Word, len(list))
+	for i, w := range list {
+		words[i] = Word{Text: w.text, Count: w.count}
+	}
+	return words
 }
 
 // Word lists by difficulty based on character count
-var easyWords = []Word{
+var easyWords = builtinWords([]builtinWord{
 	{"ace", 3}, {"ant", 3}, {"arm", 3}, {"ash", 3}, {"axe", 3}, {"bad", 3}, {"bag", 3}, {"bar", 3}, 
 	{"bat", 3}, {"bed", 3}, {"bee", 3}, {"BMW", 3}, {"BMX", 3}, {"bow", 3}, {"box", 3}, {"boy", 3}, 
 	{"bus", 3}, {"can", 3}, {"cap", 3}, {"cat", 3}, {"cow", 3}, {"cup", 3}, {"cut", 3}, {"day", 3}, 
@@ -22,9 +91,9 @@ var easyWords = []Word{
 	{"sea", 3}, {"sew", 3}, {"shy", 3}, {"sit", 3}, {"ski", 3}, {"sky", 3}, {"son", 3}, {"spy", 3}, 
 	{"sun", 3}, {"tea", 3}, {"tie", 3}, {"tip", 3}, {"toe", 3}, {"toy", 3}, {"tug", 3}, {"UFO", 3}, 
 	{"USB", 3}, {"wax", 3}, {"web", 3}, {"wig", 3}, {"zoo", 3},
-}
+})
 
-var mediumWords = []Word{
+var mediumWords = builtinWords([]builtinWord{
 	{"ABBA", 4}, {"acid", 4}, {"acne", 4}, {"afro", 4}, {"arch", 4}, {"Asia", 4}, {"atom", 4}, {"Audi", 4}, 
 	{"baby", 4}, {"bait", 4}, {"bald", 4}, {"ball", 4}, {"bank", 4}, {"bark", 4}, {"barn", 4}, {"base", 4}, 
 	{"beak", 4}, {"bean", 4}, {"bear", 4}, {"beef", 4}, {"beer", 4}, {"beet", 4}, {"bell", 4}, {"belt", 4}, 
@@ -68,9 +137,9 @@ var mediumWords = []Word{
 	{"weak", 4}, {"well", 4}, {"west", 4}, {"wife", 4}, {"wind", 4}, {"wine", 4}, {"wing", 4}, {"wire", 4}, 
 	{"wolf", 4}, {"wool", 4}, {"work", 4}, {"worm", 4}, {"Xbox", 4}, {"yawn", 4}, {"yeti", 4}, {"Yoda", 4}, 
 	{"yolk", 4}, {"Zeus", 4}, {"zoom", 4}, {"Zuma", 4},
-}
+})
 
-var hardWords = []Word{
+var hardWords = builtinWords([]builtinWord{
 	{"abyss", 5}, {"AC/DC", 5}, {"acorn", 5}, {"actor", 5}, {"adult", 5}, {"alarm", 5}, {"alien", 5}, 
 	{"alley", 5}, {"angel", 5}, {"angry", 5}, {"anime", 5}, {"anvil", 5}, {"Apple", 5}, {"armor", 5}, 
 	{"arrow", 5}, {"attic", 5}, {"bacon", 5}, {"bagel", 5}, {"Bambi", 5}, {"banjo", 5}, {"beach", 5}, 
@@ -123,4 +192,4 @@ var hardWords = []Word{
 	{"watch", 5}, {"water", 5}, {"whale", 5}, {"wheel", 5}, {"whisk", 5}, {"white", 5}, {"witch", 5}, 
 	{"W-LAN", 5}, {"world", 5}, {"wound", 5}, {"wrist", 5}, {"Xerox", 5}, {"x-ray", 5}, {"yacht", 5}, 
 	{"Yoshi", 5}, {"young", 5}, {"yo-yo", 5}, {"zebra", 5}, {"Zelda", 5}, {"Zorro", 5},
-}
\ No newline at end of file
+})
\ No newline at end of file